@@ -7,6 +7,7 @@ import (
 
 	"github.com/pressly/goose/v3"
 	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/regions"
 )
 
 // App struct
@@ -37,4 +38,9 @@ func (a *App) startup(ctx context.Context, db *sql.DB) {
 	if err != nil {
 		log.Fatalf("couldn't migrate DB: %v\n", err)
 	}
+
+	// Court sources added by the user. Invalid ones are skipped
+	if _, err := regions.LoadUserSources(); err != nil {
+		log.Printf("couldn't load court sources: %v\n", err)
+	}
 }
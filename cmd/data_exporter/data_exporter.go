@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_db "github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/export"
+)
+
+func main() {
+	var (
+		filePath        string
+		format          string
+		queryStr        string
+		tags            string
+		includeAccords  bool
+		includeArchived bool
+	)
+	flag.StringVar(
+		&filePath,
+		"f",
+		"",
+		"Path of the file to write. Defaults to a timestamped file in the current directory",
+	)
+	flag.StringVar(
+		&format,
+		"format",
+		"",
+		"Export format: csv, xlsx or json. Defaults to the extension of -f, or json",
+	)
+	flag.StringVar(&queryStr, "q", "", `Case query selecting the cases to export, e.g. "type:fam2 year:2024"`)
+	flag.StringVar(&tags, "tags", "", "Comma separated tags the exported cases must have")
+	flag.BoolVar(&includeAccords, "accords", false, "Include the full accord history of every case")
+	flag.BoolVar(&includeArchived, "archived", false, "Include archived cases")
+	flag.Parse()
+
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(filePath), ".")
+	}
+	if format == "" {
+		format = string(export.FormatJSON)
+	}
+	f, err := export.ParseFormat(format)
+	if err != nil {
+		log.Fatalf("Invalid format: %v", err)
+	}
+	if filePath == "" {
+		filePath = export.FileName(f, time.Now())
+	}
+
+	db, err := _db.Connect()
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	defer db.Close()
+	log.Println("Connected to database")
+
+	findOpts := _db.DefaultFindCaseOptions
+	findOpts.Query = queryStr
+	findOpts.IncludeArchived = includeArchived
+	if tags != "" {
+		findOpts.Tags = strings.Split(tags, ",")
+	}
+
+	cases, err := export.Load(context.Background(), db, &findOpts, includeAccords)
+	if err != nil {
+		log.Fatalf("Error loading cases: %v", err)
+	}
+
+	out, err := os.Create(filePath)
+	if err != nil {
+		log.Fatalf("Error creating file: %v", err)
+	}
+	defer out.Close()
+
+	err = export.Write(out, cases, &export.Options{Format: f, IncludeAccords: includeAccords})
+	if err != nil {
+		log.Fatalf("Error writing export: %v", err)
+	}
+
+	log.Printf("Exported %d cases to %s", len(cases), filePath)
+}
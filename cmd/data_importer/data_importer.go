@@ -1,74 +1,138 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"io"
 	"log"
 	"os"
 
 	_db "github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/importer"
 )
 
-type importedCase struct {
-	CaseId   string `json:"case_id"`
-	CaseType string `json:"case_type"`
+// Report written with -report, the import report along with its source
+type fileReport struct {
+	File   string `json:"file"`
+	Format string `json:"format"`
+	*importer.Report
 }
 
 func main() {
-	db, err := _db.Connect()
-	if err != nil {
-		log.Fatalf("Error connecting to database: %v", err)
-	}
-	defer db.Close()
-	log.Println("Connected to database")
-
 	var (
-		filePath string
+		filePath   string
+		format     string
+		mappingStr string
+		sheet      string
+		reportPath string
+		dryRun     bool
+		upsert     bool
 	)
 	flag.StringVar(
 		&filePath,
 		"f",
 		"./data.json",
-		"Path to the JSON file containing the data to import",
+		"Path to the JSON, CSV or XLSX file containing the data to import",
 	)
+	flag.StringVar(&format, "format", "", "Format of the file: json, csv or xlsx. Defaults to the extension of -f")
+	flag.StringVar(
+		&mappingStr,
+		"map",
+		"",
+		`Columns holding each field in CSV/XLSX files, e.g. "case_id=Expediente,case_type=Juzgado,alias=Cliente"`,
+	)
+	flag.StringVar(&sheet, "sheet", "", "Sheet to read from XLSX files. Defaults to the first one")
+	flag.StringVar(&reportPath, "report", "", `Path where a JSON report of the import is written, "-" for stdout`)
+	flag.BoolVar(&dryRun, "dry-run", false, "Validate the file and report what would be imported without writing anything")
+	flag.BoolVar(&upsert, "upsert", false, "Update the alias and tags of cases that already exist")
 	flag.Parse()
 
-	data, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
+	format, err := importer.ResolveFormat(filePath, format)
+	if err != nil {
+		log.Fatalf("Error reading file: %v", err)
+	}
+	mapping, err := importer.ParseMapping(mappingStr)
 	if err != nil {
-		log.Fatalf("Error opening file: %v", err)
+		log.Fatalf("Invalid column mapping: %v", err)
 	}
-	defer data.Close()
 
-	buf := new(bytes.Buffer)
-	_, err = buf.ReadFrom(data)
+	importedCases, err := importer.Read(&importer.Source{
+		Path:    filePath,
+		Format:  format,
+		Mapping: mapping,
+		Sheet:   sheet,
+	})
 	if err != nil {
 		log.Fatalf("Error reading file: %v", err)
 	}
 
-	var importedCases []importedCase
-	err = json.Unmarshal(buf.Bytes(), &importedCases)
+	db, err := _db.Connect()
 	if err != nil {
-		log.Fatalf("Error unmarshalling JSON: %v", err)
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	defer db.Close()
+	log.Println("Connected to database")
+
+	report, err := importer.Import(
+		context.Background(),
+		db,
+		importedCases,
+		&importer.Options{DryRun: dryRun, Upsert: upsert},
+	)
+	if err != nil && !errors.Is(err, importer.ErrImportRolledBack) {
+		log.Fatalf("Error importing cases: %v", err)
 	}
 
-	successful := 0
-	for _, c := range importedCases {
-		log.Printf("Importing case %s:%s", c.CaseId, c.CaseType)
+	for _, res := range report.Rows {
+		if len(res.Errors) > 0 {
+			log.Printf("Line %d (%s:%s) %s: %v", res.Line, res.CaseId, res.CaseType, res.Status, res.Errors)
+		}
+	}
 
-		newCase, err := _db.NewCase(c.CaseId, c.CaseType)
+	prefix := ""
+	if dryRun {
+		prefix = "[dry run] "
+	}
+	log.Printf(
+		"%s%d cases read: %d created, %d updated, %d skipped, %d invalid, %d failed",
+		prefix,
+		report.Total,
+		report.Created,
+		report.Updated,
+		report.Skipped,
+		report.Invalid,
+		report.Failed,
+	)
+	if report.RolledBack {
+		log.Println(importer.ErrImportRolledBack)
+	}
+
+	if reportPath != "" {
+		err := writeReport(reportPath, &fileReport{File: filePath, Format: format, Report: report})
 		if err != nil {
-			log.Printf("Invalid case: %v", err)
-			continue
+			log.Fatalf("Error writing report: %v", err)
 		}
+	}
+	if report.RolledBack {
+		os.Exit(1)
+	}
+}
 
-		err = _db.InsertCase(context.Background(), db, newCase)
+func writeReport(path string, report *fileReport) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
 		if err != nil {
-			log.Printf("Error inserting case: %s:%s\n  %v", newCase.CaseId, newCase.CaseType, err)
-			continue
+			return err
 		}
-		successful++
+		defer f.Close()
+		w = f
 	}
-	log.Printf("Successfully imported %d/%d cases", successful, len(importedCases))
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(report)
 }
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal/accupdter"
+	_db "github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/fetchers"
+)
+
+var ErrMissingBackfillCases = errors.New("pass the cases to backfill, or select them with -q or -tags")
+
+func runBackfill(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		since    string
+		until    string
+		queryStr string
+		tags     string
+		resumeId string
+		list     bool
+		force    bool
+		noCache  bool
+		interval time.Duration
+		asJSON   bool
+	)
+	fs := newFlagSet("backfill", "[case...]")
+	fs.StringVar(&since, "since", "", "Oldest date searched, as YYYY-MM-DD")
+	fs.StringVar(&until, "until", "", "Newest date searched, as YYYY-MM-DD. Defaults to today")
+	fs.StringVar(&queryStr, "q", "", `Case query selecting the cases to backfill, e.g. "year:2024"`)
+	fs.StringVar(&tags, "tags", "", "Comma separated tags the backfilled cases must have")
+	fs.StringVar(&resumeId, "resume", "", "Resume the paused or failed job with this id")
+	fs.BoolVar(&list, "list", false, "List the backfill jobs")
+	fs.BoolVar(&force, "force", false, "With -resume, also resume a job marked as running, e.g. after a crash")
+	fs.BoolVar(&noCache, "no-cache", false, "Fetch every document again instead of using the cached ones")
+	fs.DurationVar(&interval, "interval", fetchers.DefaultFetchInterval, "Minimum time between the requests made to a court")
+	fs.BoolVar(&asJSON, "json", false, "Print the jobs as JSON")
+	fs.Parse(args)
+
+	if list {
+		jobs, err := _db.FindBackfillJobs(ctx, appDb)
+		if err != nil {
+			return err
+		}
+		return printBackfillJobs(jobs, asJSON)
+	}
+
+	var job *_db.BackfillJob
+	var err error
+	if resumeId != "" {
+		job, err = _db.FindBackfillJobById(ctx, appDb, resumeId)
+		if err != nil {
+			return err
+		}
+		if force && job.Status == _db.BackfillRunning {
+			if err := _db.SetBackfillStatus(ctx, appDb, job, _db.BackfillPaused, ""); err != nil {
+				return err
+			}
+		}
+	} else {
+		job, err = newBackfillJob(ctx, appDb, fs.Args(), since, until, queryStr, tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	opts := &accupdter.BackfillOpts{
+		Interval: interval,
+		Progress: func(j *_db.BackfillJob) {
+			fmt.Fprintf(os.Stderr, "\r%d/%d days searched, %d accords found", j.SearchedDays, j.TotalDays, j.AccordCount)
+		},
+	}
+	if !noCache {
+		if opts.Cache, err = fetchers.DefaultDocCache(); err != nil {
+			log.Printf("Documents won't be cached: %v", err)
+		}
+	}
+
+	// Interrupting the command pauses the job
+	runCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	updater := accupdter.NewGeneralUpdater(&accupdter.GenUpdterConf{})
+	job, err = updater.RunBackfill(runCtx, appDb, job.Id, opts)
+	fmt.Fprintln(os.Stderr)
+	if errors.Is(err, context.Canceled) {
+		fmt.Printf("Paused, resume it with 'lexctl backfill -resume %s'\n", job.Id)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return printBackfillJobs([]*_db.BackfillJob{job}, asJSON)
+}
+
+func newBackfillJob(ctx context.Context, appDb *sql.DB, caseArgs []string, since, until, queryStr, tags string) (*_db.BackfillJob, error) {
+	if since == "" {
+		return nil, errors.New("-since is required to start a backfill")
+	}
+	sinceDate, err := time.ParseInLocation(dateLayout, since, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -since date %q: %w", since, err)
+	}
+	untilDate := time.Now()
+	if until != "" {
+		if untilDate, err = time.ParseInLocation(dateLayout, until, time.Local); err != nil {
+			return nil, fmt.Errorf("invalid -until date %q: %w", until, err)
+		}
+	}
+
+	caseIds := []string{}
+	for _, arg := range caseArgs {
+		c, err := findCaseArg(ctx, appDb, arg)
+		if err != nil {
+			return nil, err
+		}
+		caseIds = append(caseIds, c.Id)
+	}
+	if queryStr != "" || tags != "" {
+		opts := _db.DefaultFindCaseOptions
+		opts.Query = queryStr
+		opts.Tags = splitList(tags)
+		opts.Limit = 0
+		cases, err := _db.FindFilteredCases(ctx, appDb, &opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cases {
+			caseIds = append(caseIds, c.Id)
+		}
+	}
+	if len(caseIds) == 0 {
+		return nil, ErrMissingBackfillCases
+	}
+
+	job, err := _db.NewBackfillJob(caseIds, sinceDate, untilDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := _db.InsertBackfillJob(ctx, appDb, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func printBackfillJobs(jobs []*_db.BackfillJob, asJSON bool) error {
+	if asJSON {
+		return printJSON(jobs)
+	}
+
+	rows := make([][]string, len(jobs))
+	for i, j := range jobs {
+		failed := 0
+		for _, cp := range j.Checkpoints {
+			failed += cp.FailedDays
+		}
+		rows[i] = []string{
+			j.Id,
+			string(j.Status),
+			formatTime(j.Since) + " - " + formatTime(j.Until),
+			fmt.Sprintf("%d/%d", j.SearchedDays, j.TotalDays),
+			strconv.Itoa(failed),
+			strconv.Itoa(j.AccordCount),
+			j.Error,
+		}
+	}
+
+	return printTable([]string{"ID", "STATUS", "RANGE", "DAYS", "FAILED", "ACCORDS", "ERROR"}, rows)
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	_db "github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/readers"
+	"github.com/vladwithcode/lex_app/internal/regions"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
+)
+
+var ErrMissingCase = errors.New("missing the case, pass its id or its key as '12/2024:fam2' or 'MX_DGO_DGO:12/2024:fam2'")
+
+func runFind(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		queryStr        string
+		search          string
+		tags            string
+		limit           int
+		includeArchived bool
+		asJSON          bool
+	)
+	fs := newFlagSet("find", "")
+	fs.StringVar(&queryStr, "q", "", `Case query, e.g. "type:fam2 year:2024"`)
+	fs.StringVar(&search, "search", "", "Free text searched in the case id, alias and other ids")
+	fs.StringVar(&tags, "tags", "", "Comma separated tags the cases must have")
+	fs.IntVar(&limit, "limit", 0, "Maximum number of cases listed, 0 lists all of them")
+	fs.BoolVar(&includeArchived, "archived", false, "Include archived cases")
+	fs.BoolVar(&asJSON, "json", false, "Print the cases as JSON")
+	fs.Parse(args)
+
+	opts := _db.DefaultFindCaseOptions
+	opts.Query = queryStr
+	opts.Search = search
+	opts.Tags = splitList(tags)
+	opts.Limit = limit
+	opts.IncludeArchived = includeArchived
+
+	cases, err := _db.FindFilteredCases(ctx, appDb, &opts)
+	if err != nil {
+		return err
+	}
+
+	return printCases(cases, asJSON)
+}
+
+func runShow(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		accordCount int
+		asJSON      bool
+	)
+	fs := newFlagSet("show", "<case>")
+	fs.IntVar(&accordCount, "accords", 5, "Number of accords shown, newest first")
+	fs.BoolVar(&asJSON, "json", false, "Print the case as JSON")
+	fs.Parse(args)
+
+	// The accords are joined to the case, without any the case isn't read
+	if accordCount < 1 {
+		return errors.New("-accords should be at least 1")
+	}
+
+	found, err := findCaseArg(ctx, appDb, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c, err := _db.FindCaseWithAccords(ctx, appDb, found.Id, accordCount)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return printJSON(c)
+	}
+
+	status := "active"
+	if c.ArchivedAt != nil {
+		status = "archived"
+	}
+	if c.DeletedAt != nil {
+		status = "in trash"
+	}
+
+	court := "-"
+	if ct, err := regions.FindCourt(internal.Region(c.Region), internal.CaseType(c.CaseType)); err == nil {
+		court = ct.Name
+	}
+
+	err = printTable([]string{"FIELD", "VALUE"}, [][]string{
+		{"ID", c.Id},
+		{"Case", c.CaseId},
+		{"Type", c.CaseType},
+		{"Region", c.Region},
+		{"Court", court},
+		{"Alias", c.Alias},
+		{"Nature", c.Nature},
+		{"Other ids", strings.Join(c.OtherIds, ",")},
+		{"Tags", tagNames(c.Tags)},
+		{"Status", status},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	rows := make([][]string, len(c.Accords))
+	for i, a := range c.Accords {
+		rows[i] = []string{formatTime(a.Date), a.Content}
+	}
+
+	return printTable([]string{"DATE", "ACCORD"}, rows)
+}
+
+func runAdd(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		alias  string
+		region string
+		asJSON bool
+	)
+	fs := newFlagSet("add", "<case id> <case type>")
+	fs.StringVar(&alias, "alias", "", "Alias of the case")
+	fs.StringVar(&region, "region", string(internal.RegionDefault), "Region of the court, see 'lexctl regions'")
+	fs.BoolVar(&asJSON, "json", false, "Print the new case as JSON")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("add takes the case id and the case type, e.g. 'lexctl add 12/2024 fam2'")
+	}
+
+	newCase, err := _db.NewRegionCase(fs.Arg(0), strings.ToLower(fs.Arg(1)), region)
+	if err != nil {
+		return err
+	}
+	newCase.Alias = alias
+	if err := _db.InsertCase(ctx, appDb, newCase); err != nil {
+		return err
+	}
+	emit(ctx, appDb, _db.WebhookEventCaseCreated, newCase)
+
+	return printCases([]*_db.LexCase{newCase}, asJSON)
+}
+
+func runArchive(ctx context.Context, appDb *sql.DB, args []string) error {
+	var undo bool
+	fs := newFlagSet("archive", "<case>")
+	fs.BoolVar(&undo, "undo", false, "Unarchive the case instead")
+	fs.Parse(args)
+
+	c, err := findCaseArg(ctx, appDb, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if undo {
+		err = _db.UnarchiveCaseById(ctx, appDb, c.Id)
+	} else {
+		err = _db.ArchiveCaseById(ctx, appDb, c.Id)
+	}
+	if err != nil {
+		return err
+	}
+	if !undo {
+		now := time.Now()
+		c.ArchivedAt = &now
+		emit(ctx, appDb, _db.WebhookEventCaseArchived, c)
+	}
+
+	action := "Archived"
+	if undo {
+		action = "Unarchived"
+	}
+	fmt.Printf("%s %s (%s)\n", action, c.CaseId, c.CaseType)
+
+	return nil
+}
+
+// Finds the case by its key when arg has the form '12/2024:fam2', or
+// 'MX_DGO_DGO:12/2024:fam2' to pick the region, otherwise by its id
+func findCaseArg(ctx context.Context, appDb *sql.DB, arg string) (*_db.LexCase, error) {
+	if arg == "" {
+		return nil, ErrMissingCase
+	}
+
+	var (
+		c   *_db.LexCase
+		err error
+	)
+	parts := strings.Split(arg, readers.CaseKeySeparator)
+	switch len(parts) {
+	case 3:
+		c, err = _db.FindRegionCase(ctx, appDb, strings.ToUpper(parts[0]), parts[1]+readers.CaseKeySeparator+strings.ToLower(parts[2]))
+	case 2:
+		c, err = _db.FindCase(ctx, appDb, parts[0]+readers.CaseKeySeparator+strings.ToLower(parts[1]))
+	default:
+		c, err = _db.FindCaseById(ctx, appDb, arg)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s:\n\t%w", arg, _db.ErrCaseNotFound)
+	}
+
+	return c, err
+}
+
+// Notifies the webhooks subscribed to the event, see flushWebhooks
+func emit(ctx context.Context, appDb *sql.DB, event string, data any) {
+	if err := webhooks.Emit(ctx, appDb, event, data); err != nil {
+		log.Printf("Error notifying webhooks: %v", err)
+	}
+}
+
+func runCheckTypes(ctx context.Context, appDb *sql.DB, args []string) error {
+	var asJSON bool
+	fs := newFlagSet("check-types", "")
+	fs.BoolVar(&asJSON, "json", false, "Print the cases as JSON")
+	fs.Parse(args)
+
+	invalid, err := _db.FindCasesWithInvalidType(ctx, appDb)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		if err := printJSON(invalid); err != nil {
+			return err
+		}
+	} else if len(invalid) > 0 {
+		rows := make([][]string, len(invalid))
+		for i, c := range invalid {
+			rows[i] = []string{c.Id, c.CaseId, c.CaseType, c.Region, c.Problem}
+		}
+		if err := printTable([]string{"ID", "CASE", "TYPE", "REGION", "PROBLEM"}, rows); err != nil {
+			return err
+		}
+	}
+
+	// Exits with an error so it can be used from scripts
+	if len(invalid) > 0 {
+		return fmt.Errorf("%d cases have a case type their region doesn't publish", len(invalid))
+	}
+	if !asJSON {
+		fmt.Println("Every case has a valid case type")
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	_db "github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/export"
+)
+
+func runExport(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		filePath        string
+		format          string
+		queryStr        string
+		tags            string
+		includeAccords  bool
+		includeArchived bool
+	)
+	fs := newFlagSet("export", "")
+	fs.StringVar(&filePath, "f", "", `File to write, "-" for stdout. Defaults to a timestamped file in the current directory`)
+	fs.StringVar(&format, "format", "", "Export format: csv, xlsx or json. Defaults to the extension of -f, or json")
+	fs.StringVar(&queryStr, "q", "", `Case query selecting the cases to export, e.g. "type:fam2 year:2024"`)
+	fs.StringVar(&tags, "tags", "", "Comma separated tags the exported cases must have")
+	fs.BoolVar(&includeAccords, "accords", false, "Include the full accord history of every case")
+	fs.BoolVar(&includeArchived, "archived", false, "Include archived cases")
+	fs.Parse(args)
+
+	if format == "" && filePath != "-" {
+		format = strings.TrimPrefix(filepath.Ext(filePath), ".")
+	}
+	if format == "" {
+		format = string(export.FormatJSON)
+	}
+	f, err := export.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	if filePath == "" {
+		filePath = export.FileName(f, time.Now())
+	}
+
+	findOpts := _db.DefaultFindCaseOptions
+	findOpts.Query = queryStr
+	findOpts.Tags = splitList(tags)
+	findOpts.IncludeArchived = includeArchived
+
+	cases, err := export.Load(ctx, appDb, &findOpts, includeAccords)
+	if err != nil {
+		return err
+	}
+
+	exportOpts := &export.Options{Format: f, IncludeAccords: includeAccords}
+	if filePath == "-" {
+		return export.Write(os.Stdout, cases, exportOpts)
+	}
+
+	err = internal.WriteFileAtomic(filePath, func(w io.Writer) error {
+		return export.Write(w, cases, exportOpts)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d cases to %s\n", len(cases), filePath)
+	return nil
+}
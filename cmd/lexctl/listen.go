@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal/webhooks"
+)
+
+func runListen(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		addr   string
+		secret string
+		status int
+	)
+	fs := newFlagSet("listen", "")
+	fs.StringVar(&addr, "addr", "127.0.0.1:8788", "Address to listen on, use http://<addr>/ as the webhook url")
+	fs.StringVar(&secret, "secret", "", "Secret of the webhook, to verify the signature of the deliveries")
+	fs.IntVar(&status, "status", http.StatusNoContent, "Status to respond with, e.g. 500 to try out the retries")
+	fs.Parse(args)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		verified := "not verified, pass -secret to verify it"
+		if secret != "" {
+			verified = "valid"
+			if err := webhooks.Verify(secret, r.Header.Get(webhooks.SignatureHeader), body, 5*time.Minute); err != nil {
+				verified = err.Error()
+			}
+		}
+
+		fmt.Printf(
+			"%s %s delivery %s, signature %s\n",
+			time.Now().Format("15:04:05"),
+			r.Header.Get(webhooks.EventHeader),
+			r.Header.Get(webhooks.DeliveryHeader),
+			verified,
+		)
+		indented := &bytes.Buffer{}
+		if json.Indent(indented, body, "", "  ") == nil {
+			body = indented.Bytes()
+		}
+		fmt.Printf("%s\n\n", body)
+
+		w.WriteHeader(status)
+	})
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	fmt.Fprintf(os.Stderr, "Listening for webhook deliveries on http://%s/\n", addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
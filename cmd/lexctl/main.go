@@ -0,0 +1,120 @@
+// Command lexctl runs updates, searches and maintenance tasks over the
+// database of the app without the GUI, e.g. from cron:
+//
+//	lexctl update -days-back 3
+//	lexctl watch check
+//
+// The database must have been opened by the app at least once, so its
+// migrations are applied
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	_db "github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/regions"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
+)
+
+const webhookFlushTimeout = 15 * time.Second
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, appDb *sql.DB, args []string) error
+}
+
+// Set in init, the commands refer to it through newFlagSet
+var commands map[string]*command
+
+func init() {
+	commands = map[string]*command{
+		"update":      {"Search the bulletins for new accords of the tracked cases", runUpdate},
+		"find":        {"List the cases matching a query", runFind},
+		"show":        {"Show a case and its latest accords", runShow},
+		"add":         {"Register a new case", runAdd},
+		"archive":     {"Archive or unarchive a case", runArchive},
+		"export":      {"Export cases as CSV, XLSX or JSON", runExport},
+		"runs":        {"List the latest update runs", runRuns},
+		"regions":     {"List the court sources and check the user definitions", runRegions},
+		"check-types": {"List the cases whose type isn't registered for their region", runCheckTypes},
+		"serve":       {"Serve the case data as a JSON API over HTTP", runServe},
+		"listen":      {"Print the webhook deliveries sent to a local address", runListen},
+		"backfill":    {"Search past bulletins for the accord history of some cases", runBackfill},
+		"watch":       {"Track case numbers before registering them, see 'lexctl watch'", runWatch},
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("lexctl: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	if _, err := regions.LoadUserSources(); err != nil {
+		log.Printf("couldn't load court sources: %v", err)
+	}
+
+	db, err := _db.Connect()
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	defer db.Close()
+
+	err = cmd.run(context.Background(), db, flag.Args()[1:])
+	flushWebhooks()
+	if err != nil {
+		db.Close()
+		log.Fatal(err)
+	}
+}
+
+// Waits a moment for the webhook deliveries of the command. The ones
+// still pending are retried by the app
+func flushWebhooks() {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookFlushTimeout)
+	defer cancel()
+
+	if err := webhooks.Flush(ctx); err != nil {
+		log.Printf("Some webhook deliveries are pending, the app will retry them")
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: lexctl <command> [flags] [args]\n\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-8s %s\n", name, commands[name].usage)
+	}
+
+	fmt.Fprintf(out, "\nRun 'lexctl <command> -h' for the flags of each command\n")
+}
+
+// Flag set of a subcommand, exiting on parse errors like the default one
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: lexctl %s [flags] %s\n\n%s\n\nFlags:\n", name, args, commands[name].usage)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	_db "github.com/vladwithcode/lex_app/internal/db"
+)
+
+const dateLayout = "2006-01-02"
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// Prints the rows as tab aligned columns under the header
+func printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+func printCases(cases []*_db.LexCase, asJSON bool) error {
+	if asJSON {
+		return printJSON(cases)
+	}
+
+	rows := make([][]string, len(cases))
+	for i, c := range cases {
+		rows[i] = []string{c.Id, c.CaseId, c.CaseType, c.Alias, formatTime(c.LastUpdatedAt), tagNames(c.Tags)}
+	}
+
+	return printTable([]string{"ID", "CASE", "TYPE", "ALIAS", "LAST ACCORD", "TAGS"}, rows)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() || t.Unix() == 0 {
+		return "-"
+	}
+
+	return t.Format(dateLayout)
+}
+
+func tagNames(tags []*_db.Tag) string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Splits a comma separated flag value, dropping empty items
+func splitList(s string) []string {
+	list := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
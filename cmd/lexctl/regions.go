@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/vladwithcode/lex_app/internal/regions"
+)
+
+func runRegions(ctx context.Context, appDb *sql.DB, args []string) error {
+	var asJSON bool
+	fs := newFlagSet("regions", "")
+	fs.BoolVar(&asJSON, "json", false, "Print the regions as JSON")
+	fs.Parse(args)
+
+	// User definitions were loaded on startup, which already
+	// reported the invalid ones
+	dir, err := regions.SourcesDir()
+	if err != nil {
+		return err
+	}
+
+	all := regions.All()
+	if asJSON {
+		type regionOut struct {
+			Id        string   `json:"id"`
+			Name      string   `json:"name"`
+			CaseTypes []string `json:"caseTypes"`
+		}
+		out := make([]regionOut, len(all))
+		for i, r := range all {
+			out[i] = regionOut{string(r.Id), r.Name, make([]string, len(r.CaseTypes))}
+			for j, ct := range r.CaseTypes {
+				out[i].CaseTypes[j] = string(ct)
+			}
+		}
+		return printJSON(out)
+	}
+
+	rows := make([][]string, len(all))
+	for i, r := range all {
+		rows[i] = []string{string(r.Id), r.Name, strconv.Itoa(len(r.CaseTypes))}
+	}
+	if err := printTable([]string{"REGION", "NAME", "CASE TYPES"}, rows); err != nil {
+		return err
+	}
+	fmt.Printf("\nUser definitions are read from %s\n", dir)
+
+	return nil
+}
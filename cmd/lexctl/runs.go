@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	_db "github.com/vladwithcode/lex_app/internal/db"
+)
+
+func runRuns(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		limit  int
+		asJSON bool
+	)
+	fs := newFlagSet("runs", "")
+	fs.IntVar(&limit, "limit", 20, "Number of runs listed, newest first. 0 lists all of them")
+	fs.BoolVar(&asJSON, "json", false, "Print the runs as JSON")
+	fs.Parse(args)
+
+	runs, err := _db.FindUpdateRuns(ctx, appDb, limit)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return printJSON(runs)
+	}
+
+	rows := make([][]string, len(runs))
+	for i, r := range runs {
+		rows[i] = []string{
+			r.StartedAt.Format("2006-01-02 15:04"),
+			r.Source,
+			string(r.Status),
+			strconv.Itoa(r.CaseCount),
+			strconv.Itoa(r.NotFoundCount),
+			r.FinishedAt.Sub(r.StartedAt).String(),
+			r.Error,
+		}
+	}
+
+	return printTable([]string{"STARTED", "SOURCE", "STATUS", "CASES", "NOT FOUND", "DURATION", "ERROR"}, rows)
+}
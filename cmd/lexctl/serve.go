@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal/api"
+)
+
+func runServe(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		addr  string
+		token string
+	)
+	fs := newFlagSet("serve", "")
+	fs.StringVar(&addr, "addr", "", "Address to listen on. Defaults to the one in the app settings, or "+api.DefaultAddr)
+	fs.StringVar(&token, "token", "", "Token clients must send. Defaults to the one in the app settings")
+	fs.Parse(args)
+
+	s, err := api.LoadSettings(ctx, appDb)
+	if err != nil {
+		return err
+	}
+	if addr != "" {
+		s.Addr = addr
+	}
+	if token != "" {
+		s.Token = token
+	} else if s.Token == "" {
+		// Keep the generated token, so clients can use it across restarts
+		if err := api.SaveSettings(ctx, appDb, s); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Generated API token: %s\n", s.Token)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv, err := api.NewServer(ctx, appDb, s)
+	if err != nil {
+		return err
+	}
+	if err := srv.Start(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Serving the API on http://%s/api/v1\n", srv.Addr())
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/accupdter"
+	_db "github.com/vladwithcode/lex_app/internal/db"
+)
+
+var ErrConflictingRange = errors.New("-since and -days-back can't be used together")
+
+// Outcome of an update printed with -json
+type updateResult struct {
+	Run          *_db.UpdateRun `json:"run"`
+	NotFoundKeys []string       `json:"notFoundKeys"`
+}
+
+func runUpdate(ctx context.Context, appDb *sql.DB, args []string) error {
+	var (
+		since     string
+		daysBack  int
+		exhaust   bool
+		types     string
+		queryStr  string
+		tags      string
+		savedName string
+		scheduled bool
+		asJSON    bool
+	)
+	fs := newFlagSet("update", "")
+	fs.StringVar(&since, "since", "", "Search every bulletin from today back to this date, as YYYY-MM-DD")
+	fs.IntVar(&daysBack, "days-back", -1, "Days searched back from today when the latest accord of a case is not found")
+	fs.BoolVar(&exhaust, "exhaust", false, "Keep searching back after the latest accord of a case is found")
+	fs.StringVar(&types, "types", "", "Comma separated case types to update, e.g. fam2,civ2")
+	fs.StringVar(&queryStr, "q", "", `Case query selecting the cases to update, e.g. "year:2024"`)
+	fs.StringVar(&tags, "tags", "", "Comma separated tags the updated cases must have")
+	fs.StringVar(&savedName, "saved", "", "Update the cases of the saved search with this name")
+	fs.BoolVar(&scheduled, "scheduled", false, "Update the cases of every saved search flagged as scheduled")
+	fs.BoolVar(&asJSON, "json", false, "Print the outcome as JSON")
+	fs.Parse(args)
+
+	startDate := time.Now()
+	if since != "" {
+		if daysBack >= 0 {
+			return ErrConflictingRange
+		}
+		sinceDate, err := time.ParseInLocation(dateLayout, since, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -since date %q: %w", since, err)
+		}
+		daysBack = int(startDate.Sub(sinceDate) / internal.Day)
+		if daysBack < 0 {
+			return fmt.Errorf("-since date %q is in the future", since)
+		}
+	}
+	if daysBack < 0 {
+		daysBack = 0
+	}
+
+	source := _db.UpdateRunSourceCli
+	var searches []*_db.FindCaseOptions
+	switch {
+	case savedName != "":
+		ss, err := _db.FindSavedSearchByName(ctx, appDb, savedName)
+		if err != nil {
+			return err
+		}
+		searches = append(searches, ss.Options)
+	case scheduled:
+		saved, err := _db.FindScheduledSavedSearches(ctx, appDb)
+		if err != nil {
+			return err
+		}
+		for _, ss := range saved {
+			searches = append(searches, ss.Options)
+		}
+		source = _db.UpdateRunSourceSchedule
+	default:
+		opts := _db.DefaultFindCaseOptions
+		opts.Query = queryStr
+		opts.Tags = splitList(tags)
+		searches = append(searches, &opts)
+	}
+
+	caseKeys, err := findUpdateKeys(ctx, appDb, searches, splitList(types))
+	if err != nil {
+		return err
+	}
+	if len(caseKeys) == 0 {
+		fmt.Println("No cases to update")
+		return nil
+	}
+
+	updater := accupdter.NewGeneralUpdater(&accupdter.GenUpdterConf{
+		Store:  accupdter.NewDefaultCaseStore(ctx, appDb),
+		Region: internal.RegionDefault,
+	})
+
+	run, notFoundKeys, err := updater.UpdateAndRecord(ctx, appDb, source, caseKeys, startDate, daysBack, exhaust)
+	if run == nil {
+		return err
+	}
+	noUpdates := errors.Is(err, accupdter.ErrNoUpdates)
+	if err != nil && !noUpdates {
+		return err
+	}
+	if noUpdates {
+		notFoundKeys = caseKeys
+	}
+
+	if asJSON {
+		return printJSON(&updateResult{Run: run, NotFoundKeys: notFoundKeys})
+	}
+
+	if noUpdates {
+		fmt.Printf("Searched %d cases, found no updates\n", run.CaseCount)
+		return nil
+	}
+	fmt.Printf(
+		"Searched %d cases, %d updated, %d without new accords\n",
+		run.CaseCount,
+		run.CaseCount-run.NotFoundCount,
+		run.NotFoundCount,
+	)
+	for _, key := range notFoundKeys {
+		fmt.Printf("  %s\n", key)
+	}
+
+	return nil
+}
+
+// Returns the keys of the unarchived cases matched by any of the searches,
+// limited to the given case types when not empty
+func findUpdateKeys(ctx context.Context, appDb *sql.DB, searches []*_db.FindCaseOptions, types []string) ([]string, error) {
+	allowed := map[string]bool{}
+	for _, t := range types {
+		allowed[strings.ToLower(t)] = true
+	}
+
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, opts := range searches {
+		opts.IncludeArchived = false
+		opts.Limit = 0
+		cases, err := _db.FindFilteredCases(ctx, appDb, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range cases {
+			key := c.GetCaseKey()
+			if seen[key] || (len(allowed) > 0 && !allowed[c.CaseType]) {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/accupdter"
+	_db "github.com/vladwithcode/lex_app/internal/db"
+)
+
+var ErrMissingWatchItem = errors.New("missing the watchlist item, pass its id")
+
+type watchCommand struct {
+	args  string
+	usage string
+	run   func(ctx context.Context, appDb *sql.DB, fs *flag.FlagSet, args []string) error
+}
+
+var watchCommands = map[string]*watchCommand{
+	"add":     {"<case id> <case type>", "Watch a case number before registering it", runWatchAdd},
+	"list":    {"", "List the watched case numbers", runWatchList},
+	"show":    {"<item>", "Show a watched case number and its hits", runWatchShow},
+	"rm":      {"<item>", "Stop watching a case number", runWatchRm},
+	"check":   {"", "Search the bulletins for the watched case numbers", runWatchCheck},
+	"promote": {"<item>", "Register the watched case number as a case with its hits as accords", runWatchPromote},
+}
+
+func runWatch(ctx context.Context, appDb *sql.DB, args []string) error {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	cmd, ok := watchCommands[name]
+	if !ok {
+		watchUsage()
+		if name == "" {
+			return errors.New("missing the watch command")
+		}
+		return fmt.Errorf("unknown watch command %q", name)
+	}
+
+	fs := flag.NewFlagSet("watch "+name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: lexctl watch %s [flags] %s\n\n%s\n\nFlags:\n", name, cmd.args, cmd.usage)
+		fs.PrintDefaults()
+	}
+
+	return cmd.run(ctx, appDb, fs, args[1:])
+}
+
+func watchUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: lexctl watch <command> [flags] [args]\n\nCommands:\n")
+
+	names := make([]string, 0, len(watchCommands))
+	for name := range watchCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-8s %s\n", name, watchCommands[name].usage)
+	}
+}
+
+func runWatchAdd(ctx context.Context, appDb *sql.DB, fs *flag.FlagSet, args []string) error {
+	var (
+		note   string
+		region string
+		asJSON bool
+	)
+	fs.StringVar(&note, "note", "", "Note about the matter, e.g. the name of the prospect")
+	fs.StringVar(&region, "region", string(internal.RegionDefault), "Region of the court, see 'lexctl regions'")
+	fs.BoolVar(&asJSON, "json", false, "Print the new item as JSON")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("watch add takes the case id and the case type, e.g. 'lexctl watch add 12/2024 fam2'")
+	}
+
+	w, err := _db.NewWatchItem(fs.Arg(0), strings.ToLower(fs.Arg(1)), region, note)
+	if err != nil {
+		return err
+	}
+	if err := _db.InsertWatchItem(ctx, appDb, w); err != nil {
+		return err
+	}
+
+	return printWatchItems([]*_db.WatchItem{w}, asJSON)
+}
+
+func runWatchList(ctx context.Context, appDb *sql.DB, fs *flag.FlagSet, args []string) error {
+	var (
+		all    bool
+		asJSON bool
+	)
+	fs.BoolVar(&all, "all", false, "Include the items already promoted to cases")
+	fs.BoolVar(&asJSON, "json", false, "Print the items as JSON")
+	fs.Parse(args)
+
+	items, err := _db.FindWatchItems(ctx, appDb, all)
+	if err != nil {
+		return err
+	}
+
+	return printWatchItems(items, asJSON)
+}
+
+func runWatchShow(ctx context.Context, appDb *sql.DB, fs *flag.FlagSet, args []string) error {
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "Print the item as JSON")
+	fs.Parse(args)
+
+	if fs.Arg(0) == "" {
+		return ErrMissingWatchItem
+	}
+	w, err := _db.FindWatchItemById(ctx, appDb, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return printJSON(w)
+	}
+
+	promoted := "-"
+	if w.PromotedAt != nil {
+		promoted = formatTime(*w.PromotedAt) + " as " + w.PromotedCaseId
+	}
+	err = printTable([]string{"FIELD", "VALUE"}, [][]string{
+		{"ID", w.Id},
+		{"Case", w.CaseId},
+		{"Type", w.CaseType},
+		{"Region", w.Region},
+		{"Note", w.Note},
+		{"Last checked", formatTimePtr(w.LastCheckedAt)},
+		{"Promoted", promoted},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	rows := make([][]string, len(w.Hits))
+	for i, h := range w.Hits {
+		rows[i] = []string{formatTime(h.Date), h.Content}
+	}
+
+	return printTable([]string{"DATE", "ACCORD"}, rows)
+}
+
+func runWatchRm(ctx context.Context, appDb *sql.DB, fs *flag.FlagSet, args []string) error {
+	fs.Parse(args)
+
+	if fs.Arg(0) == "" {
+		return ErrMissingWatchItem
+	}
+	if err := _db.DeleteWatchItemById(ctx, appDb, fs.Arg(0)); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s\n", fs.Arg(0))
+
+	return nil
+}
+
+func runWatchCheck(ctx context.Context, appDb *sql.DB, fs *flag.FlagSet, args []string) error {
+	var (
+		daysBack int
+		asJSON   bool
+	)
+	fs.IntVar(&daysBack, "days-back", 0, "Days searched back from today")
+	fs.BoolVar(&asJSON, "json", false, "Print the outcome as JSON")
+	fs.Parse(args)
+
+	updater := accupdter.NewGeneralUpdater(&accupdter.GenUpdterConf{
+		Region: internal.RegionDefault,
+	})
+	check, err := updater.CheckWatchlist(ctx, appDb, time.Now(), max(daysBack, 0))
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return printJSON(check)
+	}
+
+	fmt.Printf("Searched %d watched cases, %d new hits\n", check.Checked, len(check.NewHits))
+	for _, h := range check.NewHits {
+		fmt.Printf("  %s  %s  %s\n", h.WatchId, formatTime(h.Date), h.Content)
+	}
+
+	return nil
+}
+
+func runWatchPromote(ctx context.Context, appDb *sql.DB, fs *flag.FlagSet, args []string) error {
+	var (
+		alias  string
+		asJSON bool
+	)
+	fs.StringVar(&alias, "alias", "", "Alias of the new case")
+	fs.BoolVar(&asJSON, "json", false, "Print the case as JSON")
+	fs.Parse(args)
+
+	if fs.Arg(0) == "" {
+		return ErrMissingWatchItem
+	}
+	c, created, err := _db.PromoteWatchItem(ctx, appDb, fs.Arg(0), alias)
+	if err != nil {
+		return err
+	}
+	if created {
+		emit(ctx, appDb, _db.WebhookEventCaseCreated, c)
+	}
+
+	return printCases([]*_db.LexCase{c}, asJSON)
+}
+
+func printWatchItems(items []*_db.WatchItem, asJSON bool) error {
+	if asJSON {
+		return printJSON(items)
+	}
+
+	rows := make([][]string, len(items))
+	for i, w := range items {
+		rows[i] = []string{
+			w.Id,
+			w.CaseId,
+			w.CaseType,
+			strconv.Itoa(w.HitCount),
+			formatTimePtr(w.LastHitAt),
+			formatTimePtr(w.LastCheckedAt),
+			w.Note,
+		}
+	}
+
+	return printTable([]string{"ID", "CASE", "TYPE", "HITS", "LAST HIT", "CHECKED", "NOTE"}, rows)
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return formatTime(*t)
+}
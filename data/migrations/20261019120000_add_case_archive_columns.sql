@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE cases
+    ADD COLUMN archived_at integer DEFAULT NULL;
+
+ALTER TABLE cases
+    ADD COLUMN deleted_at integer DEFAULT NULL;
+
+CREATE INDEX cases_archived_at_idx ON cases (archived_at);
+CREATE INDEX cases_deleted_at_idx ON cases (deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX cases_deleted_at_idx;
+DROP INDEX cases_archived_at_idx;
+
+ALTER TABLE cases
+    DROP COLUMN deleted_at;
+
+ALTER TABLE cases
+    DROP COLUMN archived_at;
+-- +goose StatementEnd
@@ -0,0 +1,116 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE tags (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL COLLATE NOCASE,
+    color TEXT NOT NULL DEFAULT '',
+
+    CONSTRAINT unique_tag_name UNIQUE(name)
+);
+
+CREATE TABLE case_tags (
+    case_id TEXT NOT NULL,
+    tag_id TEXT NOT NULL,
+
+    PRIMARY KEY (case_id, tag_id),
+    FOREIGN KEY (case_id) REFERENCES cases(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX case_tags_tag_id_idx ON case_tags (tag_id);
+
+-- Rebuild the fts table to index the tag names of each case
+DROP TRIGGER fts_after_update_cases;
+DROP TRIGGER fts_after_insert_cases;
+DROP TRIGGER fts_after_delete_cases;
+DROP TABLE cases_fts;
+
+CREATE VIRTUAL TABLE cases_fts USING fts5(uuid UNINDEXED, case_id, case_type, alias, nature, tags);
+
+INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags)
+    SELECT id, case_id, case_type, alias, nature, ''
+    FROM cases;
+
+CREATE TRIGGER fts_after_insert_cases AFTER INSERT ON cases
+BEGIN
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags)
+        VALUES (new.id, new.case_id, new.case_type, new.alias, new.nature, '');
+END;
+
+CREATE TRIGGER fts_after_update_cases AFTER UPDATE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags)
+        SELECT
+            new.id,
+            new.case_id,
+            new.case_type,
+            new.alias,
+            new.nature,
+            (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.id);
+END;
+
+CREATE TRIGGER fts_after_delete_cases AFTER DELETE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+END;
+
+CREATE TRIGGER fts_after_insert_case_tags AFTER INSERT ON case_tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.case_id)
+        WHERE uuid = new.case_id;
+END;
+
+CREATE TRIGGER fts_after_delete_case_tags AFTER DELETE ON case_tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = old.case_id)
+        WHERE uuid = old.case_id;
+END;
+
+CREATE TRIGGER fts_after_update_tags AFTER UPDATE OF name ON tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = cases_fts.uuid)
+        WHERE uuid IN (SELECT case_id FROM case_tags WHERE tag_id = new.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER fts_after_update_tags;
+DROP TRIGGER fts_after_delete_case_tags;
+DROP TRIGGER fts_after_insert_case_tags;
+DROP TRIGGER fts_after_update_cases;
+DROP TRIGGER fts_after_insert_cases;
+DROP TRIGGER fts_after_delete_cases;
+DROP TABLE cases_fts;
+
+CREATE VIRTUAL TABLE cases_fts USING fts5(uuid UNINDEXED, case_id, case_type, alias, nature);
+
+INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature)
+    SELECT id, case_id, case_type, alias, nature
+    FROM cases;
+
+CREATE TRIGGER fts_after_update_cases AFTER UPDATE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature)
+        VALUES (new.id, new.case_id, new.case_type, new.alias, new.nature);
+END;
+
+CREATE TRIGGER fts_after_delete_cases AFTER DELETE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+END;
+
+CREATE TRIGGER fts_after_insert_cases AFTER INSERT ON cases
+BEGIN
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature)
+        VALUES (new.id, new.case_id, new.case_type, new.alias, new.nature);
+END;
+
+DROP TABLE case_tags;
+DROP TABLE tags;
+-- +goose StatementEnd
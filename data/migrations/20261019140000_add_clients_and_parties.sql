@@ -0,0 +1,246 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE clients (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL,
+    rfc TEXT DEFAULT '',
+    curp TEXT DEFAULT '',
+    email TEXT DEFAULT '',
+    phone TEXT DEFAULT '',
+    address TEXT DEFAULT '',
+    notes TEXT DEFAULT '',
+    created_at integer NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE TABLE parties (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL,
+    rfc TEXT DEFAULT '',
+    curp TEXT DEFAULT '',
+    email TEXT DEFAULT '',
+    phone TEXT DEFAULT '',
+    address TEXT DEFAULT '',
+    notes TEXT DEFAULT '',
+    created_at integer NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE TABLE case_clients (
+    case_id TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+
+    PRIMARY KEY (case_id, client_id),
+    FOREIGN KEY (case_id) REFERENCES cases(id) ON DELETE CASCADE,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+);
+
+CREATE TABLE case_parties (
+    case_id TEXT NOT NULL,
+    party_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+
+    PRIMARY KEY (case_id, party_id),
+    FOREIGN KEY (case_id) REFERENCES cases(id) ON DELETE CASCADE,
+    FOREIGN KEY (party_id) REFERENCES parties(id) ON DELETE CASCADE
+);
+
+CREATE INDEX case_clients_client_id_idx ON case_clients (client_id);
+CREATE INDEX case_parties_party_id_idx ON case_parties (party_id);
+
+-- Names of every client and party linked to a case
+CREATE VIEW case_party_names AS
+    SELECT case_clients.case_id, clients.name FROM case_clients INNER JOIN clients ON clients.id = case_clients.client_id
+    UNION ALL
+    SELECT case_parties.case_id, parties.name FROM case_parties INNER JOIN parties ON parties.id = case_parties.party_id;
+
+-- Rebuild the fts table to index the names of the parties in each case
+DROP TRIGGER fts_after_update_tags;
+DROP TRIGGER fts_after_delete_case_tags;
+DROP TRIGGER fts_after_insert_case_tags;
+DROP TRIGGER fts_after_update_cases;
+DROP TRIGGER fts_after_insert_cases;
+DROP TRIGGER fts_after_delete_cases;
+DROP TABLE cases_fts;
+
+CREATE VIRTUAL TABLE cases_fts USING fts5(uuid UNINDEXED, case_id, case_type, alias, nature, tags, parties);
+
+INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+    SELECT
+        cases.id,
+        cases.case_id,
+        cases.case_type,
+        cases.alias,
+        cases.nature,
+        (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = cases.id),
+        ''
+    FROM cases;
+
+CREATE TRIGGER fts_after_insert_cases AFTER INSERT ON cases
+BEGIN
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+        VALUES (new.id, new.case_id, new.case_type, new.alias, new.nature, '', '');
+END;
+
+CREATE TRIGGER fts_after_update_cases AFTER UPDATE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+        SELECT
+            new.id,
+            new.case_id,
+            new.case_type,
+            new.alias,
+            new.nature,
+            (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.id),
+            (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = new.id);
+END;
+
+CREATE TRIGGER fts_after_delete_cases AFTER DELETE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+END;
+
+CREATE TRIGGER fts_after_insert_case_tags AFTER INSERT ON case_tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.case_id)
+        WHERE uuid = new.case_id;
+END;
+
+CREATE TRIGGER fts_after_delete_case_tags AFTER DELETE ON case_tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = old.case_id)
+        WHERE uuid = old.case_id;
+END;
+
+CREATE TRIGGER fts_after_update_tags AFTER UPDATE OF name ON tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = cases_fts.uuid)
+        WHERE uuid IN (SELECT case_id FROM case_tags WHERE tag_id = new.id);
+END;
+
+CREATE TRIGGER fts_after_insert_case_clients AFTER INSERT ON case_clients
+BEGIN
+    UPDATE cases_fts
+        SET parties = (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = new.case_id)
+        WHERE uuid = new.case_id;
+END;
+
+CREATE TRIGGER fts_after_delete_case_clients AFTER DELETE ON case_clients
+BEGIN
+    UPDATE cases_fts
+        SET parties = (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = old.case_id)
+        WHERE uuid = old.case_id;
+END;
+
+CREATE TRIGGER fts_after_insert_case_parties AFTER INSERT ON case_parties
+BEGIN
+    UPDATE cases_fts
+        SET parties = (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = new.case_id)
+        WHERE uuid = new.case_id;
+END;
+
+CREATE TRIGGER fts_after_delete_case_parties AFTER DELETE ON case_parties
+BEGIN
+    UPDATE cases_fts
+        SET parties = (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = old.case_id)
+        WHERE uuid = old.case_id;
+END;
+
+CREATE TRIGGER fts_after_update_clients AFTER UPDATE OF name ON clients
+BEGIN
+    UPDATE cases_fts
+        SET parties = (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = cases_fts.uuid)
+        WHERE uuid IN (SELECT case_id FROM case_clients WHERE client_id = new.id);
+END;
+
+CREATE TRIGGER fts_after_update_parties AFTER UPDATE OF name ON parties
+BEGIN
+    UPDATE cases_fts
+        SET parties = (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = cases_fts.uuid)
+        WHERE uuid IN (SELECT case_id FROM case_parties WHERE party_id = new.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER fts_after_update_parties;
+DROP TRIGGER fts_after_update_clients;
+DROP TRIGGER fts_after_delete_case_parties;
+DROP TRIGGER fts_after_insert_case_parties;
+DROP TRIGGER fts_after_delete_case_clients;
+DROP TRIGGER fts_after_insert_case_clients;
+DROP VIEW case_party_names;
+
+DROP TRIGGER fts_after_update_tags;
+DROP TRIGGER fts_after_delete_case_tags;
+DROP TRIGGER fts_after_insert_case_tags;
+DROP TRIGGER fts_after_update_cases;
+DROP TRIGGER fts_after_insert_cases;
+DROP TRIGGER fts_after_delete_cases;
+DROP TABLE cases_fts;
+
+CREATE VIRTUAL TABLE cases_fts USING fts5(uuid UNINDEXED, case_id, case_type, alias, nature, tags);
+
+INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags)
+    SELECT
+        cases.id,
+        cases.case_id,
+        cases.case_type,
+        cases.alias,
+        cases.nature,
+        (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = cases.id)
+    FROM cases;
+
+CREATE TRIGGER fts_after_insert_cases AFTER INSERT ON cases
+BEGIN
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags)
+        VALUES (new.id, new.case_id, new.case_type, new.alias, new.nature, '');
+END;
+
+CREATE TRIGGER fts_after_update_cases AFTER UPDATE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags)
+        SELECT
+            new.id,
+            new.case_id,
+            new.case_type,
+            new.alias,
+            new.nature,
+            (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.id);
+END;
+
+CREATE TRIGGER fts_after_delete_cases AFTER DELETE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+END;
+
+CREATE TRIGGER fts_after_insert_case_tags AFTER INSERT ON case_tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.case_id)
+        WHERE uuid = new.case_id;
+END;
+
+CREATE TRIGGER fts_after_delete_case_tags AFTER DELETE ON case_tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = old.case_id)
+        WHERE uuid = old.case_id;
+END;
+
+CREATE TRIGGER fts_after_update_tags AFTER UPDATE OF name ON tags
+BEGIN
+    UPDATE cases_fts
+        SET tags = (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = cases_fts.uuid)
+        WHERE uuid IN (SELECT case_id FROM case_tags WHERE tag_id = new.id);
+END;
+
+DROP TABLE case_parties;
+DROP TABLE case_clients;
+DROP TABLE parties;
+DROP TABLE clients;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE case_notes (
+    id TEXT PRIMARY KEY NOT NULL,
+    case_id TEXT NOT NULL,
+    accord_id TEXT DEFAULT NULL,
+    content TEXT NOT NULL,
+    created_at integer NOT NULL DEFAULT (unixepoch()),
+    updated_at integer NOT NULL DEFAULT (unixepoch()),
+
+    FOREIGN KEY (case_id) REFERENCES cases(id) ON DELETE CASCADE,
+    FOREIGN KEY (accord_id) REFERENCES accords(id) ON DELETE SET NULL
+);
+
+CREATE INDEX case_notes_case_id_idx ON case_notes (case_id, created_at);
+
+CREATE VIRTUAL TABLE case_notes_fts USING fts5(uuid UNINDEXED, case_id UNINDEXED, content);
+
+CREATE TRIGGER fts_after_insert_case_notes AFTER INSERT ON case_notes
+BEGIN
+    INSERT INTO case_notes_fts (uuid, case_id, content)
+        VALUES (new.id, new.case_id, new.content);
+END;
+
+CREATE TRIGGER fts_after_update_case_notes AFTER UPDATE OF content ON case_notes
+BEGIN
+    DELETE FROM case_notes_fts WHERE uuid = old.id;
+    INSERT INTO case_notes_fts (uuid, case_id, content)
+        VALUES (new.id, new.case_id, new.content);
+END;
+
+CREATE TRIGGER fts_after_delete_case_notes AFTER DELETE ON case_notes
+BEGIN
+    DELETE FROM case_notes_fts WHERE uuid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER fts_after_delete_case_notes;
+DROP TRIGGER fts_after_update_case_notes;
+DROP TRIGGER fts_after_insert_case_notes;
+DROP TABLE case_notes_fts;
+DROP TABLE case_notes;
+-- +goose StatementEnd
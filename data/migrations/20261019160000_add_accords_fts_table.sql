@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE VIRTUAL TABLE accords_fts USING fts5(uuid UNINDEXED, for_case UNINDEXED, content);
+
+INSERT INTO accords_fts (uuid, for_case, content)
+    SELECT id, for_case, content
+    FROM accords;
+
+CREATE TRIGGER fts_after_insert_accords AFTER INSERT ON accords
+BEGIN
+    INSERT INTO accords_fts (uuid, for_case, content)
+        VALUES (new.id, new.for_case, new.content);
+END;
+
+CREATE TRIGGER fts_after_update_accords AFTER UPDATE OF content ON accords
+BEGIN
+    DELETE FROM accords_fts WHERE uuid = old.id;
+    INSERT INTO accords_fts (uuid, for_case, content)
+        VALUES (new.id, new.for_case, new.content);
+END;
+
+CREATE TRIGGER fts_after_delete_accords AFTER DELETE ON accords
+BEGIN
+    DELETE FROM accords_fts WHERE uuid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER fts_after_delete_accords;
+DROP TRIGGER fts_after_update_accords;
+DROP TRIGGER fts_after_insert_accords;
+DROP TABLE accords_fts;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE cases
+    ADD COLUMN last_checked_at integer DEFAULT NULL;
+
+CREATE INDEX cases_last_checked_at_idx ON cases (last_checked_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX cases_last_checked_at_idx;
+
+ALTER TABLE cases
+    DROP COLUMN last_checked_at;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE saved_searches (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL COLLATE NOCASE,
+    options TEXT NOT NULL DEFAULT '{}',
+    scheduled integer NOT NULL DEFAULT 0,
+    position integer NOT NULL DEFAULT 0,
+    created_at integer NOT NULL DEFAULT (unixepoch()),
+    updated_at integer NOT NULL DEFAULT (unixepoch()),
+
+    CONSTRAINT unique_saved_search_name UNIQUE(name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE saved_searches;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE cases
+    ADD COLUMN created_at integer DEFAULT NULL;
+
+-- Existing cases take the date of their oldest accord as an approximation
+UPDATE cases SET created_at = coalesce(
+    (SELECT CAST(min(accords.date) AS INTEGER) FROM accords WHERE accords.for_case = cases.id),
+    unixepoch()
+);
+
+-- Days without accords after which a case is considered stale.
+-- The row with case_type '*' applies to every type without its own row
+CREATE TABLE stale_thresholds (
+    case_type TEXT PRIMARY KEY NOT NULL,
+    days integer NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE stale_thresholds;
+
+ALTER TABLE cases
+    DROP COLUMN created_at;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE update_runs (
+    id TEXT PRIMARY KEY NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    case_count integer NOT NULL DEFAULT 0,
+    not_found_count integer NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at integer NOT NULL,
+    finished_at integer NOT NULL
+);
+
+CREATE INDEX update_runs_started_at_idx ON update_runs (started_at);
+CREATE INDEX accords_date_idx ON accords (date);
+CREATE INDEX cases_case_type_idx ON cases (case_type);
+CREATE INDEX cases_case_year_idx ON cases (case_year);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX cases_case_year_idx;
+DROP INDEX cases_case_type_idx;
+DROP INDEX accords_date_idx;
+DROP INDEX update_runs_started_at_idx;
+DROP TABLE update_runs;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- App settings as JSON values, e.g. the configuration of the API server
+CREATE TABLE settings (
+    key TEXT PRIMARY KEY NOT NULL,
+    value TEXT NOT NULL,
+    updated_at integer NOT NULL DEFAULT (unixepoch())
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE settings;
+-- +goose StatementEnd
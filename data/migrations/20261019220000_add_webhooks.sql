@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Endpoints notified of case events. events holds a JSON array of event names
+CREATE TABLE webhooks (
+    id TEXT PRIMARY KEY NOT NULL,
+    url TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    secret TEXT NOT NULL,
+    events TEXT NOT NULL DEFAULT '[]',
+    enabled integer NOT NULL DEFAULT 1,
+    created_at integer NOT NULL,
+    updated_at integer NOT NULL
+);
+
+-- Every attempt to notify a webhook of an event. Pending deliveries are
+-- retried at next_attempt_at
+CREATE TABLE webhook_deliveries (
+    id TEXT PRIMARY KEY NOT NULL,
+    webhook_id TEXT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    response_status integer NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at integer NOT NULL,
+    next_attempt_at integer DEFAULT NULL,
+    delivered_at integer DEFAULT NULL
+);
+
+CREATE INDEX webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX webhook_deliveries_status_idx ON webhook_deliveries (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_deliveries_status_idx;
+DROP INDEX webhook_deliveries_webhook_idx;
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Case numbers searched for before they are registered as cases
+CREATE TABLE watchlist (
+    id TEXT PRIMARY KEY NOT NULL,
+    case_id TEXT NOT NULL,
+    case_type TEXT NOT NULL,
+    region TEXT NOT NULL DEFAULT 'MX_DGO_DGO',
+    note TEXT NOT NULL DEFAULT '',
+    created_at integer NOT NULL,
+    last_checked_at integer DEFAULT NULL,
+    promoted_case_id TEXT DEFAULT NULL REFERENCES cases(id) ON DELETE SET NULL,
+    promoted_at integer DEFAULT NULL,
+
+    CONSTRAINT unique_watch_case UNIQUE(case_id, case_type)
+);
+
+-- Accords found for a watched case number, one per bulletin date
+CREATE TABLE watchlist_hits (
+    id TEXT PRIMARY KEY NOT NULL,
+    watch_id TEXT NOT NULL REFERENCES watchlist(id) ON DELETE CASCADE,
+    date integer NOT NULL,
+    nature TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    found_at integer NOT NULL,
+
+    CONSTRAINT unique_watch_hit UNIQUE(watch_id, date)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE watchlist_hits;
+DROP TABLE watchlist;
+-- +goose StatementEnd
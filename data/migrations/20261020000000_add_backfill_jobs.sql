@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Searches of past bulletins for the accord history of some cases
+CREATE TABLE backfill_jobs (
+    id TEXT PRIMARY KEY NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    -- Searched walking back from until_date to since_date, both included
+    since_date integer NOT NULL,
+    until_date integer NOT NULL,
+    accord_count integer NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at integer NOT NULL,
+    updated_at integer NOT NULL,
+    finished_at integer DEFAULT NULL
+);
+
+CREATE TABLE backfill_job_cases (
+    job_id TEXT NOT NULL REFERENCES backfill_jobs(id) ON DELETE CASCADE,
+    case_id TEXT NOT NULL REFERENCES cases(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (job_id, case_id)
+);
+
+-- Progress of a job per region and case type. Every date after
+-- next_date was already searched
+CREATE TABLE backfill_checkpoints (
+    job_id TEXT NOT NULL REFERENCES backfill_jobs(id) ON DELETE CASCADE,
+    region TEXT NOT NULL,
+    case_type TEXT NOT NULL,
+    next_date integer NOT NULL,
+    searched_days integer NOT NULL DEFAULT 0,
+    failed_days integer NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (job_id, region, case_type)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE backfill_checkpoints;
+DROP TABLE backfill_job_cases;
+DROP TABLE backfill_jobs;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Updates to the columns cases_fts doesn't index, like last_checked_at
+-- which every update run sets, don't rewrite the row of the case
+DROP TRIGGER fts_after_update_cases;
+
+CREATE TRIGGER fts_after_update_cases AFTER UPDATE OF case_id, case_type, alias, nature, other_ids ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+        SELECT
+            new.id,
+            new.case_id,
+            new.case_type,
+            new.alias,
+            new.nature,
+            (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.id),
+            (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = new.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER fts_after_update_cases;
+
+CREATE TRIGGER fts_after_update_cases AFTER UPDATE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+        SELECT
+            new.id,
+            new.case_id,
+            new.case_type,
+            new.alias,
+            new.nature,
+            (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.id),
+            (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = new.id);
+END;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Hearings scheduled for a case, printed on its reports
+CREATE TABLE case_hearings (
+    id TEXT PRIMARY KEY NOT NULL,
+    case_id TEXT NOT NULL REFERENCES cases(id) ON DELETE CASCADE,
+    date integer NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at integer NOT NULL
+);
+
+CREATE INDEX idx_case_hearings_case_date ON case_hearings(case_id, date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE case_hearings;
+-- +goose StatementEnd
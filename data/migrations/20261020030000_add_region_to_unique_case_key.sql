@@ -0,0 +1,136 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- The same case number and type may be tracked for the courts of several
+-- regions. SQLite can't change a table constraint in place, so the table
+-- is rebuilt. Foreign keys are turned off for the swap, otherwise dropping
+-- the old table would cascade to the accords, notes and tags of every case.
+-- Everything is sent as a single statement to run on the same connection
+-- +goose StatementBegin
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE cases_new (
+    id TEXT PRIMARY KEY NOT NULL,
+    case_id TEXT NOT NULL,
+    case_type TEXT NOT NULL,
+    case_year TEXT DEFAULT 0,
+    case_no TEXT DEFAULT 0,
+    alias TEXT DEFAULT '',
+    other_ids TEXT DEFAULT '',
+    region text NOT NULL DEFAULT 'MX_DGO_DGO',
+    nature text DEFAULT '',
+    archived_at integer DEFAULT NULL,
+    deleted_at integer DEFAULT NULL,
+    last_checked_at integer DEFAULT NULL,
+    created_at integer DEFAULT NULL,
+
+    CONSTRAINT unique_case_id UNIQUE(region, case_id, case_type)
+);
+
+INSERT INTO cases_new (id, case_id, case_type, case_year, case_no, alias, other_ids, region, nature, archived_at, deleted_at, last_checked_at, created_at)
+    SELECT id, case_id, case_type, case_year, case_no, alias, other_ids, region, nature, archived_at, deleted_at, last_checked_at, created_at
+    FROM cases;
+
+DROP TABLE cases;
+ALTER TABLE cases_new RENAME TO cases;
+
+CREATE INDEX cases_case_type_idx ON cases (case_type);
+CREATE INDEX cases_case_year_idx ON cases (case_year);
+CREATE INDEX cases_archived_at_idx ON cases (archived_at);
+CREATE INDEX cases_deleted_at_idx ON cases (deleted_at);
+CREATE INDEX cases_last_checked_at_idx ON cases (last_checked_at);
+
+CREATE TRIGGER fts_after_insert_cases AFTER INSERT ON cases
+BEGIN
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+    VALUES (new.id, new.case_id, new.case_type, new.alias, new.nature, '', '');
+END;
+
+CREATE TRIGGER fts_after_delete_cases AFTER DELETE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+END;
+
+CREATE TRIGGER fts_after_update_cases AFTER UPDATE OF case_id, case_type, alias, nature, other_ids ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+    SELECT
+        new.id,
+        new.case_id,
+        new.case_type,
+        new.alias,
+        new.nature,
+        (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.id),
+        (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = new.id);
+END;
+
+COMMIT;
+PRAGMA foreign_keys = ON;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Fails if the same number and type were registered in several regions
+-- +goose StatementBegin
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE cases_new (
+    id TEXT PRIMARY KEY NOT NULL,
+    case_id TEXT NOT NULL,
+    case_type TEXT NOT NULL,
+    case_year TEXT DEFAULT 0,
+    case_no TEXT DEFAULT 0,
+    alias TEXT DEFAULT '',
+    other_ids TEXT DEFAULT '',
+    region text NOT NULL DEFAULT 'MX_DGO_DGO',
+    nature text DEFAULT '',
+    archived_at integer DEFAULT NULL,
+    deleted_at integer DEFAULT NULL,
+    last_checked_at integer DEFAULT NULL,
+    created_at integer DEFAULT NULL,
+
+    CONSTRAINT unique_case_id UNIQUE(case_id, case_type)
+);
+
+INSERT INTO cases_new (id, case_id, case_type, case_year, case_no, alias, other_ids, region, nature, archived_at, deleted_at, last_checked_at, created_at)
+    SELECT id, case_id, case_type, case_year, case_no, alias, other_ids, region, nature, archived_at, deleted_at, last_checked_at, created_at
+    FROM cases;
+
+DROP TABLE cases;
+ALTER TABLE cases_new RENAME TO cases;
+
+CREATE INDEX cases_case_type_idx ON cases (case_type);
+CREATE INDEX cases_case_year_idx ON cases (case_year);
+CREATE INDEX cases_archived_at_idx ON cases (archived_at);
+CREATE INDEX cases_deleted_at_idx ON cases (deleted_at);
+CREATE INDEX cases_last_checked_at_idx ON cases (last_checked_at);
+
+CREATE TRIGGER fts_after_insert_cases AFTER INSERT ON cases
+BEGIN
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+    VALUES (new.id, new.case_id, new.case_type, new.alias, new.nature, '', '');
+END;
+
+CREATE TRIGGER fts_after_delete_cases AFTER DELETE ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+END;
+
+CREATE TRIGGER fts_after_update_cases AFTER UPDATE OF case_id, case_type, alias, nature, other_ids ON cases
+BEGIN
+    DELETE FROM cases_fts WHERE uuid = old.id;
+    INSERT INTO cases_fts (uuid, case_id, case_type, alias, nature, tags, parties)
+    SELECT
+        new.id,
+        new.case_id,
+        new.case_type,
+        new.alias,
+        new.nature,
+        (SELECT group_concat(tags.name, ' ') FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE case_tags.case_id = new.id),
+        (SELECT group_concat(name, ' ') FROM case_party_names WHERE case_id = new.id);
+END;
+
+COMMIT;
+PRAGMA foreign_keys = ON;
+-- +goose StatementEnd
@@ -0,0 +1,64 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Same rebuild as the one of the cases table, letting a number and type be
+-- watched in several regions. Foreign keys are off for the swap so the hits
+-- of each item are kept
+-- +goose StatementBegin
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE watchlist_new (
+    id TEXT PRIMARY KEY NOT NULL,
+    case_id TEXT NOT NULL,
+    case_type TEXT NOT NULL,
+    region TEXT NOT NULL DEFAULT 'MX_DGO_DGO',
+    note TEXT NOT NULL DEFAULT '',
+    created_at integer NOT NULL,
+    last_checked_at integer DEFAULT NULL,
+    promoted_case_id TEXT DEFAULT NULL REFERENCES cases(id) ON DELETE SET NULL,
+    promoted_at integer DEFAULT NULL,
+
+    CONSTRAINT unique_watch_case UNIQUE(region, case_id, case_type)
+);
+
+INSERT INTO watchlist_new (id, case_id, case_type, region, note, created_at, last_checked_at, promoted_case_id, promoted_at)
+    SELECT id, case_id, case_type, region, note, created_at, last_checked_at, promoted_case_id, promoted_at
+    FROM watchlist;
+
+DROP TABLE watchlist;
+ALTER TABLE watchlist_new RENAME TO watchlist;
+
+COMMIT;
+PRAGMA foreign_keys = ON;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Fails if the same number and type are watched in several regions
+-- +goose StatementBegin
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE watchlist_new (
+    id TEXT PRIMARY KEY NOT NULL,
+    case_id TEXT NOT NULL,
+    case_type TEXT NOT NULL,
+    region TEXT NOT NULL DEFAULT 'MX_DGO_DGO',
+    note TEXT NOT NULL DEFAULT '',
+    created_at integer NOT NULL,
+    last_checked_at integer DEFAULT NULL,
+    promoted_case_id TEXT DEFAULT NULL REFERENCES cases(id) ON DELETE SET NULL,
+    promoted_at integer DEFAULT NULL,
+
+    CONSTRAINT unique_watch_case UNIQUE(case_id, case_type)
+);
+
+INSERT INTO watchlist_new (id, case_id, case_type, region, note, created_at, last_checked_at, promoted_case_id, promoted_at)
+    SELECT id, case_id, case_type, region, note, created_at, last_checked_at, promoted_case_id, promoted_at
+    FROM watchlist;
+
+DROP TABLE watchlist;
+ALTER TABLE watchlist_new RENAME TO watchlist;
+
+COMMIT;
+PRAGMA foreign_keys = ON;
+-- +goose StatementEnd
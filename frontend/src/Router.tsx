@@ -5,6 +5,12 @@ import Dashboard from "./pages/Dashboard";
 import CasesPage from "./pages/cases/CasesPage";
 import NewCasePage from "./pages/cases/NewCasePage";
 import CaseDetailPage from "./pages/cases/CaseDetailPage";
+import ImportPage from "./pages/ImportPage";
+import SettingsPage from "./pages/SettingsPage";
+import WatchlistPage from "./pages/WatchlistPage";
+import BulletinPage from "./pages/BulletinPage";
+import AccordSearchPage from "./pages/AccordSearchPage";
+import TrashPage from "./pages/TrashPage";
 
 export default function Router() {
     return (
@@ -15,6 +21,12 @@ export default function Router() {
                     <Route path="/casos" element={<CasesPage />} />
                     <Route path="/casos/nuevo" element={<NewCasePage />} />
                     <Route path="/casos/:caseUUID" element={<CaseDetailPage />} />
+                    <Route path="/buscador" element={<BulletinPage />} />
+                    <Route path="/acuerdos" element={<AccordSearchPage />} />
+                    <Route path="/seguimiento" element={<WatchlistPage />} />
+                    <Route path="/importar" element={<ImportPage />} />
+                    <Route path="/papelera" element={<TrashPage />} />
+                    <Route path="/ajustes" element={<SettingsPage />} />
                 </Route>
 
                 <Route path="*" element={<ErrorPage error={new Error("Not found")} />} />
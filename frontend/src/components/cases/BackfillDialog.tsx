@@ -0,0 +1,113 @@
+import { useState } from "react";
+import { Button } from "../ui/button";
+import { LucideLoader } from "lucide-react";
+import { Dialog, DialogContent, DialogDescription, DialogFooter, DialogHeader, DialogTitle, DialogTrigger } from "@/components/ui/dialog";
+import { Input } from "@/components/ui/input";
+import { Separator } from "../ui/separator";
+import { Label } from "../ui/label";
+import { useFindCasesAndBackfill, useStartBackfill } from "@/queries/backfill";
+import { toast } from "sonner";
+import { CaseFilters } from "./CaseFilters";
+import { toInputDate } from "@/lib/formatUtils";
+
+// Months searched back by default
+const defaultMonths = 6
+
+// Starts a backfill for a single case when caseUUID is given,
+// otherwise for the cases matching the filters
+export default function BackfillDialog({
+    caseUUID,
+    filters,
+    blockAction,
+}: { caseUUID?: string; filters?: CaseFilters; blockAction: boolean }) {
+    const [isOpen, setIsOpen] = useState(false)
+    const [since, setSince] = useState(() => {
+        const d = new Date()
+        d.setMonth(d.getMonth() - defaultMonths)
+        return toInputDate(d)
+    })
+    const [until, setUntil] = useState(toInputDate(new Date()))
+    const startBackfill = useStartBackfill()
+    const findAndBackfill = useFindCasesAndBackfill()
+    const isPending = startBackfill.isPending || findAndBackfill.isPending
+
+    const callbacks = {
+        onSuccess: () => {
+            toast.success("Recuperación iniciada, puedes seguir su avance en la lista de casos")
+            setIsOpen(false)
+        },
+        onError: (err: Error) => {
+            toast.error(`No se pudo iniciar la recuperación: ${err}`)
+        },
+    }
+    const onStart = () => {
+        const range = { since: new Date(since + "T00:00"), until: new Date(until + "T00:00") }
+        if (caseUUID) {
+            startBackfill.mutate({ caseIds: [caseUUID], ...range }, callbacks)
+            return
+        }
+        findAndBackfill.mutate({
+            findOpts: {
+                CaseType: filters?.caseType,
+                CaseYear: filters?.caseYear,
+                CaseNo: filters?.caseNo,
+                Search: filters?.search,
+                IncludeAccords: false,
+            },
+            ...range,
+        }, callbacks)
+    }
+
+    return (
+        <Dialog open={isOpen} onOpenChange={setIsOpen}>
+            <DialogTrigger asChild>
+                <Button
+                    size="lg"
+                    variant="secondary"
+                    className="text-base font-bold active:scale-95 transition-transform duration-150 disabled:opacity-50"
+                    disabled={blockAction}>Recuperar historial</Button>
+            </DialogTrigger>
+            <DialogContent className="w-full max-w-lg">
+                <DialogHeader>
+                    <DialogTitle className="text-lg">Recuperar historial</DialogTitle>
+                    <DialogDescription>
+                        {caseUUID
+                            ? "Busca en los boletines anteriores los acuerdos de este caso."
+                            : "Busca en los boletines anteriores los acuerdos de los casos que coinciden con los filtros."}
+                        {" "}La búsqueda continúa en segundo plano y puede pausarse y reanudarse.
+                    </DialogDescription>
+                </DialogHeader>
+                <div>
+                    <Separator className="mb-2" />
+                    <div className="grid grid-cols-2 gap-4 py-1">
+                        <div className="col-span-1">
+                            <Label htmlFor="backfill-since">Desde (fecha):</Label>
+                            <Input
+                                id="backfill-since"
+                                type="date"
+                                value={since}
+                                onChange={(e) => setSince(e.target.value)} />
+                        </div>
+                        <div className="col-span-1">
+                            <Label htmlFor="backfill-until">Hasta (fecha):</Label>
+                            <Input
+                                id="backfill-until"
+                                type="date"
+                                value={until}
+                                onChange={(e) => setUntil(e.target.value)} />
+                        </div>
+                    </div>
+                    <Separator className="mt-4" />
+                </div>
+                <DialogFooter>
+                    <Button
+                        onClick={onStart}
+                        className="text-base"
+                        disabled={blockAction || isPending || !since || !until || since > until}>
+                        {isPending ? <LucideLoader className="animate-spin" /> : "Iniciar"}
+                    </Button>
+                </DialogFooter>
+            </DialogContent>
+        </Dialog>
+    )
+}
@@ -0,0 +1,71 @@
+import { LucidePause, LucidePlay, LucideTrash } from "lucide-react";
+import { Button } from "../ui/button";
+import { db } from "../../../wailsjs/go/models";
+import { formatDateToShortReadable } from "@/lib/formatUtils";
+import { useBackfillJobs, useDeleteBackfillJob, usePauseBackfill, useResumeBackfill } from "@/queries/backfill";
+import { toast } from "sonner";
+
+const statusNames: Record<string, string> = {
+    pending: "Pendiente",
+    running: "En curso",
+    paused: "Pausada",
+    done: "Terminada",
+    failed: "Error",
+}
+
+// Progress of the history recoveries. Finished jobs are left out
+export default function BackfillJobs() {
+    const { data } = useBackfillJobs()
+    const jobs = data?.filter(j => j.status !== "done") ?? []
+
+    if (jobs.length === 0) {
+        return null
+    }
+
+    return (
+        <div className="flex flex-col gap-2">
+            <h2 className="text-xl text-stone-200">Recuperación de historial</h2>
+            {jobs.map(j => <BackfillJobRow key={j.id} job={j} />)}
+        </div>
+    )
+}
+
+function BackfillJobRow({ job }: { job: db.BackfillJob }) {
+    const resume = useResumeBackfill()
+    const pause = usePauseBackfill()
+    const deleteJob = useDeleteBackfillJob()
+
+    const percent = job.totalDays > 0 ? Math.floor(job.searchedDays * 100 / job.totalDays) : 0
+    const failedDays = job.checkpoints?.reduce((n, cp) => n + cp.failedDays, 0) ?? 0
+
+    const onResume = () => {
+        resume.mutate(job.id, {
+            onError: err => toast.error(`No se pudo reanudar: ${err}`),
+        })
+    }
+
+    return (
+        <div className="rounded-lg border border-stone-700 p-2 text-sm space-y-1">
+            <div className="flex items-center gap-4">
+                <p className="font-semibold">{statusNames[job.status] ?? job.status}</p>
+                <p className="text-stone-400">
+                    {formatDateToShortReadable(new Date(job.since))} - {formatDateToShortReadable(new Date(job.until))}
+                </p>
+                <p className="text-stone-400">
+                    {job.searchedDays}/{job.totalDays} días · {job.accordCount} acuerdos
+                    {failedDays > 0 && ` · ${failedDays} boletines no disponibles`}
+                </p>
+                <div className="ml-auto flex gap-1">
+                    {job.status === "running"
+                        ? <Button size="icon" variant="ghost" onClick={() => pause.mutate(job.id)}><LucidePause /></Button>
+                        : <Button size="icon" variant="ghost" onClick={onResume} disabled={resume.isPending}><LucidePlay /></Button>}
+                    <Button size="icon" variant="ghost" onClick={() => deleteJob.mutate(job.id)}><LucideTrash /></Button>
+                </div>
+            </div>
+            <div className="h-1.5 rounded bg-stone-800">
+                <div className="h-full rounded bg-primary" style={{ width: `${percent}%` }} />
+            </div>
+            {job.error && <p className="text-red-400">{job.error}</p>}
+        </div>
+    )
+}
@@ -2,7 +2,7 @@ import { Button } from "../ui/button";
 import { Card, CardContent, CardFooter, CardHeader, CardTitle } from "../ui/card";
 import { Separator } from "../ui/separator";
 import { db } from "../../../wailsjs/go/models"
-import { CaseType, caseTypeToName } from "../../lib/caseTypeNames";
+import { useCourtName } from "@/queries/courts";
 import { Link } from "react-router";
 
 export type CaseCardProps = React.PropsWithChildren & {
@@ -10,13 +10,15 @@ export type CaseCardProps = React.PropsWithChildren & {
 }
 
 export default function CaseCard({ caseData, children }: CaseCardProps) {
+    const courtName = useCourtName()
+
     return (
         <Card className="bg-zinc-900 basis-1/3 flex-grow-0 flex-shrink-0 flex flex-col overflow-hidden">
             <CardHeader className="p-3">
                 <CardTitle className="text-lg font-medium">
                     Expediente {caseData.caseId}
                     <br />
-                    {caseTypeToName(caseData.caseType as CaseType)}
+                    {courtName(caseData.caseType, caseData.region)}
                 </CardTitle>
             </CardHeader>
             <Separator />
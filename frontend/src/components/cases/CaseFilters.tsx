@@ -1,5 +1,6 @@
 import { useState } from "react"
 import { getTribunalCategoryOptions, TribunalCategoryOptions } from "../../lib/caseTypeNames"
+import { Checkbox } from "../ui/checkbox"
 import { Input } from "../ui/input"
 import { Label } from "../ui/label"
 import { Select, SelectItem, SelectLabel, SelectContent, SelectGroup, SelectTrigger, SelectValue } from "../ui/select"
@@ -9,6 +10,8 @@ export type CaseFilters = {
     caseNo: string;
     caseYear: string;
     caseType: string;
+    // "1" when archived cases are included
+    archived: string;
 };
 
 export type CaseFiltersParams = React.PropsWithChildren & {
@@ -71,6 +74,13 @@ export default function CaseFilters({ setFilter, filters }: CaseFiltersParams) {
                         }}
                         defaultValue={filters.caseType} />
                 </div>
+                <div className="flex items-center gap-2 shrink-0 mt-auto pb-2">
+                    <Checkbox
+                        id="case-filter-archived"
+                        defaultChecked={filters.archived === "1"}
+                        onCheckedChange={c => setFilter("archived", c === true ? "1" : "")} />
+                    <Label htmlFor="case-filter-archived">Incluir archivados</Label>
+                </div>
                 {/* TODO: Implement Advanced Filters
                     <div className="flex gap-3 items-center shrink grow-0 max-w-32 ml-auto mt-auto">
                     <Button
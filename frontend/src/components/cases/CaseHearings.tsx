@@ -0,0 +1,81 @@
+import { useState } from "react"
+import { toast } from "sonner"
+import { LucideCalendarClock, LucideLoader, LucideTrash } from "lucide-react"
+import { Button } from "../ui/button"
+import { Input } from "../ui/input"
+import { formatDateToShortReadable } from "@/lib/formatUtils"
+import { useCreateCaseHearing, useDeleteCaseHearing, useUpcomingHearings } from "@/queries/hearings"
+
+const timeFormatter = new Intl.DateTimeFormat("es-MX", { hour: "2-digit", minute: "2-digit" })
+
+// Upcoming hearings of the case, printed on its PDF report
+export default function CaseHearings({ caseUUID }: { caseUUID: string }) {
+    const { data, isLoading, isError } = useUpcomingHearings(caseUUID)
+    const deleteHearing = useDeleteCaseHearing()
+
+    return (
+        <div className="flex flex-col gap-2">
+            <h2 className="text-2xl text-stone-200">Próximas audiencias</h2>
+            {isLoading && <LucideLoader className="animate-spin" />}
+            {isError && <p className="text-red-400">Ocurrio un error al recuperar las audiencias</p>}
+            {data?.length === 0 && <p className="text-stone-400">Sin audiencias programadas</p>}
+            {data && data.length > 0 && (
+                <ul className="flex flex-col gap-1">
+                    {data.map(h => (
+                        <li key={h.id} className="flex items-center gap-2 text-stone-200">
+                            <LucideCalendarClock size={16} />
+                            <span className="font-semibold">
+                                {formatDateToShortReadable(new Date(h.date))} {timeFormatter.format(new Date(h.date))}
+                            </span>
+                            <span>{h.description}</span>
+                            <Button
+                                size="icon"
+                                variant="ghost"
+                                onClick={() => deleteHearing.mutate(h.id)}
+                                disabled={deleteHearing.isPending}>
+                                <LucideTrash />
+                            </Button>
+                        </li>
+                    ))}
+                </ul>
+            )}
+            <HearingForm caseUUID={caseUUID} />
+        </div>
+    )
+}
+
+function HearingForm({ caseUUID }: { caseUUID: string }) {
+    const [date, setDate] = useState("")
+    const [description, setDescription] = useState("")
+    const createHearing = useCreateCaseHearing()
+
+    const onSubmit = (e: React.FormEvent) => {
+        e.preventDefault()
+        // datetime-local values have no offset, so they're read as local time
+        createHearing.mutate({ caseId: caseUUID, date: new Date(date), description }, {
+            onSuccess: () => {
+                setDate("")
+                setDescription("")
+            },
+            onError: err => toast.error(`No se pudo registrar la audiencia: ${err}`),
+        })
+    }
+
+    return (
+        <form onSubmit={onSubmit} className="flex items-center gap-2">
+            <Input
+                className="max-w-56"
+                type="datetime-local"
+                value={date}
+                onChange={e => setDate(e.target.value)} />
+            <Input
+                className="max-w-96"
+                placeholder="Descripción de la audiencia"
+                value={description}
+                onChange={e => setDescription(e.target.value)} />
+            <Button type="submit" variant="secondary" disabled={!date || description.trim() === "" || createHearing.isPending}>
+                {createHearing.isPending ? <LucideLoader className="animate-spin" /> : "Agregar audiencia"}
+            </Button>
+        </form>
+    )
+}
@@ -19,6 +19,6 @@ export default function CaseListing() {
     }
 
     return <div className="flex gap-4 max-w-full overflow-visible mb-2">
-        {isSuccess && data.map(c => <CaseCard key={c.id} caseData={c} />)}
+        {isSuccess && data.cases.map(c => <CaseCard key={c.id} caseData={c} />)}
     </div>
 }
@@ -0,0 +1,156 @@
+import { useState } from "react"
+import { toast } from "sonner"
+import { LucideArchive, LucideLoader, LucidePencil, LucideStickyNote, LucideTrash, LucideTrash2 } from "lucide-react"
+import { db } from "wailsjs/go/models"
+import { Button } from "../ui/button"
+import { Label } from "../ui/label"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "../ui/select"
+import { formatDateToShortReadable } from "@/lib/formatUtils"
+import { useCaseTimeline, useCreateCaseNote, useDeleteCaseNote, useUpdateCaseNote } from "@/queries/notes"
+import CaseAccordCard from "./CaseAccordCard"
+
+const noAccord = "none"
+
+const eventNames: Record<string, string> = {
+    "case.archived": "Caso archivado",
+    "case.deleted": "Caso movido a la papelera",
+}
+
+const formatDate = (date: string) => formatDateToShortReadable(new Date(date))
+
+// Accords, notes and events of the case from the newest to the oldest
+export default function CaseTimeline({ caseUUID, accordCount }: { caseUUID: string, accordCount: number }) {
+    const { data, isLoading, isError } = useCaseTimeline(caseUUID, accordCount)
+
+    if (isLoading) {
+        return <LucideLoader className="animate-spin" />
+    }
+    if (isError || !data) {
+        return <p className="text-red-400">Ocurrio un error al recuperar la línea de tiempo</p>
+    }
+
+    const accords = data.accords ?? []
+    const timeline = data.timeline ?? []
+
+    return (
+        <div className="flex flex-col gap-4 h-full overflow-auto">
+            <NoteForm caseUUID={caseUUID} accords={accords} />
+            {timeline.length === 0 && <p className="text-stone-400">El caso aún no tiene acuerdos ni notas</p>}
+            <ol className="flex flex-col gap-3 border-l border-stone-700 pl-4">
+                {timeline.map((entry, i) => (
+                    <li key={entry.accord?.id ?? entry.note?.id ?? `${entry.event}-${i}`}>
+                        <TimelineItem entry={entry} accords={accords} />
+                    </li>
+                ))}
+            </ol>
+        </div>
+    )
+}
+
+function TimelineItem({ entry, accords }: { entry: db.TimelineEntry, accords: db.Accord[] }) {
+    if (entry.accord) {
+        return <CaseAccordCard accord={entry.accord} />
+    }
+    if (entry.note) {
+        return <NoteItem note={entry.note} accords={accords} />
+    }
+
+    return (
+        <p className="flex items-center gap-2 text-stone-400">
+            {entry.event === "case.deleted" ? <LucideTrash2 size={16} /> : <LucideArchive size={16} />}
+            {eventNames[entry.event ?? ""] ?? entry.event} · {formatDate(entry.dateStr)}
+        </p>
+    )
+}
+
+function NoteItem({ note, accords }: { note: db.CaseNote, accords: db.Accord[] }) {
+    const [editing, setEditing] = useState(false)
+    const deleteNote = useDeleteCaseNote()
+    const pinned = accords.find(a => a.id === note.accordId)
+
+    if (editing) {
+        return <NoteForm note={note} caseUUID={note.caseId} accords={accords} onDone={() => setEditing(false)} />
+    }
+
+    return (
+        <div className="rounded-lg border border-amber-700/50 bg-amber-950/20 p-3 space-y-1">
+            <div className="flex items-center gap-2 text-sm text-stone-400">
+                <LucideStickyNote size={16} />
+                <p>
+                    Nota · {formatDate(String(note.createdAt))}
+                    {note.accordId && ` · sobre el acuerdo ${pinned ? `del ${formatDate(pinned.dateStr)}` : "anterior"}`}
+                </p>
+                <div className="ml-auto flex gap-1">
+                    <Button size="icon" variant="ghost" onClick={() => setEditing(true)}><LucidePencil /></Button>
+                    <Button size="icon" variant="ghost" onClick={() => deleteNote.mutate(note.id)} disabled={deleteNote.isPending}>
+                        <LucideTrash />
+                    </Button>
+                </div>
+            </div>
+            <p className="whitespace-pre-line text-stone-200">{note.content}</p>
+        </div>
+    )
+}
+
+// Creates a note, or edits it when one is given
+function NoteForm({ caseUUID, accords, note, onDone }: {
+    caseUUID: string,
+    accords: db.Accord[],
+    note?: db.CaseNote,
+    onDone?: () => void,
+}) {
+    const [content, setContent] = useState(note?.content ?? "")
+    const [accordId, setAccordId] = useState(note?.accordId || noAccord)
+    const createNote = useCreateCaseNote()
+    const updateNote = useUpdateCaseNote()
+    const isPending = createNote.isPending || updateNote.isPending
+
+    const onSubmit = (e: React.FormEvent) => {
+        e.preventDefault()
+        const pinTo = accordId === noAccord ? "" : accordId
+        const opts = {
+            onSuccess: () => {
+                setContent("")
+                setAccordId(noAccord)
+                onDone?.()
+            },
+            onError: (err: unknown) => toast.error(`No se pudo guardar la nota: ${err}`),
+        }
+
+        if (note) {
+            updateNote.mutate({ id: note.id, content, accordId: pinTo }, opts)
+        } else {
+            createNote.mutate({ caseId: caseUUID, content, accordId: pinTo }, opts)
+        }
+    }
+
+    return (
+        <form onSubmit={onSubmit} className="flex flex-col gap-2">
+            <Label htmlFor={`note-${note?.id ?? "new"}`}>{note ? "Editar nota" : "Nueva nota"}</Label>
+            <textarea
+                id={`note-${note?.id ?? "new"}`}
+                className="min-h-20 rounded-md border border-input bg-transparent px-3 py-2 text-sm"
+                placeholder="Escribe la nota en markdown"
+                value={content}
+                onChange={e => setContent(e.target.value)} />
+            <div className="flex items-center gap-2">
+                <Select value={accordId} onValueChange={setAccordId}>
+                    <SelectTrigger className="w-72"><SelectValue /></SelectTrigger>
+                    <SelectContent>
+                        <SelectItem value={noAccord}>Sin acuerdo</SelectItem>
+                        {accords.map(a => (
+                            <SelectItem key={a.id} value={a.id}>Acuerdo del {formatDate(a.dateStr)}</SelectItem>
+                        ))}
+                        {note?.accordId && !accords.some(a => a.id === note.accordId) && (
+                            <SelectItem value={note.accordId}>Acuerdo anterior</SelectItem>
+                        )}
+                    </SelectContent>
+                </Select>
+                {onDone && <Button type="button" variant="ghost" onClick={onDone}>Cancelar</Button>}
+                <Button type="submit" className="ml-auto" disabled={content.trim() === "" || isPending}>
+                    {isPending ? <LucideLoader className="animate-spin" /> : "Guardar nota"}
+                </Button>
+            </div>
+        </form>
+    )
+}
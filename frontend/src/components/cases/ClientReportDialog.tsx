@@ -0,0 +1,73 @@
+import { useState } from "react";
+import { toast } from "sonner";
+import { LucideFileText, LucideLoader } from "lucide-react";
+import { Button } from "../ui/button";
+import { Dialog, DialogContent, DialogDescription, DialogFooter, DialogHeader, DialogTitle, DialogTrigger } from "@/components/ui/dialog";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "../ui/select";
+import { Separator } from "../ui/separator";
+import { Label } from "../ui/label";
+import { isExportCancelledError, useExportClientReport } from "@/queries/cases";
+import { useClients } from "@/queries/clients";
+
+// Generates the PDF report of every tracked case of a client
+export default function ClientReportDialog({ blockAction }: { blockAction: boolean }) {
+    const [isOpen, setIsOpen] = useState(false)
+    const [clientId, setClientId] = useState("")
+    const { data: clients, isLoading } = useClients("")
+    const exportReport = useExportClientReport()
+
+    const onExport = () => {
+        exportReport.mutate(clientId, {
+            onSuccess: path => {
+                toast.success(`Reporte guardado en ${path}`)
+                setIsOpen(false)
+            },
+            onError: err => {
+                if (!isExportCancelledError(err)) {
+                    toast.error(`No se pudo generar el reporte: ${err}`)
+                }
+            },
+        })
+    }
+
+    return (
+        <Dialog open={isOpen} onOpenChange={setIsOpen}>
+            <DialogTrigger asChild>
+                <Button
+                    size="lg"
+                    variant="secondary"
+                    className="text-base font-bold active:scale-95 transition-transform duration-150 disabled:opacity-50"
+                    disabled={blockAction}>
+                    <LucideFileText /> Reporte de cliente
+                </Button>
+            </DialogTrigger>
+            <DialogContent className="w-full max-w-lg">
+                <DialogHeader>
+                    <DialogTitle className="text-lg">Reporte de cliente</DialogTitle>
+                    <DialogDescription>
+                        Genera un PDF con los casos del cliente, sus acuerdos, audiencias y notas.
+                    </DialogDescription>
+                </DialogHeader>
+                <div>
+                    <Separator className="mb-2" />
+                    <Label htmlFor="report-client">Cliente:</Label>
+                    {isLoading
+                        ? <LucideLoader className="animate-spin" />
+                        : <Select value={clientId} onValueChange={setClientId}>
+                            <SelectTrigger id="report-client"><SelectValue placeholder="Selecciona un cliente" /></SelectTrigger>
+                            <SelectContent>
+                                {clients?.map(c => <SelectItem key={c.id} value={c.id}>{c.name}</SelectItem>)}
+                            </SelectContent>
+                        </Select>}
+                    {clients?.length === 0 && <p className="text-stone-400 pt-2">No hay clientes registrados</p>}
+                    <Separator className="mt-4" />
+                </div>
+                <DialogFooter>
+                    <Button onClick={onExport} className="text-base" disabled={blockAction || !clientId || exportReport.isPending}>
+                        {exportReport.isPending ? <LucideLoader className="animate-spin" /> : "Generar"}
+                    </Button>
+                </DialogFooter>
+            </DialogContent>
+        </Dialog>
+    )
+}
@@ -8,7 +8,7 @@ import { Button } from "../ui/button"
 import { Link } from "react-router"
 import { cn } from "../../lib/utils"
 import { useState } from "react"
-import { useCreateCase } from "../../queries/cases"
+import { isCaseInTrashError, useCreateCase, useRestoreTrashedCase } from "../../queries/cases"
 import { CaseType, caseTypeToName, getTribunalCategoryOptions } from "../../lib/caseTypeNames"
 
 const TribunalOptions = getTribunalCategoryOptions()
@@ -20,15 +20,18 @@ export default function CreateCaseCard() {
         alias: string
     }>({ caseNo: "", caseType: "", alias: "" })
     const [displayErr, setDisplayErr] = useState("")
+    const [inTrash, setInTrash] = useState(false)
     const onFieldChange = (field: keyof typeof fields, value: string) => {
         setFields(prev => ({ ...prev, [field]: value }))
         setDisplayErr("")
+        setInTrash(false)
     }
 
     const [isLoading, setIsLoading] = useState(false)
     const [caseUUID, setCaseUUID] = useState("")
 
     const createCase = useCreateCase()
+    const restoreCase = useRestoreTrashedCase()
     const submitRegister: React.MouseEventHandler<HTMLButtonElement> = (e) => {
         e.preventDefault()
         setIsLoading(true)
@@ -44,7 +47,16 @@ export default function CreateCaseCard() {
                 },
                 onError: (err) => {
                     let errMsg = String(err)
-                    if (errMsg.includes("UNIQUE")) {
+                    if (isCaseInTrashError(err)) {
+                        setInTrash(true)
+                        setDisplayErr(
+                            "El caso "
+                            + fields.caseNo
+                            + " | "
+                            + caseTypeToName(fields.caseType as CaseType)
+                            + " está en la papelera"
+                        )
+                    } else if (errMsg.includes("UNIQUE")) {
                         setDisplayErr(
                             "Ya existe un registro para el caso "
                             + fields.caseNo
@@ -57,6 +69,10 @@ export default function CreateCaseCard() {
                             + fields.caseNo
                             + " no es válido. El formato debe ser '123/2024[-I]'"
                         )
+                    } else if (errMsg.includes("caseType invalid")) {
+                        setDisplayErr(
+                            "El juzgado seleccionado no publica acuerdos en la región del caso"
+                        )
                     } else {
                         setDisplayErr("Ocurrió un error al registrar el caso")
                     }
@@ -67,8 +83,19 @@ export default function CreateCaseCard() {
             })
         }, 100)
     }
+    const onRestore = () => {
+        restoreCase.mutate({ caseId: fields.caseNo, caseType: fields.caseType }, {
+            onSuccess: ({ id }) => {
+                setInTrash(false)
+                setDisplayErr("")
+                setCaseUUID(id)
+            },
+            onError: () => setDisplayErr("Ocurrió un error al restaurar el caso"),
+        })
+    }
     const onReset = () => {
         setDisplayErr("")
+        setInTrash(false)
         setCaseUUID("")
         setFields({ caseNo: "", caseType: "", alias: "" })
         createCase.reset()
@@ -135,15 +162,20 @@ export default function CreateCaseCard() {
                         value={fields.alias}
                         onChange={e => onFieldChange("alias", e.target.value)} />
                 </div>
-                <div className={createCase.isError ? "text-sm text-red-500/80 pt-2" : "opacity-0"}>
+                <div className={createCase.isError && displayErr ? "flex items-center gap-2 text-sm text-red-500/80 pt-2" : "opacity-0"}>
                     {displayErr || "error"}
+                    {inTrash && (
+                        <Button type="button" size="sm" variant="outline" onClick={onRestore} disabled={restoreCase.isPending}>
+                            Restaurar
+                        </Button>
+                    )}
                 </div>
             </CardContent>
             <Separator className="my-2" />
             <CardFooter className="justify-end items-end gap-2">
                 <FooterActions
                     uuid={caseUUID}
-                    show={createCase.isSuccess ? "success" : "default"}
+                    show={caseUUID ? "success" : "default"}
                     onReset={onReset}
                     onSubmit={submitRegister} />
             </CardFooter>
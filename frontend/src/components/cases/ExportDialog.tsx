@@ -0,0 +1,87 @@
+import { useState } from "react";
+import { toast } from "sonner";
+import { LucideDownload, LucideLoader } from "lucide-react";
+import { Button } from "../ui/button";
+import { Dialog, DialogContent, DialogDescription, DialogFooter, DialogHeader, DialogTitle, DialogTrigger } from "@/components/ui/dialog";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "../ui/select";
+import { Checkbox } from "../ui/checkbox";
+import { Separator } from "../ui/separator";
+import { Label } from "../ui/label";
+import { FindCaseOptions, isExportCancelledError, useExportCases } from "@/queries/cases";
+
+const formats = [
+    { value: "xlsx", label: "Excel (.xlsx)" },
+    { value: "csv", label: "CSV (.csv)" },
+    { value: "json", label: "JSON (.json)" },
+]
+
+// Exports the cases matching opts to a file picked by the user
+export default function ExportDialog({ opts, blockAction }: { opts: FindCaseOptions; blockAction: boolean }) {
+    const [isOpen, setIsOpen] = useState(false)
+    const [format, setFormat] = useState(formats[0].value)
+    const [includeAccords, setIncludeAccords] = useState(false)
+    const exportCases = useExportCases()
+
+    const onExport = () => {
+        exportCases.mutate({ opts, format, includeAccords }, {
+            onSuccess: path => {
+                toast.success(`Casos exportados a ${path}`)
+                setIsOpen(false)
+            },
+            onError: err => {
+                if (!isExportCancelledError(err)) {
+                    toast.error(`No se pudieron exportar los casos: ${err}`)
+                }
+            },
+        })
+    }
+
+    return (
+        <Dialog open={isOpen} onOpenChange={setIsOpen}>
+            <DialogTrigger asChild>
+                <Button
+                    size="lg"
+                    variant="secondary"
+                    className="text-base font-bold active:scale-95 transition-transform duration-150 disabled:opacity-50"
+                    disabled={blockAction}>
+                    <LucideDownload /> Exportar
+                </Button>
+            </DialogTrigger>
+            <DialogContent className="w-full max-w-lg">
+                <DialogHeader>
+                    <DialogTitle className="text-lg">Exportar casos</DialogTitle>
+                    <DialogDescription>
+                        Guarda en un archivo los casos que coinciden con los filtros.
+                    </DialogDescription>
+                </DialogHeader>
+                <div>
+                    <Separator className="mb-2" />
+                    <div className="flex flex-col gap-4 py-1">
+                        <div>
+                            <Label htmlFor="export-format">Formato:</Label>
+                            <Select value={format} onValueChange={setFormat}>
+                                <SelectTrigger id="export-format"><SelectValue /></SelectTrigger>
+                                <SelectContent>
+                                    {formats.map(f => <SelectItem key={f.value} value={f.value}>{f.label}</SelectItem>)}
+                                </SelectContent>
+                            </Select>
+                        </div>
+                        <div className="flex items-center gap-2">
+                            <Checkbox
+                                id="export-accords"
+                                checked={includeAccords}
+                                onCheckedChange={checked => setIncludeAccords(checked === true)} />
+                            <Label htmlFor="export-accords">Incluir el historial de acuerdos</Label>
+                        </div>
+                    </div>
+                    <Separator className="mt-4" />
+                </div>
+                <DialogFooter>
+                    <Button onClick={onExport} className="text-base" disabled={blockAction || exportCases.isPending}>
+                        {exportCases.isPending ? <LucideLoader className="animate-spin" /> : "Exportar"}
+                    </Button>
+                </DialogFooter>
+            </DialogContent>
+        </Dialog>
+    )
+}
@@ -21,7 +21,7 @@ export default function RecentCases() {
     }
 
     return <div className="flex gap-4 max-w-full overflow-visible mb-2">
-        {data.map(c => (
+        {data.cases.map(c => (
             <CaseCard key={c.id} caseData={c}>
                 <CardContent className="flex flex-col p-2 gap-2 grow">
                     {
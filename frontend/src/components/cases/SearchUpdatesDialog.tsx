@@ -6,7 +6,7 @@ import { Dialog, DialogContent, DialogDescription, DialogFooter, DialogHeader, D
 import { Tooltip, TooltipContent, TooltipProvider, TooltipTrigger } from "@/components/ui/tooltip";
 import { Input } from "@/components/ui/input";
 import { Separator } from "../ui/separator";
-import { CaseType, caseTypeToName } from "@/lib/caseTypeNames";
+import { useCourtName } from "@/queries/courts";
 import { Label } from "../ui/label";
 import { useUpdateCaseAccords } from "@/queries/cases";
 import { toast } from "sonner";
@@ -24,8 +24,9 @@ export default function SearchUpdatesDialog({
     caseUUID,
     caseId,
     caseType,
+    region,
     blockAction
-}: { caseUUID: string; blockAction: boolean; caseId: string; caseType: string }) {
+}: { caseUUID: string; blockAction: boolean; caseId: string; caseType: string; region?: string }) {
     const [isOpen, setIsOpen] = useState(false)
     const [searchParams, setSearchParams] = useState<SearchParams>({
         fromDate: new Date().toISOString().split('T')[0],
@@ -37,6 +38,7 @@ export default function SearchUpdatesDialog({
         setSearchParams(prev => ({ ...prev, [field]: value }))
     }
     const updateAccords = useUpdateCaseAccords(String(caseUUID))
+    const courtName = useCourtName()
 
     return (
         <Dialog open={isOpen} onOpenChange={setIsOpen}>
@@ -53,7 +55,7 @@ export default function SearchUpdatesDialog({
                         <p className="text-base pb-1">
                             Buscando para el caso
                             <span className="text-stone-100"> {caseId} </span>
-                            juzgado <span className="text-stone-100"> {caseTypeToName(caseType as CaseType)} </span>
+                            <span className="text-stone-100"> {courtName(caseType, region)} </span>
                         </p>
                         <p>Busca actualizaciones para este caso con parametros especificos.</p>
                     </DialogDescription>
@@ -0,0 +1,36 @@
+import { Link } from "react-router"
+import { useStaleCases } from "../../queries/cases"
+import { formatDateToShortReadable } from "../../lib/formatUtils"
+import { useCourtName } from "@/queries/courts"
+
+export default function StaleCaseAlerts() {
+    const { data, isSuccess } = useStaleCases(5)
+    const courtName = useCourtName()
+
+    if (!isSuccess || data.length === 0) {
+        return null
+    }
+
+    return <div className="flex flex-col gap-2 mb-2">
+        {data.map(c => (
+            <Link
+                key={c.id}
+                to={`/casos/${c.id}`}
+                className="flex flex-col gap-1 rounded-xl border border-amber-600 bg-amber-950/40 p-3 hover:bg-amber-950/70"
+            >
+                <p className="text-stone-200 font-semibold">
+                    {c.caseId} <span className="text-stone-400">{courtName(c.caseType)}</span>
+                    {c.alias && <span className="ml-2 text-stone-400">{c.alias}</span>}
+                </p>
+                <p className="text-amber-400 text-sm font-semibold">
+                    {c.daysInactive} días sin actividad desde el <span className="capitalize">{
+                        formatDateToShortReadable(new Date(c.lastActivityStr))
+                    }</span> (límite: {c.thresholdDays} días)
+                </p>
+                {c.lastAccordExcerpt && (
+                    <p className="text-stone-400 text-sm line-clamp-2">{c.lastAccordExcerpt}</p>
+                )}
+            </Link>
+        ))}
+    </div>
+}
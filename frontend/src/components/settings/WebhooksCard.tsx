@@ -0,0 +1,171 @@
+import { useState } from "react"
+import { toast } from "sonner"
+import { Card, CardContent, CardHeader, CardTitle } from "../ui/card"
+import { Button } from "../ui/button"
+import { Checkbox } from "../ui/checkbox"
+import { Input } from "../ui/input"
+import { Label } from "../ui/label"
+import { cn } from "../../lib/utils"
+import { db } from "../../../wailsjs/go/models"
+import {
+    useCreateWebhook,
+    useDeleteWebhook,
+    useSendTestWebhook,
+    useUpdateWebhook,
+    useWebhookDeliveries,
+    useWebhookEvents,
+    useWebhooks,
+} from "../../queries/webhooks"
+
+export default function WebhooksCard() {
+    const { data: webhooks } = useWebhooks()
+
+    return (
+        <Card className="col-span-2">
+            <CardHeader>
+                <CardTitle className="text-2xl font-semibold">Webhooks</CardTitle>
+            </CardHeader>
+            <CardContent className="space-y-4">
+                <p className="text-stone-400 text-sm">
+                    Notifica a otros sistemas cuando se registran casos o se encuentran acuerdos.
+                    Cada envío está firmado con el secreto del webhook en el encabezado <code>X-Lex-Signature</code>.
+                </p>
+                {webhooks?.map(wh => <WebhookRow key={wh.id} webhook={wh} />)}
+                <NewWebhookForm />
+                <DeliveryLog />
+            </CardContent>
+        </Card>
+    )
+}
+
+function EventCheckboxes({ selected, onChange }: { selected: string[], onChange: (events: string[]) => void }) {
+    const { data: events } = useWebhookEvents()
+
+    return (
+        <div className="flex flex-wrap gap-4">
+            {events?.map(ev => (
+                <div key={ev} className="flex items-center gap-2">
+                    <Checkbox
+                        id={`event-${ev}`}
+                        checked={selected.includes(ev)}
+                        onCheckedChange={c => onChange(c === true ? [...selected, ev] : selected.filter(s => s !== ev))}
+                    />
+                    <Label htmlFor={`event-${ev}`}><code>{ev}</code></Label>
+                </div>
+            ))}
+        </div>
+    )
+}
+
+function WebhookRow({ webhook }: { webhook: db.Webhook }) {
+    const updateWebhook = useUpdateWebhook()
+    const deleteWebhook = useDeleteWebhook()
+    const sendTest = useSendTestWebhook()
+
+    const onTest = () => {
+        sendTest.mutate(webhook.id, {
+            onSuccess: d => {
+                if (d.status === "delivered") {
+                    toast(`Envío de prueba entregado (${d.responseStatus})`)
+                } else {
+                    toast(`El envío de prueba falló: ${d.error}`)
+                }
+            }
+        })
+    }
+
+    return (
+        <div className="rounded-lg border border-stone-700 p-3 space-y-2">
+            <div className="flex items-center gap-2">
+                <Checkbox
+                    checked={webhook.enabled}
+                    onCheckedChange={c => updateWebhook.mutate({ ...webhook, enabled: c === true } as db.Webhook)}
+                />
+                <p className={cn("font-semibold truncate grow", !webhook.enabled && "text-stone-500")}>{webhook.url}</p>
+                <Button variant="outline" size="sm" onClick={onTest} disabled={sendTest.isPending}>Probar</Button>
+                <Button variant="outline" size="sm" onClick={() => deleteWebhook.mutate(webhook.id)}>Eliminar</Button>
+            </div>
+            {webhook.description && <p className="text-stone-400 text-sm">{webhook.description}</p>}
+            <EventCheckboxes
+                selected={webhook.events}
+                onChange={events => events.length > 0 && updateWebhook.mutate({ ...webhook, events } as db.Webhook)}
+            />
+            <div className="flex items-center gap-2 text-sm">
+                <Label>Secreto</Label>
+                <code className="text-stone-400 truncate">{webhook.secret}</code>
+                <Button variant="ghost" size="sm" onClick={() => navigator.clipboard.writeText(webhook.secret)}>Copiar</Button>
+            </div>
+        </div>
+    )
+}
+
+function NewWebhookForm() {
+    const createWebhook = useCreateWebhook()
+    const [url, setUrl] = useState("")
+    const [description, setDescription] = useState("")
+    const [events, setEvents] = useState<string[]>([])
+
+    const onCreate = () => {
+        createWebhook.mutate({ url, description, events }, {
+            onSuccess: () => {
+                setUrl("")
+                setDescription("")
+                setEvents([])
+            }
+        })
+    }
+
+    return (
+        <div className="space-y-2">
+            <Label>Nuevo webhook</Label>
+            <div className="flex gap-2">
+                <Input value={url} onChange={e => setUrl(e.target.value)} placeholder="https://ejemplo.com/webhooks/lex" />
+                <Input value={description} onChange={e => setDescription(e.target.value)} placeholder="Descripción" />
+            </div>
+            <EventCheckboxes selected={events} onChange={setEvents} />
+            {createWebhook.isError && <p className="text-red-400 text-sm">{String(createWebhook.error)}</p>}
+            <Button onClick={onCreate} disabled={createWebhook.isPending || url === "" || events.length === 0}>Agregar</Button>
+        </div>
+    )
+}
+
+const deliveryStatusNames: Record<string, string> = {
+    pending: "Pendiente",
+    delivered: "Entregado",
+    failed: "Fallido",
+}
+
+function DeliveryLog() {
+    const { data: deliveries } = useWebhookDeliveries("", 20)
+
+    if (!deliveries || deliveries.length === 0) {
+        return null
+    }
+
+    return (
+        <table className="w-full text-sm text-left">
+            <thead className="text-stone-400">
+                <tr>
+                    <th className="p-1">Fecha</th>
+                    <th className="p-1">Evento</th>
+                    <th className="p-1">Estado</th>
+                    <th className="p-1">Intentos</th>
+                    <th className="p-1">Error</th>
+                </tr>
+            </thead>
+            <tbody>
+                {deliveries.map(d => (
+                    <tr key={d.id} className="border-t border-stone-700">
+                        <td className="p-1">{new Date(d.createdAt).toLocaleString()}</td>
+                        <td className="p-1"><code>{d.event}</code></td>
+                        <td className={cn("p-1", d.status === "failed" && "text-red-400")}>
+                            {deliveryStatusNames[d.status]} {d.responseStatus > 0 && `(${d.responseStatus})`}
+                        </td>
+                        <td className="p-1">{d.attempts}</td>
+                        <td className="p-1 truncate max-w-xs">{d.error}</td>
+                    </tr>
+                ))}
+            </tbody>
+        </table>
+    )
+}
@@ -1,4 +1,4 @@
-import { Home, LucideFolder, SearchX } from "lucide-react";
+import { Eye, FileSearch, FileUp, Home, LucideFolder, SearchX, Settings, Trash2 } from "lucide-react";
 import {
     Sidebar,
     SidebarContent,
@@ -15,6 +15,7 @@ import { useNavigate } from "react-router";
 import { cn } from "../../lib/utils";
 import { Separator } from "../ui/separator";
 import { useSidebarStore } from "../../stores/useSidebarStore";
+import SavedSearchesNav from "./SavedSearchesNav";
 
 const items = [
     {
@@ -32,6 +33,31 @@ const items = [
         url: "/buscador",
         icon: SearchX,
     },
+    {
+        title: "Acuerdos",
+        url: "/acuerdos",
+        icon: FileSearch,
+    },
+    {
+        title: "Seguimiento",
+        url: "/seguimiento",
+        icon: Eye,
+    },
+    {
+        title: "Importar",
+        url: "/importar",
+        icon: FileUp,
+    },
+    {
+        title: "Papelera",
+        url: "/papelera",
+        icon: Trash2,
+    },
+    {
+        title: "Ajustes",
+        url: "/ajustes",
+        icon: Settings,
+    },
 ]
 
 export default function AppSidebar() {
@@ -47,6 +73,7 @@ export default function AppSidebar() {
                     <SidebarGroupLabel children="Menu" />
                     <NavMain />
                 </SidebarGroup>
+                <SavedSearchesNav />
             </SidebarContent>
         </Sidebar>
     )
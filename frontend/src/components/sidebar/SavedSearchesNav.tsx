@@ -0,0 +1,41 @@
+import { LucideListFilter } from "lucide-react";
+import { useNavigate } from "react-router";
+import {
+    SidebarGroup,
+    SidebarGroupLabel,
+    SidebarMenu,
+    SidebarMenuBadge,
+    SidebarMenuButton,
+    SidebarMenuItem,
+} from "../ui/sidebar";
+import { useSavedSearchCounts } from "@/queries/savedSearches";
+
+// The saved searches with the number of cases each one matches,
+// opening their cases in the case listing
+export default function SavedSearchesNav() {
+    const navigate = useNavigate()
+    const { data } = useSavedSearchCounts()
+
+    if (!data || data.length === 0) {
+        return null
+    }
+
+    return (
+        <SidebarGroup>
+            <SidebarGroupLabel children="Búsquedas guardadas" />
+            <SidebarMenu>
+                {data.map(({ search, count }) => (
+                    <SidebarMenuItem key={search.id}>
+                        <SidebarMenuButton
+                            title={search.name}
+                            onClick={() => navigate(`/casos?saved=${search.id}`)}>
+                            <LucideListFilter />
+                            <span className="truncate">{search.name}</span>
+                        </SidebarMenuButton>
+                        <SidebarMenuBadge>{count}</SidebarMenuBadge>
+                    </SidebarMenuItem>
+                ))}
+            </SidebarMenu>
+        </SidebarGroup>
+    )
+}
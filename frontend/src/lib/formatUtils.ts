@@ -7,3 +7,11 @@ const dateFormatter = new Intl.DateTimeFormat("es-MX", {
 export function formatDateToShortReadable(date: Date) {
     return dateFormatter.format(date)
 }
+
+// Formats the date as YYYY-MM-DD in local time, as expected by date inputs
+export function toInputDate(date: Date) {
+    const month = String(date.getMonth() + 1).padStart(2, "0")
+    const day = String(date.getDate()).padStart(2, "0")
+
+    return `${date.getFullYear()}-${month}-${day}`
+}
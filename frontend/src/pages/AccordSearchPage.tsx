@@ -0,0 +1,107 @@
+import { useEffect, useState } from "react";
+import { Link } from "react-router";
+import { LucideLoader } from "lucide-react";
+import BasePageHeader from "@/components/layouts/BasePageHeader";
+import { Separator } from "../components/ui/separator";
+import { Input } from "../components/ui/input";
+import { Label } from "../components/ui/label";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "../components/ui/select";
+import { db } from "../../wailsjs/go/models";
+import { splitSnippet, useAccordSearch } from "../queries/accords";
+import { useCourtName, useCourts } from "../queries/courts";
+import { formatDateToShortReadable } from "../lib/formatUtils";
+
+const allTypes = "all"
+
+export default function AccordSearchPage() {
+    const [search, setSearch] = useState("")
+    const [debounced, setDebounced] = useState("")
+    const [from, setFrom] = useState("")
+    const [to, setTo] = useState("")
+    const [caseType, setCaseType] = useState(allTypes)
+
+    useEffect(() => {
+        const id = setTimeout(() => setDebounced(search), 250)
+        return () => clearTimeout(id)
+    }, [search])
+
+    const { data: courts } = useCourts()
+    const caseTypes = [...new Set(courts?.map(c => c.caseType))]
+    const courtName = useCourtName()
+    const { data, isFetching, isError, error } = useAccordSearch({
+        Search: debounced,
+        From: from,
+        To: to,
+        CaseType: caseType === allTypes ? "" : caseType,
+    })
+
+    return (
+        <>
+            <BasePageHeader
+                title="Buscar en acuerdos"
+                description="Busca palabras en el contenido de los acuerdos de todos los casos, p. ej. embargo o caducidad." />
+            <Separator className="my-2" />
+            <div className="flex items-end gap-2">
+                <div className="flex flex-col gap-1 grow">
+                    <Label htmlFor="accord-search">Buscar</Label>
+                    <Input
+                        id="accord-search"
+                        type="search"
+                        placeholder="Palabras del acuerdo"
+                        value={search}
+                        onChange={e => setSearch(e.target.value)} />
+                </div>
+                <div className="flex flex-col gap-1">
+                    <Label htmlFor="accord-search-from">Desde</Label>
+                    <Input id="accord-search-from" type="date" value={from} onChange={e => setFrom(e.target.value)} />
+                </div>
+                <div className="flex flex-col gap-1">
+                    <Label htmlFor="accord-search-to">Hasta</Label>
+                    <Input id="accord-search-to" type="date" value={to} onChange={e => setTo(e.target.value)} />
+                </div>
+                <div className="flex flex-col gap-1">
+                    <Label>Juzgado</Label>
+                    <Select value={caseType} onValueChange={setCaseType}>
+                        <SelectTrigger className="w-72"><SelectValue /></SelectTrigger>
+                        <SelectContent>
+                            <SelectItem value={allTypes}>Todos</SelectItem>
+                            {caseTypes.map(ct => <SelectItem key={ct} value={ct}>{courtName(ct)}</SelectItem>)}
+                        </SelectContent>
+                    </Select>
+                </div>
+            </div>
+            <Separator className="my-2" />
+
+            {isFetching && <LucideLoader className="animate-spin" />}
+            {isError && <p className="text-red-400">No se pudo completar la búsqueda: {String(error)}</p>}
+            {data && !isFetching && (
+                <div className="flex flex-col gap-3 max-h-full overflow-auto">
+                    <p className="text-stone-400 text-sm">{data.length} acuerdos</p>
+                    {data.map(r => <AccordResult key={r.accord.id} result={r} />)}
+                </div>
+            )}
+        </>
+    )
+}
+
+function AccordResult({ result }: { result: db.AccordSearchResult }) {
+    const courtName = useCourtName()
+
+    return (
+        <div className="rounded-lg border border-stone-700 p-3 space-y-1 text-sm">
+            <div className="flex items-center gap-2 text-stone-400">
+                <Link to={`/casos/${result.accord.forCase}`} className="font-semibold text-stone-200 hover:underline">
+                    Expediente {result.caseId}
+                </Link>
+                <p>{courtName(result.caseType)}</p>
+                {result.alias && <p>· {result.alias}</p>}
+                <p className="ml-auto">{formatDateToShortReadable(new Date(result.accord.dateStr))}</p>
+            </div>
+            <p className="text-stone-200">
+                {splitSnippet(result.snippet).map((p, i) => p.match
+                    ? <mark key={i} className="bg-primary/40 text-stone-50 rounded-sm">{p.text}</mark>
+                    : <span key={i}>{p.text}</span>)}
+            </p>
+        </div>
+    )
+}
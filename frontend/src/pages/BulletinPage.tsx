@@ -0,0 +1,154 @@
+import { useState } from "react";
+import { Link } from "react-router";
+import { toast } from "sonner";
+import { LucideLoader, LucidePlus } from "lucide-react";
+import BasePageHeader from "@/components/layouts/BasePageHeader";
+import { Separator } from "../components/ui/separator";
+import { Button } from "../components/ui/button";
+import { Input } from "../components/ui/input";
+import { Label } from "../components/ui/label";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "../components/ui/select";
+import { accupdter } from "../../wailsjs/go/models";
+import { courtKey, parseCourtKey, useCourts } from "../queries/courts";
+import { bulletinQueryKeys, useBulletin } from "../queries/bulletins";
+import { isCaseInTrashError, useCreateRegionCase, useRestoreTrashedCase } from "../queries/cases";
+import { cn } from "../lib/utils";
+import { toInputDate } from "../lib/formatUtils";
+import queryClient from "@/QueryClient";
+
+// Lower cases and strips accents so searches ignore them
+const normalize = (s: string) => s.normalize("NFD").replace(/[\u0300-\u036f]/g, "").toLowerCase()
+
+export default function BulletinPage() {
+    const [court, setCourt] = useState("")
+    const [date, setDate] = useState(toInputDate(new Date()))
+    const [search, setSearch] = useState("")
+
+    const { data: courts } = useCourts()
+    const { region, caseType } = court ? parseCourtKey(court) : { region: "", caseType: "" }
+    const { data, isFetching, isError, error } = useBulletin(region, caseType, date)
+
+    const terms = normalize(search).split(/\s+/).filter(t => t !== "")
+    const rows = data?.rows.filter(r => {
+        const text = normalize([r.caseId, ...r.otherIds, r.nature, r.accord].join(" "))
+        return terms.every(t => text.includes(t))
+    }) ?? []
+
+    return (
+        <>
+            <BasePageHeader
+                title="Buscar en el boletín"
+                description="Revisa la lista completa de un juzgado en una fecha, p. ej. cuando el cliente no recuerda su número de expediente." />
+            <Separator className="my-2" />
+            <div className="flex items-end gap-2">
+                <div className="flex flex-col gap-1">
+                    <Label>Juzgado</Label>
+                    <Select value={court} onValueChange={setCourt}>
+                        <SelectTrigger className="w-96"><SelectValue placeholder="Selecciona el juzgado" /></SelectTrigger>
+                        <SelectContent>
+                            {courts?.map(c => (
+                                <SelectItem key={courtKey(c)} value={courtKey(c)}>{c.name}</SelectItem>
+                            ))}
+                        </SelectContent>
+                    </Select>
+                </div>
+                <div className="flex flex-col gap-1">
+                    <Label htmlFor="bulletin-date">Fecha</Label>
+                    <Input id="bulletin-date" type="date" value={date} onChange={e => setDate(e.target.value)} />
+                </div>
+                <div className="flex flex-col gap-1 grow">
+                    <Label htmlFor="bulletin-search">Filtrar</Label>
+                    <Input
+                        id="bulletin-search"
+                        placeholder="Nombre de las partes, naturaleza o número"
+                        value={search}
+                        onChange={e => setSearch(e.target.value)} />
+                </div>
+            </div>
+            <Separator className="my-2" />
+
+            {isFetching && <LucideLoader className="animate-spin" />}
+            {isError && <p className="text-red-400">No se pudo recuperar la lista: {String(error)}</p>}
+            {!court && <p className="text-stone-400">Selecciona un juzgado y una fecha para ver su lista</p>}
+            {data && !isFetching && (
+                <div className="flex flex-col gap-2 max-h-full overflow-auto">
+                    <p className="text-stone-400 text-sm">
+                        {rows.length} de {data.rows.length} expedientes
+                        {data.unparsed.length > 0 && ` · ${data.unparsed.length} filas sin número de expediente`}
+                    </p>
+                    <table className="text-sm text-left">
+                        <thead className="text-stone-400">
+                            <tr>
+                                <th className="p-1">Expediente</th>
+                                <th className="p-1">Naturaleza</th>
+                                <th className="p-1">Acuerdo</th>
+                                <th className="p-1"></th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {rows.map((r, i) => <BulletinRow key={`${r.caseId}-${i}`} row={r} bulletin={data} />)}
+                        </tbody>
+                    </table>
+                </div>
+            )}
+        </>
+    )
+}
+
+function BulletinRow({ row, bulletin }: { row: accupdter.BulletinRow, bulletin: accupdter.Bulletin }) {
+    const createCase = useCreateRegionCase()
+    const restoreCase = useRestoreTrashedCase()
+
+    const onRestore = () => {
+        restoreCase.mutate({ caseId: row.caseId, caseType: bulletin.caseType, region: bulletin.region }, {
+            onSuccess: () => {
+                toast.success(`Caso ${row.caseId} restaurado`)
+                queryClient.invalidateQueries({ queryKey: bulletinQueryKeys.all })
+            },
+            onError: err => toast.error(`No se pudo restaurar el caso: ${err}`),
+        })
+    }
+
+    const onAdd = () => {
+        createCase.mutate({ caseId: row.caseId, caseType: bulletin.caseType, region: bulletin.region }, {
+            onSuccess: () => {
+                toast.success(`Caso ${row.caseId} registrado`)
+                queryClient.invalidateQueries({ queryKey: bulletinQueryKeys.all })
+            },
+            onError: err => {
+                if (isCaseInTrashError(err)) {
+                    toast.error(`El caso ${row.caseId} está en la papelera`, {
+                        action: { label: "Restaurar", onClick: onRestore },
+                    })
+                    return
+                }
+                toast.error(`No se pudo registrar el caso: ${err}`)
+            },
+        })
+    }
+
+    return (
+        <tr className={cn("border-t border-stone-700 align-top", row.trackedCaseId && "bg-primary/15")}>
+            <td className="p-1 whitespace-nowrap">
+                {row.caseId}
+                {row.otherIds.length > 1 && (
+                    <p className="text-stone-400 text-xs">{row.otherIds.filter(id => id !== row.caseId).join(", ")}</p>
+                )}
+            </td>
+            <td className="p-1 whitespace-pre-line">{row.nature}</td>
+            <td className="p-1 whitespace-pre-line">{row.accord}</td>
+            <td className="p-1 whitespace-nowrap text-right">
+                {row.trackedCaseId ? (
+                    <Button size="sm" variant="outline" asChild>
+                        <Link to={`/casos/${row.trackedCaseId}`}>Ver caso</Link>
+                    </Button>
+                ) : (
+                    <Button size="sm" onClick={onAdd} disabled={createCase.isPending}>
+                        <LucidePlus /> Agregar
+                    </Button>
+                )}
+                {row.watchId && <p className="text-stone-400 text-xs pt-1">En seguimiento</p>}
+            </td>
+        </tr>
+    )
+}
@@ -1,5 +1,6 @@
 import { Link } from "react-router";
 import RecentCases from "../components/cases/RecentCases";
+import StaleCaseAlerts from "../components/cases/StaleCaseAlerts";
 import { Separator } from "../components/ui/separator";
 import BasePageHeader from "@/components/layouts/BasePageHeader";
 
@@ -8,6 +9,9 @@ export default function Dashboard() {
         <>
             <BasePageHeader title="lexApp" description="Los ultimos acuerdos publicados para tus casos." />
             <Separator className="my-2" />
+            <div className="py-2 px-4">
+                <StaleCaseAlerts />
+            </div>
             <div className="py-2 px-4">
                 <h2 className="text-3xl">
                     <span>Ultimas Actualizaciones</span>
@@ -0,0 +1,172 @@
+import { useEffect, useState } from "react";
+import { LucideFileUp, LucideLoader } from "lucide-react";
+import { Separator } from "../components/ui/separator";
+import { Button } from "../components/ui/button";
+import { Checkbox } from "../components/ui/checkbox";
+import { Label } from "../components/ui/label";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "../components/ui/select";
+import { SelectImportFile } from "../../wailsjs/go/controllers/ImportController";
+import { EventsOn } from "../../wailsjs/runtime/runtime";
+import { ImportSource, importFields, useImportPreview, useRunImport } from "../queries/imports";
+import { cn } from "../lib/utils";
+
+const emptySource: ImportSource = { path: "", format: "", mapping: {}, sheet: "" }
+const unmapped = "-"
+
+const statusNames: Record<string, string> = {
+    created: "Nuevo",
+    updated: "Actualizado",
+    skipped: "Omitido",
+    invalid: "Inválido",
+    failed: "Error",
+}
+
+export default function ImportPage() {
+    const [src, setSrc] = useState<ImportSource>(emptySource)
+    const [upsert, setUpsert] = useState(false)
+    const [progress, setProgress] = useState<{ done: number, total: number } | null>(null)
+
+    const preview = useImportPreview(src, upsert)
+    const runImport = useRunImport()
+
+    useEffect(() => {
+        return EventsOn("import:progress", setProgress)
+    }, [])
+
+    const selectFile = async () => {
+        const path = await SelectImportFile()
+        if (path) {
+            setSrc({ ...emptySource, path })
+            runImport.reset()
+        }
+    }
+    const setMapping = (field: string, column: string) => {
+        const mapping = { ...src.mapping }
+        if (column === unmapped) {
+            delete mapping[field]
+        } else {
+            mapping[field] = column
+        }
+        setSrc({ ...src, mapping })
+    }
+    const submitImport = () => {
+        setProgress(null)
+        if (preview.data) {
+            runImport.mutate(preview.data.id)
+        }
+    }
+
+    const data = preview.data
+    const report = runImport.data ?? data?.report
+
+    return (
+        <>
+            <h1 className="text-6xl font-semibold">Importar Casos | lexApp</h1>
+            <p className="text-lg text-stone-400 pt-2">Registra varios casos a la vez desde un archivo JSON, CSV o XLSX.</p>
+            <Separator className="my-2" />
+            <div className="flex flex-col gap-4 max-h-full overflow-auto">
+                <div className="flex items-center gap-4">
+                    <Button onClick={selectFile}><LucideFileUp /> Seleccionar archivo</Button>
+                    <p className="text-stone-400 truncate">{src.path || "Ningún archivo seleccionado"}</p>
+                </div>
+
+                {preview.isError && (
+                    <p className="text-red-400">No se pudo leer el archivo: {String(preview.error)}</p>
+                )}
+                {preview.isFetching && <LucideLoader className="animate-spin" />}
+
+                {data && (
+                    <>
+                        {data.sheets.length > 0 && (
+                            <div className="flex items-center gap-2">
+                                <Label>Hoja</Label>
+                                <Select value={src.sheet || data.sheets[0]} onValueChange={sheet => setSrc({ ...src, sheet, mapping: {} })}>
+                                    <SelectTrigger className="w-60"><SelectValue /></SelectTrigger>
+                                    <SelectContent>
+                                        {data.sheets.map(s => <SelectItem key={s} value={s}>{s}</SelectItem>)}
+                                    </SelectContent>
+                                </Select>
+                            </div>
+                        )}
+                        {data.format !== "json" && (
+                            <div className="grid grid-cols-4 gap-2">
+                                {importFields.map(field => (
+                                    <div key={field} className="flex flex-col gap-1">
+                                        <Label>{field}</Label>
+                                        <Select value={src.mapping[field] ?? unmapped} onValueChange={col => setMapping(field, col)}>
+                                            <SelectTrigger><SelectValue /></SelectTrigger>
+                                            <SelectContent>
+                                                <SelectItem value={unmapped}>Automático</SelectItem>
+                                                {data.columns.filter(c => c !== "").map(c => (
+                                                    <SelectItem key={c} value={c}>{c}</SelectItem>
+                                                ))}
+                                            </SelectContent>
+                                        </Select>
+                                    </div>
+                                ))}
+                            </div>
+                        )}
+                        <div className="flex items-center gap-2">
+                            <Checkbox id="upsert" checked={upsert} onCheckedChange={c => setUpsert(c === true)} />
+                            <Label htmlFor="upsert">Actualizar los casos existentes</Label>
+                        </div>
+
+                        {report && (
+                            <p className="text-stone-300">
+                                {report.dryRun ? "Vista previa" : "Resultado"}: {report.created} nuevos,
+                                {" "}{report.updated} actualizados, {report.skipped} omitidos,
+                                {" "}{report.invalid} inválidos, {report.failed} con error
+                                {report.rolledBack && " (no se guardó ningún cambio)"}
+                            </p>
+                        )}
+                        {runImport.isError && (
+                            <p className="text-red-400">La importación falló: {String(runImport.error)}</p>
+                        )}
+
+                        <div className="flex items-center gap-4">
+                            <Button
+                                onClick={submitImport}
+                                disabled={runImport.isPending || preview.isFetching || data.report.created + data.report.updated === 0}>
+                                Importar
+                            </Button>
+                            {runImport.isPending && progress && (
+                                <p className="text-stone-400">{progress.done} / {progress.total}</p>
+                            )}
+                        </div>
+
+                        <table className="text-sm text-left">
+                            <thead className="text-stone-400">
+                                <tr>
+                                    <th className="p-1">Línea</th>
+                                    <th className="p-1">Expediente</th>
+                                    <th className="p-1">Tipo</th>
+                                    <th className="p-1">Alias</th>
+                                    <th className="p-1">Acuerdos</th>
+                                    <th className="p-1">Estado</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {data.rows.map(r => (
+                                    <tr key={r.result.line} className="border-t border-stone-700">
+                                        <td className="p-1">{r.result.line}</td>
+                                        <td className="p-1">{r.case.case_id}</td>
+                                        <td className="p-1 uppercase">{r.case.case_type}</td>
+                                        <td className="p-1">{r.case.alias}</td>
+                                        <td className="p-1">{r.case.accords?.length ?? 0}</td>
+                                        <td className={cn(
+                                            "p-1",
+                                            (r.result.status === "invalid" || r.result.status === "failed") && "text-red-400"
+                                        )}>
+                                            {statusNames[r.result.status]}
+                                            {r.result.errors?.map(e => <p key={e} className="text-xs">{e}</p>)}
+                                        </td>
+                                    </tr>
+                                ))}
+                            </tbody>
+                        </table>
+                    </>
+                )}
+            </div>
+        </>
+    )
+}
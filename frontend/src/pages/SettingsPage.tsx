@@ -0,0 +1,92 @@
+import { useEffect, useState } from "react";
+import { Separator } from "../components/ui/separator";
+import { Card, CardContent, CardFooter, CardHeader, CardTitle } from "../components/ui/card";
+import { Button } from "../components/ui/button";
+import { Checkbox } from "../components/ui/checkbox";
+import { Input } from "../components/ui/input";
+import { Label } from "../components/ui/label";
+import WebhooksCard from "../components/settings/WebhooksCard";
+import { useApiServerStatus, useApiSettings, useRegenerateApiToken, useSaveApiSettings } from "../queries/settings";
+
+export default function SettingsPage() {
+    return (
+        <>
+            <h1 className="text-6xl font-semibold">Ajustes | lexApp</h1>
+            <p className="text-lg text-stone-400 pt-2">Configura la integración de lexApp con otras herramientas.</p>
+            <Separator className="my-2" />
+            <div className="grid grid-cols-2 gap-4 max-h-full overflow-auto">
+                <ApiSettingsCard />
+                <WebhooksCard />
+            </div>
+        </>
+    )
+}
+
+function ApiSettingsCard() {
+    const settings = useApiSettings()
+    const status = useApiServerStatus()
+    const saveSettings = useSaveApiSettings()
+    const regenerateToken = useRegenerateApiToken()
+
+    const [enabled, setEnabled] = useState(false)
+    const [addr, setAddr] = useState("")
+
+    useEffect(() => {
+        if (settings.data) {
+            setEnabled(settings.data.enabled)
+            setAddr(settings.data.addr)
+        }
+    }, [settings.data])
+
+    const onSave = () => {
+        saveSettings.mutate({ enabled, addr, token: settings.data?.token ?? "" })
+    }
+
+    return (
+        <Card>
+            <CardHeader>
+                <CardTitle className="text-2xl font-semibold">Servidor API</CardTitle>
+            </CardHeader>
+            <CardContent className="space-y-3">
+                <p className="text-stone-400 text-sm">
+                    Permite consultar los casos y sus acuerdos desde otras herramientas de la red.
+                    La descripción de la API está en <code>/api/v1/openapi.json</code>.
+                </p>
+                <div className="flex items-center gap-2">
+                    <Checkbox id="api-enabled" checked={enabled} onCheckedChange={c => setEnabled(c === true)} />
+                    <Label htmlFor="api-enabled">Iniciar el servidor con la aplicación</Label>
+                </div>
+                <div className="space-y-1">
+                    <Label htmlFor="api-addr">Dirección</Label>
+                    <Input id="api-addr" value={addr} onChange={e => setAddr(e.target.value)} placeholder="127.0.0.1:8787" />
+                    <p className="text-stone-400 text-xs">Usa la dirección de la red local, o 0.0.0.0, para acceder desde otros equipos.</p>
+                </div>
+                <div className="space-y-1">
+                    <Label>Token</Label>
+                    <div className="flex gap-2">
+                        <Input readOnly value={settings.data?.token || "Se genera al guardar"} />
+                        <Button
+                            variant="outline"
+                            disabled={!settings.data?.token}
+                            onClick={() => navigator.clipboard.writeText(settings.data!.token)}>
+                            Copiar
+                        </Button>
+                        <Button variant="outline" onClick={() => regenerateToken.mutate()}>Regenerar</Button>
+                    </div>
+                </div>
+                {status.data && (
+                    <p className="text-sm">
+                        {status.data.running
+                            ? <span className="text-green-400">En ejecución en http://{status.data.addr}/api/v1</span>
+                            : <span className="text-stone-400">Detenido</span>}
+                        {status.data.error && <span className="block text-red-400">{status.data.error}</span>}
+                    </p>
+                )}
+                {saveSettings.isError && <p className="text-red-400 text-sm">{String(saveSettings.error)}</p>}
+            </CardContent>
+            <CardFooter>
+                <Button onClick={onSave} disabled={saveSettings.isPending}>Guardar</Button>
+            </CardFooter>
+        </Card>
+    )
+}
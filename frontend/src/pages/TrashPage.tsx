@@ -0,0 +1,84 @@
+import { toast } from "sonner";
+import { LucideLoader, LucideRotateCcw, LucideTrash2 } from "lucide-react";
+import BasePageHeader from "@/components/layouts/BasePageHeader";
+import { Separator } from "../components/ui/separator";
+import { Button } from "../components/ui/button";
+import { db } from "../../wailsjs/go/models";
+import { useDeletedCases, usePurgeCase, useRestoreCase } from "../queries/cases";
+import { useCourtName } from "../queries/courts";
+import { formatDateToShortReadable } from "../lib/formatUtils";
+
+export default function TrashPage() {
+    const { data, isLoading, isError } = useDeletedCases()
+
+    return (
+        <>
+            <BasePageHeader
+                title="Papelera"
+                description="Casos eliminados. Se conservan con sus acuerdos hasta que los elimines permanentemente." />
+            <Separator className="my-2" />
+            {isLoading && <LucideLoader className="animate-spin" />}
+            {isError && <p className="text-red-400">Ocurrio un error al recuperar la papelera</p>}
+            {data?.length === 0 && <p className="text-stone-400">La papelera está vacía</p>}
+            {data && data.length > 0 && (
+                <div className="max-h-full overflow-auto">
+                    <table className="w-full text-sm text-left">
+                        <thead className="text-stone-400">
+                            <tr>
+                                <th className="p-1">Expediente</th>
+                                <th className="p-1">Juzgado</th>
+                                <th className="p-1">Alias</th>
+                                <th className="p-1">Eliminado</th>
+                                <th className="p-1"></th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {data.map(c => <TrashRow key={c.id} c={c} />)}
+                        </tbody>
+                    </table>
+                </div>
+            )}
+        </>
+    )
+}
+
+function TrashRow({ c }: { c: db.LexCase }) {
+    const courtName = useCourtName()
+    const restore = useRestoreCase()
+    const purge = usePurgeCase()
+
+    const onRestore = () => {
+        restore.mutate(c.id, {
+            onSuccess: () => toast.success(`Caso ${c.caseId} restaurado`),
+            onError: err => toast.error(`No se pudo restaurar el caso: ${err}`),
+        })
+    }
+
+    const onPurge = () => {
+        purge.mutate(c.id, {
+            onSuccess: () => toast.success(`Caso ${c.caseId} eliminado permanentemente`),
+            onError: err => {
+                if (!String(err).includes("cancelled")) {
+                    toast.error(`No se pudo eliminar el caso: ${err}`)
+                }
+            },
+        })
+    }
+
+    return (
+        <tr className="border-t border-stone-700">
+            <td className="p-1">{c.caseId}</td>
+            <td className="p-1">{courtName(c.caseType, c.region)}</td>
+            <td className="p-1">{c.alias || "-"}</td>
+            <td className="p-1">{c.deletedAt ? formatDateToShortReadable(new Date(c.deletedAt)) : "-"}</td>
+            <td className="p-1 flex gap-2 justify-end">
+                <Button size="sm" variant="outline" onClick={onRestore} disabled={restore.isPending}>
+                    <LucideRotateCcw /> Restaurar
+                </Button>
+                <Button size="sm" variant="destructive" onClick={onPurge} disabled={purge.isPending}>
+                    <LucideTrash2 /> Eliminar permanentemente
+                </Button>
+            </td>
+        </tr>
+    )
+}
@@ -0,0 +1,270 @@
+import { useState } from "react";
+import { useNavigate } from "react-router";
+import { toast } from "sonner";
+import { LucideEye, LucideLoader, LucideSearch } from "lucide-react";
+import { Separator } from "../components/ui/separator";
+import { Button } from "../components/ui/button";
+import { Checkbox } from "../components/ui/checkbox";
+import { Input } from "../components/ui/input";
+import { Label } from "../components/ui/label";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "../components/ui/select";
+import { db } from "../../wailsjs/go/models";
+import { courtKey, parseCourtKey, useCourtName, useCourts } from "../queries/courts";
+import {
+    useCheckWatchlist,
+    useCreateWatchItem,
+    useDeleteWatchItem,
+    usePromoteWatchItem,
+    useWatchItem,
+    useWatchlist,
+} from "../queries/watchlist";
+import { useStartBackfill } from "../queries/backfill";
+import { isCaseInTrashError, useRestoreTrashedCase } from "../queries/cases";
+import { formatDateToShortReadable } from "../lib/formatUtils";
+
+const formatDate = (date?: any) => date ? formatDateToShortReadable(new Date(date)) : "-"
+
+// Months of bulletins searched for the history of a promoted case
+const promotedBackfillMonths = 6
+
+export default function WatchlistPage() {
+    const [includePromoted, setIncludePromoted] = useState(false)
+    const [daysBack, setDaysBack] = useState(0)
+    const [selectedId, setSelectedId] = useState("")
+
+    const { data: items, isLoading } = useWatchlist(includePromoted)
+    const checkWatchlist = useCheckWatchlist()
+
+    const onCheck = () => {
+        checkWatchlist.mutate(daysBack, {
+            onSuccess: res => toast(`Se buscaron ${res.checked} expedientes, ${res.newHits.length} coincidencias nuevas`),
+            onError: err => toast.error(`La búsqueda falló: ${err}`),
+        })
+    }
+
+    return (
+        <>
+            <h1 className="text-6xl font-semibold">Seguimiento | lexApp</h1>
+            <p className="text-lg text-stone-400 pt-2">
+                Sigue expedientes antes de registrarlos como casos, p. ej. los asuntos de un posible cliente.
+            </p>
+            <Separator className="my-2" />
+            <div className="flex flex-col gap-4 max-h-full overflow-auto">
+                <NewWatchItemForm />
+
+                <div className="flex items-center gap-4">
+                    <Button onClick={onCheck} disabled={checkWatchlist.isPending}>
+                        {checkWatchlist.isPending ? <LucideLoader className="animate-spin" /> : <LucideSearch />}
+                        Buscar en los boletines
+                    </Button>
+                    <Label htmlFor="daysBack">Días hacia atrás</Label>
+                    <Input
+                        id="daysBack"
+                        type="number"
+                        min={0}
+                        className="w-20"
+                        value={daysBack}
+                        onChange={e => setDaysBack(Math.max(0, Number(e.target.value)))}
+                    />
+                    <div className="flex items-center gap-2 ml-auto">
+                        <Checkbox id="includePromoted" checked={includePromoted} onCheckedChange={c => setIncludePromoted(c === true)} />
+                        <Label htmlFor="includePromoted">Mostrar los ya registrados</Label>
+                    </div>
+                </div>
+
+                {isLoading && <LucideLoader className="animate-spin" />}
+                {items?.length === 0 && <p className="text-stone-400">No hay expedientes en seguimiento</p>}
+                {items && items.length > 0 && (
+                    <table className="text-sm text-left">
+                        <thead className="text-stone-400">
+                            <tr>
+                                <th className="p-1">Expediente</th>
+                                <th className="p-1">Juzgado</th>
+                                <th className="p-1">Nota</th>
+                                <th className="p-1">Coincidencias</th>
+                                <th className="p-1">Última coincidencia</th>
+                                <th className="p-1">Revisado</th>
+                                <th className="p-1"></th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {items.map(w => (
+                                <WatchItemRow
+                                    key={w.id}
+                                    item={w}
+                                    selected={w.id === selectedId}
+                                    onSelect={() => setSelectedId(w.id === selectedId ? "" : w.id)}
+                                />
+                            ))}
+                        </tbody>
+                    </table>
+                )}
+
+                {selectedId && <WatchHits id={selectedId} />}
+            </div>
+        </>
+    )
+}
+
+function NewWatchItemForm() {
+    const [caseId, setCaseId] = useState("")
+    const [court, setCourt] = useState("")
+    const [note, setNote] = useState("")
+
+    const { data: courts } = useCourts()
+    const createWatchItem = useCreateWatchItem()
+
+    const onSubmit = (e: React.FormEvent) => {
+        e.preventDefault()
+        const { region, caseType } = parseCourtKey(court)
+        createWatchItem.mutate({ caseId, caseType, region, note }, {
+            onSuccess: () => {
+                setCaseId("")
+                setNote("")
+            },
+        })
+    }
+
+    return (
+        <form onSubmit={onSubmit} className="flex flex-col gap-2">
+            <div className="flex items-end gap-2">
+                <div className="flex flex-col gap-1">
+                    <Label htmlFor="watchCaseId">Expediente</Label>
+                    <Input id="watchCaseId" className="w-40" placeholder="12/2024" value={caseId} onChange={e => setCaseId(e.target.value)} />
+                </div>
+                <div className="flex flex-col gap-1">
+                    <Label>Juzgado</Label>
+                    <Select value={court} onValueChange={setCourt}>
+                        <SelectTrigger className="w-96"><SelectValue placeholder="Selecciona el juzgado" /></SelectTrigger>
+                        <SelectContent>
+                            {courts?.map(c => (
+                                <SelectItem key={courtKey(c)} value={courtKey(c)}>
+                                    {c.name}
+                                </SelectItem>
+                            ))}
+                        </SelectContent>
+                    </Select>
+                </div>
+                <div className="flex flex-col gap-1 grow">
+                    <Label htmlFor="watchNote">Nota</Label>
+                    <Input id="watchNote" placeholder="Nombre del posible cliente" value={note} onChange={e => setNote(e.target.value)} />
+                </div>
+                <Button type="submit" disabled={!caseId || !court || createWatchItem.isPending}>
+                    <LucideEye /> Seguir
+                </Button>
+            </div>
+            {createWatchItem.isError && (
+                <p className="text-red-400">No se pudo agregar el expediente: {String(createWatchItem.error)}</p>
+            )}
+        </form>
+    )
+}
+
+function WatchItemRow({ item, selected, onSelect }: { item: db.WatchItem, selected: boolean, onSelect: () => void }) {
+    const navigate = useNavigate()
+    const courtName = useCourtName()
+    const deleteWatchItem = useDeleteWatchItem()
+    const promoteWatchItem = usePromoteWatchItem()
+    const restoreCase = useRestoreTrashedCase()
+    const startBackfill = useStartBackfill()
+
+    // The hits only cover the dates the item was checked, so the rest
+    // of the history of the case is left to a backfill
+    const backfill = (caseId: string) => {
+        const since = new Date()
+        since.setMonth(since.getMonth() - promotedBackfillMonths)
+        startBackfill.mutate({ caseIds: [caseId], since, until: new Date() }, {
+            onSuccess: () => toast.success("Recuperación iniciada, puedes seguir su avance en la lista de casos"),
+            onError: err => toast.error(`No se pudo iniciar la recuperación: ${err}`),
+        })
+    }
+
+    const onPromote = () => {
+        promoteWatchItem.mutate({ id: item.id, alias: item.note }, {
+            onSuccess: c => {
+                toast.success(`Caso ${c.caseId} registrado`, {
+                    description: `¿Recuperar sus acuerdos de los últimos ${promotedBackfillMonths} meses?`,
+                    action: { label: "Recuperar historial", onClick: () => backfill(c.id) },
+                })
+                navigate(`/casos/${c.id}`)
+            },
+            onError: err => {
+                if (isCaseInTrashError(err)) {
+                    toast.error(`El caso ${item.caseId} está en la papelera`, {
+                        description: "Restáuralo para agregarle los acuerdos encontrados",
+                        action: { label: "Restaurar", onClick: onRestore },
+                    })
+                    return
+                }
+                toast.error(`No se pudo registrar el caso: ${err}`)
+            },
+        })
+    }
+
+    // Promoting again once restored adds the hits to the case
+    const onRestore = () => {
+        restoreCase.mutate({ caseId: item.caseId, caseType: item.caseType, region: item.region }, {
+            onSuccess: () => onPromote(),
+            onError: err => toast.error(`No se pudo restaurar el caso: ${err}`),
+        })
+    }
+
+    return (
+        <tr className={`border-t border-stone-700 ${selected ? "bg-stone-800" : ""}`}>
+            <td className="p-1">{item.caseId}</td>
+            <td className="p-1">{courtName(item.caseType, item.region)}</td>
+            <td className="p-1">{item.note}</td>
+            <td className="p-1">{item.hitCount}</td>
+            <td className="p-1">{formatDate(item.lastHitAt)}</td>
+            <td className="p-1">{formatDate(item.lastCheckedAt)}</td>
+            <td className="p-1 flex gap-2 justify-end">
+                <Button size="sm" variant="ghost" onClick={onSelect} disabled={item.hitCount === 0}>
+                    {selected ? "Ocultar" : "Ver acuerdos"}
+                </Button>
+                {item.promotedCaseId ? (
+                    <>
+                        <Button
+                            size="sm"
+                            variant="ghost"
+                            onClick={() => backfill(item.promotedCaseId)}
+                            disabled={startBackfill.isPending}>
+                            Recuperar historial
+                        </Button>
+                        <Button size="sm" variant="outline" onClick={() => navigate(`/casos/${item.promotedCaseId}`)}>
+                            Ver caso
+                        </Button>
+                    </>
+                ) : (
+                    <>
+                        <Button size="sm" onClick={onPromote} disabled={promoteWatchItem.isPending}>
+                            Registrar caso
+                        </Button>
+                        <Button size="sm" variant="destructive" onClick={() => deleteWatchItem.mutate(item.id)}>
+                            Quitar
+                        </Button>
+                    </>
+                )}
+            </td>
+        </tr>
+    )
+}
+
+function WatchHits({ id }: { id: string }) {
+    const { data: item, isLoading } = useWatchItem(id)
+
+    if (isLoading) {
+        return <LucideLoader className="animate-spin" />
+    }
+
+    return (
+        <div className="rounded-lg border border-stone-700 p-3 space-y-2">
+            <h2 className="text-xl font-semibold">Acuerdos de {item?.caseId}</h2>
+            {item?.hits.map(h => (
+                <div key={h.id} className="text-sm">
+                    <p className="text-stone-400">{formatDate(h.date)} · {h.nature}</p>
+                    <p className="whitespace-pre-line">{h.content}</p>
+                </div>
+            ))}
+        </div>
+    )
+}
@@ -1,22 +1,36 @@
 import CaseAccordCard from "@/components/cases/CaseAccordCard";
 import SearchUpdatesDialog from "@/components/cases/SearchUpdatesDialog";
+import BackfillDialog from "@/components/cases/BackfillDialog";
+import CaseTimeline from "@/components/cases/CaseTimeline";
+import CaseHearings from "@/components/cases/CaseHearings";
 import BasePageHeader from "@/components/layouts/BasePageHeader";
 import { Button } from "@/components/ui/button";
 import { Separator } from "@/components/ui/separator";
-import { CaseType, caseTypeToName } from "@/lib/caseTypeNames";
 import { cn } from "@/lib/utils";
-import { useCaseWithAccords, useUpdateCaseAccords } from "@/queries/cases";
-import { LucideLoader } from "lucide-react";
+import {
+    useArchiveCase,
+    useCaseWithAccords,
+    isExportCancelledError,
+    useDeleteCase,
+    useExportCaseReport,
+    useRestoreCase,
+    useUnarchiveCase,
+    useUpdateCaseAccords,
+} from "@/queries/cases";
+import { useCourt, useCourtName } from "@/queries/courts";
+import { LucideArchive, LucideArchiveRestore, LucideFileText, LucideLoader, LucideRotateCcw, LucideTrash2 } from "lucide-react";
 import { useState } from "react";
-import { useParams } from "react-router";
+import { useNavigate, useParams } from "react-router";
 import { toast } from "sonner";
 import { db } from "wailsjs/go/models";
 
 export default function CaseDetailPage() {
     const { caseUUID } = useParams()
+    const [view, setView] = useState<"accords" | "timeline">("accords")
     const { data, status } = useCaseWithAccords(String(caseUUID), 15)
     const updateAccords = useUpdateCaseAccords(String(caseUUID))
     const blockAction = updateAccords.status === "pending"
+    const courtName = useCourtName()
 
     if (status === "pending") {
         return <div className="flex flex-col items-center justify-center gap-4 grow basis-full">
@@ -54,12 +68,13 @@ export default function CaseDetailPage() {
     return (
         <>
             <BasePageHeader
-                title={`Caso ${data.caseId} - ${caseTypeToName(data.caseType as CaseType)}`}
+                title={`Caso ${data.caseId} - ${courtName(data.caseType, data.region)}`}
                 description={
                     <>
                         {data.alias === ""
                             || <p className="text-2xl text-stone-200 font-medium"> {data.alias} </p>}
-                        <p className="text-lg text-stone-400 pt-2">Acuerdos y detalles del caso No. {data.caseId} del juzgado {caseTypeToName(data.caseType as CaseType)}</p>
+                        <p className="text-lg text-stone-400 pt-2">Acuerdos y detalles del caso No. {data.caseId} del {courtName(data.caseType, data.region)}</p>
+                        <CourtDetails caseType={data.caseType} region={data.region} />
                     </>
                 } />
             <Separator className="my-2" />
@@ -83,27 +98,114 @@ export default function CaseDetailPage() {
                     caseUUID={String(caseUUID)}
                     caseId={data.caseId}
                     caseType={data.caseType}
+                    region={data.region}
                     blockAction={blockAction} />
+                <BackfillDialog caseUUID={String(caseUUID)} blockAction={blockAction} />
+                <CaseReportButton id={data.id} />
+                <CaseStateActions data={data} />
             </div>
             <Separator className="my-2" />
             <CaseDetails data={data} />
             <Separator className="my-2" />
+            <CaseHearings caseUUID={String(caseUUID)} />
+            <Separator className="my-2" />
             <div className="grid grid-rows-[auto_1fr] flex-1 gap-2 overflow-hidden">
-                <h2 className="text-2xl text-stone-200">Acuerdos</h2>
-                <div className="row-start-2 row-span-1 grid grid-cols-3 grid-rows-cards auto-rows-auto items-start gap-4 h-full overflow-auto">
-                    {
-                        data.accords == null || data.accords.length == 0
-                            ? <div className="col-span-full self-center justify-self-center py-24">
-                                <p className="text-stone-400 text-2xl font-semibold">No se encontraron acuerdos para el caso</p>
-                            </div>
-                            : data.accords.map(c => (<CaseAccordCard key={c.id} accord={c} />))
-                    }
+                <div className="flex items-center gap-2">
+                    <h2 className="text-2xl text-stone-200">{view === "accords" ? "Acuerdos" : "Línea de tiempo"}</h2>
+                    <Button
+                        variant="ghost"
+                        className="ml-auto"
+                        onClick={() => setView(view === "accords" ? "timeline" : "accords")}>
+                        {view === "accords" ? "Ver línea de tiempo y notas" : "Ver acuerdos"}
+                    </Button>
                 </div>
+                {view === "timeline"
+                    ? <CaseTimeline caseUUID={String(caseUUID)} accordCount={15} />
+                    : <div className="row-start-2 row-span-1 grid grid-cols-3 grid-rows-cards auto-rows-auto items-start gap-4 h-full overflow-auto">
+                        {
+                            data.accords == null || data.accords.length == 0
+                                ? <div className="col-span-full self-center justify-self-center py-24">
+                                    <p className="text-stone-400 text-2xl font-semibold">No se encontraron acuerdos para el caso</p>
+                                </div>
+                                : data.accords.map(c => (<CaseAccordCard key={c.id} accord={c} />))
+                        }
+                    </div>}
             </div>
         </>
     )
 }
 
+function CaseReportButton({ id }: { id: string }) {
+    const exportReport = useExportCaseReport()
+
+    const onClick = () => {
+        exportReport.mutate(id, {
+            onSuccess: path => toast.success(`Reporte guardado en ${path}`),
+            onError: err => {
+                if (!isExportCancelledError(err)) {
+                    toast.error(`No se pudo generar el reporte: ${err}`)
+                }
+            },
+        })
+    }
+
+    return (
+        <Button size="lg" variant="secondary" className="text-base font-bold" onClick={onClick} disabled={exportReport.isPending}>
+            {exportReport.isPending ? <LucideLoader className="animate-spin" /> : <><LucideFileText /> Reporte PDF</>}
+        </Button>
+    )
+}
+
+// Archiving and moving the case to the trash, or undoing either
+function CaseStateActions({ data }: { data: db.LexCase }) {
+    const navigate = useNavigate()
+    const archive = useArchiveCase()
+    const unarchive = useUnarchiveCase()
+    const deleteCase = useDeleteCase()
+    const restore = useRestoreCase()
+
+    const onError = (err: unknown) => toast.error(`No se pudo cambiar el estado del caso: ${err}`)
+
+    if (data.deletedAt) {
+        return (
+            <div className="ml-auto flex items-center gap-2">
+                <p className="text-red-400">En la papelera</p>
+                <Button variant="outline" onClick={() => restore.mutate(data.id, { onError })} disabled={restore.isPending}>
+                    <LucideRotateCcw /> Restaurar
+                </Button>
+            </div>
+        )
+    }
+
+    const onDelete = () => {
+        deleteCase.mutate(data.id, {
+            onSuccess: () => {
+                toast.success(`Caso ${data.caseId} movido a la papelera`)
+                navigate("/casos")
+            },
+            onError,
+        })
+    }
+
+    return (
+        <div className="ml-auto flex items-center gap-2">
+            {data.archivedAt
+                ? <>
+                    <p className="text-stone-400">Archivado</p>
+                    <Button variant="outline" onClick={() => unarchive.mutate(data.id, { onError })} disabled={unarchive.isPending}>
+                        <LucideArchiveRestore /> Desarchivar
+                    </Button>
+                </>
+                : <Button variant="outline" onClick={() => archive.mutate(data.id, { onError })} disabled={archive.isPending}>
+                    <LucideArchive /> Archivar
+                </Button>}
+            <Button variant="destructive" onClick={onDelete} disabled={deleteCase.isPending}>
+                <LucideTrash2 /> Eliminar
+            </Button>
+        </div>
+    )
+}
+
 function CaseDetails({ data }: { data: db.LexCase }) {
     const [showFullId, setShowFullId] = useState(false)
     return (
@@ -161,3 +263,35 @@ function CaseDetails({ data }: { data: db.LexCase }) {
         </div>
     )
 }
+
+const matterNames: Record<string, string> = {
+    civil: "Civil",
+    familiar: "Familiar",
+    mercantil: "Mercantil",
+    laboral: "Laboral",
+    otro: "Otra",
+}
+
+function CourtDetails({ caseType, region }: { caseType: string, region: string }) {
+    const court = useCourt(caseType, region)
+    if (!court) {
+        return null
+    }
+
+    const details = [
+        { label: "Materia", value: matterNames[court.matter] ?? court.matter },
+        { label: "Juez", value: court.judge },
+        { label: "Domicilio", value: court.address },
+        { label: "Horario", value: court.schedule },
+    ].filter(d => d.value)
+
+    return (
+        <div className="flex flex-wrap gap-x-6 gap-y-1 pt-2 text-stone-400">
+            {details.map(d => (
+                <p key={d.label}>
+                    <span className="font-semibold text-stone-300">{d.label}:</span> {d.value}
+                </p>
+            ))}
+        </div>
+    )
+}
@@ -1,19 +1,31 @@
-import { Link, useLocation } from "react-router";
-import CaseFilters from "../../components/cases/CaseFilters";
+import { Link, useLocation, useNavigate } from "react-router";
+import { InfiniteData, UseInfiniteQueryResult } from "@tanstack/react-query";
+import { toast } from "sonner";
+import CaseFilters, { CaseFilters as CaseFiltersValues } from "../../components/cases/CaseFilters";
 import { Separator } from "../../components/ui/separator";
 import { useState, useRef, useEffect } from "react";
-import { useCases } from "../../queries/cases";
-import { LucideLoader } from "lucide-react";
+import { FindCaseOptions, useCasePages } from "../../queries/cases";
+import { useCreateSavedSearch, useDeleteSavedSearch, useSavedSearch, useSavedSearchPages } from "../../queries/savedSearches";
+import { LucideLoader, LucideSave, LucideTrash } from "lucide-react";
 import CaseCard from "../../components/cases/CaseCard";
 import { CardContent } from "../../components/ui/card";
 import { formatDateToShortReadable } from "../../lib/formatUtils";
 import { db } from "../../../wailsjs/go/models";
 import { Button } from "../../components/ui/button";
+import { Input } from "../../components/ui/input";
 import BasePageHeader from "@/components/layouts/BasePageHeader";
 import GeneralUpdatesDialog from "@/components/cases/GeneralUpdatesDialog";
+import BackfillDialog from "@/components/cases/BackfillDialog";
+import BackfillJobs from "@/components/cases/BackfillJobs";
+import ExportDialog from "@/components/cases/ExportDialog";
+import ClientReportDialog from "@/components/cases/ClientReportDialog";
+
+const casePageSize = 60
 
 export default function CasesPage() {
     const { params, setParam } = useCasesSearchParams()
+    const savedId = new URLSearchParams(useLocation().search).get("saved") ?? ""
+    const { data: savedSearch } = useSavedSearch(savedId)
     const blockAction = false
 
     return (
@@ -25,42 +37,112 @@ export default function CasesPage() {
                     <Link to="/casos/nuevo">Registrar Caso</Link>
                 </Button>
                 <GeneralUpdatesDialog blockAction={blockAction} filters={params} />
+                <BackfillDialog blockAction={blockAction} filters={params} />
+                <ExportDialog
+                    blockAction={blockAction || (savedId !== "" && !savedSearch)}
+                    opts={savedId ? (savedSearch?.options ?? {}) : filtersToOptions(params)} />
+                <ClientReportDialog blockAction={blockAction} />
             </div>
+            <BackfillJobs />
             <Separator className="my-2" />
-            <CaseFilters setFilter={setParam} filters={params} />
-            <Separator className="my-2" />
-            <Listing filters={params} />
+            {savedId
+                ? <SavedSearchListing id={savedId} />
+                : <>
+                    <CaseFilters setFilter={setParam} filters={params} />
+                    <SaveSearchForm filters={params} />
+                    <Separator className="my-2" />
+                    <Listing filters={params} />
+                </>}
         </>
     )
 }
 
-function Listing(
-    {
-        filters
-    }: {
-        filters: {
-            search: string;
-            caseNo: string;
-            caseYear: string;
-            caseType: string;
-        }
-    } & React.PropsWithChildren
-) {
-    const { data, isSuccess, isLoading, isError } = useCases({
-        IncludeAccords: true,
-        MaxAccords: 1,
+function filtersToOptions(filters: CaseFiltersValues): FindCaseOptions {
+    return {
         CaseNo: filters.caseNo,
         CaseYear: filters.caseYear,
         CaseType: filters.caseType,
+        IncludeArchived: filters.archived === "1",
         search: filters.search,
-    })
+    }
+}
 
-    if (isError || !isSuccess) {
-        return <div className="flex h-36 items-center justify-center">
-            <p className="text-stone-200 text-xl font-semibold">Ocurrio un error al recuperar los casos</p>
-        </div>
+function Listing({ filters }: { filters: CaseFiltersValues }) {
+    const query = useCasePages({
+        ...filtersToOptions(filters),
+        IncludeAccords: true,
+        MaxAccords: 1,
+    }, casePageSize)
+
+    return <CaseGrid query={query} />
+}
+
+function SaveSearchForm({ filters }: { filters: CaseFiltersValues }) {
+    const [name, setName] = useState("")
+    const navigate = useNavigate()
+    const createSavedSearch = useCreateSavedSearch()
+
+    const onSubmit = (e: React.FormEvent) => {
+        e.preventDefault()
+        createSavedSearch.mutate({ name, opts: filtersToOptions(filters) }, {
+            onSuccess: ss => {
+                setName("")
+                toast.success(`Búsqueda "${ss.name}" guardada`)
+                navigate(`/casos?saved=${ss.id}`)
+            },
+            onError: err => toast.error(`No se pudo guardar la búsqueda: ${err}`),
+        })
     }
 
+    return (
+        <form onSubmit={onSubmit} className="flex items-center gap-2 pt-2">
+            <Input
+                className="max-w-80"
+                placeholder="Nombre para guardar estos filtros"
+                value={name}
+                onChange={e => setName(e.target.value)} />
+            <Button type="submit" variant="secondary" disabled={name.trim() === "" || createSavedSearch.isPending}>
+                <LucideSave /> Guardar búsqueda
+            </Button>
+        </form>
+    )
+}
+
+function SavedSearchListing({ id }: { id: string }) {
+    const navigate = useNavigate()
+    const { data: savedSearch } = useSavedSearch(id)
+    const deleteSavedSearch = useDeleteSavedSearch()
+    const query = useSavedSearchPages(id, casePageSize)
+
+    const onDelete = () => {
+        deleteSavedSearch.mutate(id, {
+            onSuccess: () => navigate("/casos"),
+            onError: err => toast.error(`No se pudo eliminar la búsqueda: ${err}`),
+        })
+    }
+
+    return (
+        <>
+            <div className="flex items-center gap-2">
+                <h4 className="text-xl font-medium">Búsqueda guardada: {savedSearch?.name}</h4>
+                <Button variant="ghost" className="ml-auto" asChild>
+                    <Link to="/casos">Ver todos los casos</Link>
+                </Button>
+                <Button variant="destructive" onClick={onDelete} disabled={deleteSavedSearch.isPending}>
+                    <LucideTrash /> Eliminar búsqueda
+                </Button>
+            </div>
+            <Separator className="my-2" />
+            <CaseGrid query={query} />
+        </>
+    )
+}
+
+// The cases loaded so far by a paged query, with a button
+// loading the next page
+function CaseGrid({ query }: { query: UseInfiniteQueryResult<InfiniteData<db.CasePage>> }) {
+    const { data, isLoading, isError, hasNextPage, fetchNextPage, isFetchingNextPage } = query
+
     if (isLoading) {
         return <div className="flex flex-col h-36 items-center justify-center">
             <LucideLoader className="animate-spin" />
@@ -68,12 +150,29 @@ function Listing(
         </div>
     }
 
-    return <div className="flex-1 grid grid-cols-3 auto-rows-[14rem] gap-4 max-h-full overflow-auto">
-        {data.length == 0
-            ? <div className="col-span-full self-center justify-self-center py-24">
-                <p className="text-stone-400 text-2xl font-semibold">No se encontraron casos para la búsqueda ingresada</p>
-            </div>
-            : data.map(c => (<ListingCase key={c.id} c={c} />))}
+    if (isError || !data) {
+        return <div className="flex h-36 items-center justify-center">
+            <p className="text-stone-200 text-xl font-semibold">Ocurrio un error al recuperar los casos</p>
+        </div>
+    }
+
+    const cases = data.pages.flatMap(p => p.cases)
+    const total = data.pages[0]?.total ?? 0
+
+    return <div className="flex-1 flex flex-col gap-2 max-h-full overflow-auto">
+        <p className="text-stone-400 text-sm">Mostrando {cases.length} de {total} casos</p>
+        <div className="grid grid-cols-3 auto-rows-[14rem] gap-4">
+            {cases.length == 0
+                ? <div className="col-span-full self-center justify-self-center py-24">
+                    <p className="text-stone-400 text-2xl font-semibold">No se encontraron casos para la búsqueda ingresada</p>
+                </div>
+                : cases.map(c => (<ListingCase key={c.id} c={c} />))}
+        </div>
+        {hasNextPage && (
+            <Button variant="secondary" className="self-center" onClick={() => fetchNextPage()} disabled={isFetchingNextPage}>
+                {isFetchingNextPage ? <LucideLoader className="animate-spin" /> : "Cargar más"}
+            </Button>
+        )}
     </div>
 }
 
@@ -147,6 +246,7 @@ function useCasesSearchParams() {
             caseNo: params.get("caseNo") || "",
             caseYear: params.get("caseYear") || "",
             caseType: params.get("caseType") || "",
+            archived: params.get("archived") || "",
         },
         setParam,
     }
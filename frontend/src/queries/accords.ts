@@ -0,0 +1,39 @@
+import { useQuery } from "@tanstack/react-query";
+import { SearchAccords } from "../../wailsjs/go/controllers/CaseController"
+import { db } from "../../wailsjs/go/models";
+
+const accordQueryKeys = {
+    all: ["accords"] as const,
+    search: (opts: Partial<db.AccordSearchOptions>) => [...accordQueryKeys.all, "search", opts] as const,
+}
+
+// Markers wrapping the matches within the snippet of a result
+export const snippetMatchStart = "\u0002"
+export const snippetMatchEnd = "\u0003"
+
+// Full text search over the content of the accords, best matches first
+export function useAccordSearch(opts: Partial<db.AccordSearchOptions>) {
+    return useQuery({
+        queryKey: accordQueryKeys.search(opts),
+        queryFn: async () => {
+            return await SearchAccords(opts as db.AccordSearchOptions)
+        },
+        enabled: (opts.Search ?? "").trim() !== "",
+    })
+}
+
+// Splits the snippet of a result into its plain and matched parts
+export function splitSnippet(snippet: string): { text: string, match: boolean }[] {
+    const parts: { text: string, match: boolean }[] = []
+    for (const chunk of snippet.split(snippetMatchStart)) {
+        const end = chunk.indexOf(snippetMatchEnd)
+        if (end === -1) {
+            parts.push({ text: chunk, match: false })
+            continue
+        }
+        parts.push({ text: chunk.slice(0, end), match: true })
+        parts.push({ text: chunk.slice(end + snippetMatchEnd.length), match: false })
+    }
+
+    return parts.filter(p => p.text !== "")
+}
@@ -0,0 +1,93 @@
+import { useEffect } from "react";
+import { useMutation, useQuery } from "@tanstack/react-query";
+import {
+    DeleteBackfillJob,
+    FindBackfillJobs,
+    FindCasesAndBackfill,
+    PauseBackfill,
+    ResumeBackfill,
+    StartBackfill,
+} from "../../wailsjs/go/controllers/BackfillController"
+import { db } from "../../wailsjs/go/models";
+import { EventsOn } from "../../wailsjs/runtime/runtime";
+import queryClient from "@/QueryClient";
+import { FindCaseOptions } from "./cases";
+
+// Emitted by the BackfillController as jobs advance
+const backfillProgressEvent = "backfill:progress"
+
+const backfillQueryKeys = {
+    all: ["backfill"] as const,
+    list: () => [...backfillQueryKeys.all, "list"] as const,
+}
+
+const invalidateBackfill = () => queryClient.invalidateQueries({ queryKey: backfillQueryKeys.all })
+
+// Lists the jobs, keeping the running ones up to date through
+// the progress events
+export function useBackfillJobs() {
+    useEffect(() => {
+        return EventsOn(backfillProgressEvent, (job: db.BackfillJob) => {
+            queryClient.setQueryData<db.BackfillJob[]>(backfillQueryKeys.list(), jobs => {
+                if (!jobs?.some(j => j.id === job.id)) {
+                    return [job, ...(jobs ?? [])]
+                }
+                return jobs.map(j => j.id === job.id ? job : j)
+            })
+            if (job.status === "done") {
+                queryClient.invalidateQueries({ queryKey: ["cases"] })
+            }
+        })
+    }, [])
+
+    return useQuery({
+        queryKey: backfillQueryKeys.list(),
+        queryFn: async () => {
+            return await FindBackfillJobs()
+        }
+    })
+}
+
+export function useStartBackfill() {
+    return useMutation({
+        mutationFn: async ({ caseIds, since, until }: { caseIds: string[], since: Date, until: Date }) => {
+            return await StartBackfill(caseIds, since, until)
+        },
+        onSettled: invalidateBackfill,
+    })
+}
+
+export function useFindCasesAndBackfill() {
+    return useMutation({
+        mutationFn: async ({ findOpts, since, until }: { findOpts: FindCaseOptions, since: Date, until: Date }) => {
+            return await FindCasesAndBackfill(findOpts as db.FindCaseOptions, since, until)
+        },
+        onSettled: invalidateBackfill,
+    })
+}
+
+export function useResumeBackfill() {
+    return useMutation({
+        mutationFn: async (id: string) => {
+            return await ResumeBackfill(id)
+        },
+        onSettled: invalidateBackfill,
+    })
+}
+
+export function usePauseBackfill() {
+    return useMutation({
+        mutationFn: async (id: string) => {
+            return await PauseBackfill(id)
+        },
+    })
+}
+
+export function useDeleteBackfillJob() {
+    return useMutation({
+        mutationFn: async (id: string) => {
+            return await DeleteBackfillJob(id)
+        },
+        onSettled: invalidateBackfill,
+    })
+}
@@ -0,0 +1,20 @@
+import { useQuery } from "@tanstack/react-query";
+import { ReadBulletin } from "../../wailsjs/go/controllers/AccordUpdaterCtl"
+
+export const bulletinQueryKeys = {
+    all: ["bulletins"] as const,
+    bulletin: (region: string, caseType: string, date: string) => [...bulletinQueryKeys.all, region, caseType, date] as const,
+}
+
+// Every row of the list published for the case type on the date,
+// as YYYY-MM-DD
+export function useBulletin(region: string, caseType: string, date: string) {
+    return useQuery({
+        queryKey: bulletinQueryKeys.bulletin(region, caseType, date),
+        queryFn: async () => {
+            return await ReadBulletin(region, caseType, new Date(date + "T00:00"))
+        },
+        enabled: caseType !== "" && date !== "",
+        retry: false,
+    })
+}
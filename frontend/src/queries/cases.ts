@@ -1,5 +1,23 @@
-import { useMutation, useQuery } from "@tanstack/react-query";
-import { CreateCase, FindCaseById, FindCases, FindCaseWithAccords, UpdateCase } from "../../wailsjs/go/controllers/CaseController"
+import { useInfiniteQuery, useMutation, useQuery } from "@tanstack/react-query";
+import {
+    ArchiveCase,
+    CreateCase,
+    CreateRegionCase,
+    DeleteCase,
+    ExportCaseReport,
+    ExportCases,
+    ExportClientReport,
+    FindCaseById,
+    FindCases,
+    FindCaseWithAccords,
+    FindDeletedCases,
+    FindStaleCases,
+    PurgeCase,
+    RestoreCase,
+    RestoreTrashedCase,
+    UnarchiveCase,
+    UpdateCase,
+} from "../../wailsjs/go/controllers/CaseController"
 import { FindUpdates as FindCaseUpdates, Update as UpdateCaseAccords, FindCasesAndUpdate } from "../../wailsjs/go/controllers/AccordUpdaterCtl"
 import { db } from "../../wailsjs/go/models";
 import queryClient from "@/QueryClient";
@@ -8,18 +26,23 @@ export type FindCaseOptions = Partial<db.FindCaseOptions> & {
     search?: string;
 }
 
-const caseQueryKeys = {
+export const caseQueryKeys = {
     all: ["cases"] as const,
     lists: () => [...caseQueryKeys.all, "list"] as const,
     list: (filters?: FindCaseOptions) => [...caseQueryKeys.lists(), filters] as const,
+    pages: (filters?: FindCaseOptions) => [...caseQueryKeys.lists(), "pages", filters] as const,
     //listWith: (filters: CaseFilters) => [...caseKeys.lists(), filters] as const,
     details: () => [...caseQueryKeys.all, "detail"] as const,
     detail: (id: string) => [...caseQueryKeys.details(), id] as const,
 
+    stale: (limit: number) => [...caseQueryKeys.all, "stale", limit] as const,
+    trash: () => [...caseQueryKeys.all, "trash"] as const,
+
     detailsAndAccords: () => [...caseQueryKeys.details(), "accords"] as const,
     detailAndAccords: (id: string, accordCount: number) => [...caseQueryKeys.detailsAndAccords(), id, accordCount] as const
 }
 
+// A single page of the cases matching the filters, with their total
 export function useCases(filters: FindCaseOptions) {
     return useQuery({
         queryKey: caseQueryKeys.list(filters),
@@ -29,6 +52,37 @@ export function useCases(filters: FindCaseOptions) {
     })
 }
 
+// Loads the cases matching the filters a page at a time,
+// following the cursor of the last page loaded
+export function useCasePages(filters: FindCaseOptions, pageSize: number) {
+    return useInfiniteQuery({
+        queryKey: caseQueryKeys.pages({ ...filters, Limit: pageSize }),
+        queryFn: async ({ pageParam }) => {
+            return await FindCases({ ...filters, Limit: pageSize, Cursor: pageParam } as db.FindCaseOptions)
+        },
+        initialPageParam: "",
+        getNextPageParam: lastPage => lastPage.nextCursor || undefined,
+    })
+}
+
+export function useDeletedCases() {
+    return useQuery({
+        queryKey: caseQueryKeys.trash(),
+        queryFn: async () => {
+            return await FindDeletedCases()
+        }
+    })
+}
+
+export function useStaleCases(limit: number) {
+    return useQuery({
+        queryKey: caseQueryKeys.stale(limit),
+        queryFn: async () => {
+            return await FindStaleCases(limit)
+        }
+    })
+}
+
 export function useCase(id: string) {
     return useQuery({
         queryKey: caseQueryKeys.detail(id),
@@ -61,6 +115,19 @@ export function useCreateCase() {
     })
 }
 
+export function useCreateRegionCase() {
+    return useMutation({
+        mutationFn: ({ caseId, caseType, region, alias }: CreateCaseParams & { region: string }) => {
+            return CreateRegionCase(caseId, caseType, region, alias || "")
+        },
+        onSuccess: () => {
+            queryClient.invalidateQueries({
+                queryKey: caseQueryKeys.lists()
+            })
+        }
+    })
+}
+
 type UpdateCaseParams = {
     id: string;
     caseData: Partial<db.LexCase>;
@@ -119,3 +186,92 @@ export function useFindAndUpdateCaseAccords() {
         }
     })
 }
+
+// Archiving, trashing and restoring change which listings a case
+// shows up in, so every case query is refreshed after them
+function useCaseStateMutation(mutationFn: (id: string) => Promise<void>) {
+    return useMutation({
+        mutationFn,
+        onSuccess: () => {
+            queryClient.invalidateQueries({
+                queryKey: caseQueryKeys.all
+            })
+        }
+    })
+}
+
+export function useArchiveCase() {
+    return useCaseStateMutation(ArchiveCase)
+}
+
+export function useUnarchiveCase() {
+    return useCaseStateMutation(UnarchiveCase)
+}
+
+export function useDeleteCase() {
+    return useCaseStateMutation(DeleteCase)
+}
+
+export function useRestoreCase() {
+    return useCaseStateMutation(RestoreCase)
+}
+
+// Asks for confirmation through a native dialog before purging
+export function usePurgeCase() {
+    return useCaseStateMutation(PurgeCase)
+}
+
+// Registering a case whose number is held by a case in the trash
+// fails with this error, see useRestoreTrashedCase
+export function isCaseInTrashError(err: unknown) {
+    return String(err).includes("case is in the trash")
+}
+
+export function useRestoreTrashedCase() {
+    return useMutation({
+        // Without a region the case is looked up in the default one
+        mutationFn: ({ caseId, caseType, region }: { caseId: string, caseType: string, region?: string }) => {
+            return RestoreTrashedCase(caseId, caseType, region || "")
+        },
+        onSuccess: () => {
+            queryClient.invalidateQueries({
+                queryKey: caseQueryKeys.all
+            })
+        }
+    })
+}
+
+// Exporting asks where to save the file through a native dialog,
+// cancelling it fails with this error
+export function isExportCancelledError(err: unknown) {
+    return String(err).includes("export was cancelled")
+}
+
+type ExportCasesParams = {
+    opts: FindCaseOptions;
+    format: string;
+    includeAccords: boolean;
+}
+export function useExportCases() {
+    return useMutation({
+        mutationFn: ({ opts, format, includeAccords }: ExportCasesParams) => {
+            return ExportCases(opts as db.FindCaseOptions, format, includeAccords)
+        }
+    })
+}
+
+export function useExportCaseReport() {
+    return useMutation({
+        mutationFn: (id: string) => {
+            return ExportCaseReport(id)
+        }
+    })
+}
+
+export function useExportClientReport() {
+    return useMutation({
+        mutationFn: (clientId: string) => {
+            return ExportClientReport(clientId)
+        }
+    })
+}
@@ -0,0 +1,16 @@
+import { useQuery } from "@tanstack/react-query";
+import { FindAllClients } from "../../wailsjs/go/controllers/ClientController"
+
+const clientQueryKeys = {
+    all: ["clients"] as const,
+    list: (search: string) => [...clientQueryKeys.all, "list", search] as const,
+}
+
+export function useClients(search: string) {
+    return useQuery({
+        queryKey: clientQueryKeys.list(search),
+        queryFn: async () => {
+            return await FindAllClients(search)
+        }
+    })
+}
@@ -0,0 +1,54 @@
+import { useQuery } from "@tanstack/react-query";
+import { FindCourts } from "../../wailsjs/go/controllers/CourtController"
+import { regions } from "../../wailsjs/go/models";
+import { CaseType, caseTypeToName } from "@/lib/caseTypeNames";
+
+const courtQueryKeys = {
+    all: ["courts"] as const,
+    list: () => [...courtQueryKeys.all, "list"] as const,
+}
+
+const defaultRegion = "MX_DGO_DGO"
+
+export function useCourts() {
+    return useQuery({
+        queryKey: courtQueryKeys.list(),
+        queryFn: async () => {
+            return await FindCourts()
+        },
+        // The catalog only changes when the app is restarted
+        staleTime: Infinity,
+    })
+}
+
+// Returns the court of the case type, undefined while the catalog loads
+// or when the region has no court for it
+export function useCourt(caseType: string, region?: string): regions.Court | undefined {
+    const { data } = useCourts()
+
+    return data?.find(c => c.caseType === caseType && c.region === (region || defaultRegion))
+}
+
+// Returns a function giving the full court name of a case type,
+// falling back to the short names until the catalog loads
+export function useCourtName() {
+    const { data } = useCourts()
+
+    return (caseType: string, region?: string) => {
+        const court = data?.find(c => c.caseType === caseType && c.region === (region || defaultRegion))
+
+        return court?.name ?? caseTypeToName(caseType as CaseType)
+    }
+}
+
+const courtKeySeparator = "|"
+
+// Identifies a court in selects, whose values must be strings
+export function courtKey(court: { region: string, caseType: string }) {
+    return court.region + courtKeySeparator + court.caseType
+}
+
+export function parseCourtKey(key: string): { region: string, caseType: string } {
+    const [region, caseType] = key.split(courtKeySeparator)
+    return { region, caseType }
+}
@@ -0,0 +1,45 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+import {
+    CreateCaseHearing,
+    DeleteCaseHearing,
+    FindUpcomingHearings,
+} from "../../wailsjs/go/controllers/CaseController"
+import queryClient from "@/QueryClient";
+import { caseQueryKeys } from "./cases";
+
+const hearingQueryKeys = {
+    all: () => [...caseQueryKeys.details(), "hearings"] as const,
+    upcoming: (caseId: string) => [...hearingQueryKeys.all(), caseId] as const,
+}
+
+const invalidateHearings = () => queryClient.invalidateQueries({ queryKey: hearingQueryKeys.all() })
+
+export function useUpcomingHearings(caseId: string) {
+    return useQuery({
+        queryKey: hearingQueryKeys.upcoming(caseId),
+        queryFn: async () => {
+            return await FindUpcomingHearings(caseId)
+        }
+    })
+}
+
+type CreateCaseHearingParams = {
+    caseId: string;
+    date: Date;
+    description: string;
+}
+export function useCreateCaseHearing() {
+    return useMutation({
+        mutationFn: ({ caseId, date, description }: CreateCaseHearingParams) => {
+            return CreateCaseHearing(caseId, date, description)
+        },
+        onSuccess: invalidateHearings,
+    })
+}
+
+export function useDeleteCaseHearing() {
+    return useMutation({
+        mutationFn: DeleteCaseHearing,
+        onSuccess: invalidateHearings,
+    })
+}
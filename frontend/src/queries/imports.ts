@@ -0,0 +1,57 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+import { PreviewImport, RunImport } from "../../wailsjs/go/controllers/ImportController"
+import { importer } from "../../wailsjs/go/models";
+import queryClient from "@/QueryClient";
+
+export const importFields = [
+    "case_id",
+    "case_type",
+    "region",
+    "alias",
+    "nature",
+    "other_ids",
+    "tags",
+    "accord_date",
+    "accord_content",
+] as const
+
+export type ImportSource = {
+    path: string;
+    format: string;
+    mapping: Record<string, string>;
+    sheet: string;
+}
+
+const importQueryKeys = {
+    all: ["import"] as const,
+    preview: (src: ImportSource, upsert: boolean) => [...importQueryKeys.all, "preview", src, upsert] as const,
+}
+
+export function useImportPreview(src: ImportSource, upsert: boolean) {
+    return useQuery({
+        queryKey: importQueryKeys.preview(src, upsert),
+        queryFn: async () => {
+            return await PreviewImport(src as importer.Source, upsert)
+        },
+        enabled: src.path !== "",
+        retry: false,
+        // Each preview replaces the one RunImport runs, so it's only
+        // rebuilt when the source or the options change
+        refetchInterval: false,
+        refetchOnWindowFocus: false,
+    })
+}
+
+// Runs the preview with the id as it was reviewed, without
+// reading the file again
+export function useRunImport() {
+    return useMutation({
+        mutationFn: async (previewId: string) => {
+            return await RunImport(previewId)
+        },
+        onSettled: () => {
+            queryClient.invalidateQueries({ queryKey: ["cases"] })
+            queryClient.invalidateQueries({ queryKey: importQueryKeys.all })
+        }
+    })
+}
@@ -0,0 +1,57 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+import {
+    CreateCaseNote,
+    DeleteCaseNote,
+    FindCaseWithTimeline,
+    UpdateCaseNote,
+} from "../../wailsjs/go/controllers/CaseController"
+import { db } from "../../wailsjs/go/models";
+import queryClient from "@/QueryClient";
+import { caseQueryKeys } from "./cases";
+
+const noteQueryKeys = {
+    timelines: () => [...caseQueryKeys.details(), "timeline"] as const,
+    timeline: (id: string, accordCount: number) => [...noteQueryKeys.timelines(), id, accordCount] as const,
+}
+
+const invalidateTimelines = () => queryClient.invalidateQueries({ queryKey: noteQueryKeys.timelines() })
+
+// The case with its latest accords merged with its notes and events
+export function useCaseTimeline(id: string, accordCount: number) {
+    return useQuery({
+        queryKey: noteQueryKeys.timeline(id, accordCount),
+        queryFn: async () => {
+            return await FindCaseWithTimeline(id, accordCount)
+        }
+    })
+}
+
+type CreateCaseNoteParams = {
+    caseId: string;
+    content: string;
+    accordId?: string;
+}
+export function useCreateCaseNote() {
+    return useMutation({
+        mutationFn: ({ caseId, content, accordId }: CreateCaseNoteParams) => {
+            return CreateCaseNote(caseId, content, accordId || "")
+        },
+        onSuccess: invalidateTimelines,
+    })
+}
+
+export function useUpdateCaseNote() {
+    return useMutation({
+        mutationFn: ({ id, content, accordId }: { id: string, content: string, accordId?: string }) => {
+            return UpdateCaseNote(id, new db.CaseNote({ content, accordId: accordId || "" }))
+        },
+        onSuccess: invalidateTimelines,
+    })
+}
+
+export function useDeleteCaseNote() {
+    return useMutation({
+        mutationFn: DeleteCaseNote,
+        onSuccess: invalidateTimelines,
+    })
+}
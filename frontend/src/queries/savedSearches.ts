@@ -0,0 +1,70 @@
+import { useInfiniteQuery, useMutation, useQuery } from "@tanstack/react-query";
+import {
+    CreateSavedSearch,
+    DeleteSavedSearch,
+    FindSavedSearch,
+    FindSavedSearches,
+    RunSavedSearch,
+} from "../../wailsjs/go/controllers/SavedSearchController"
+import { db } from "../../wailsjs/go/models";
+import queryClient from "@/QueryClient";
+import { caseQueryKeys } from "./cases";
+
+// Nested in the case keys, so the counts refresh whenever cases change
+const savedSearchQueryKeys = {
+    all: [...caseQueryKeys.all, "saved-searches"] as const,
+    counts: () => [...savedSearchQueryKeys.all, "counts"] as const,
+    detail: (id: string) => [...savedSearchQueryKeys.all, "detail", id] as const,
+    pages: (id: string, pageSize: number) => [...savedSearchQueryKeys.all, "pages", id, pageSize] as const,
+}
+
+const invalidateSavedSearches = () => queryClient.invalidateQueries({ queryKey: savedSearchQueryKeys.all })
+
+// Every saved search with the number of cases it matches
+export function useSavedSearchCounts() {
+    return useQuery({
+        queryKey: savedSearchQueryKeys.counts(),
+        queryFn: async () => {
+            return await FindSavedSearches()
+        },
+        refetchInterval: 60_000,
+    })
+}
+
+export function useSavedSearch(id: string) {
+    return useQuery({
+        queryKey: savedSearchQueryKeys.detail(id),
+        queryFn: async () => {
+            return await FindSavedSearch(id)
+        },
+        enabled: id !== "",
+    })
+}
+
+// Same as useCasePages for the cases of a saved search
+export function useSavedSearchPages(id: string, pageSize: number) {
+    return useInfiniteQuery({
+        queryKey: savedSearchQueryKeys.pages(id, pageSize),
+        queryFn: async ({ pageParam }) => {
+            return await RunSavedSearch(id, pageSize, pageParam, true)
+        },
+        initialPageParam: "",
+        getNextPageParam: lastPage => lastPage.nextCursor || undefined,
+    })
+}
+
+export function useCreateSavedSearch() {
+    return useMutation({
+        mutationFn: ({ name, opts }: { name: string, opts: Partial<db.FindCaseOptions> }) => {
+            return CreateSavedSearch(name, opts as db.FindCaseOptions, false)
+        },
+        onSuccess: invalidateSavedSearches,
+    })
+}
+
+export function useDeleteSavedSearch() {
+    return useMutation({
+        mutationFn: DeleteSavedSearch,
+        onSuccess: invalidateSavedSearches,
+    })
+}
@@ -0,0 +1,50 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+import { ApiServerStatus, FindApiSettings, RegenerateApiToken, SaveApiSettings } from "../../wailsjs/go/controllers/ApiController"
+import { api } from "../../wailsjs/go/models";
+import queryClient from "@/QueryClient";
+
+const settingsQueryKeys = {
+    all: ["settings"] as const,
+    api: () => [...settingsQueryKeys.all, "api"] as const,
+    apiStatus: () => [...settingsQueryKeys.all, "api", "status"] as const,
+}
+
+export function useApiSettings() {
+    return useQuery({
+        queryKey: settingsQueryKeys.api(),
+        queryFn: async () => {
+            return await FindApiSettings()
+        }
+    })
+}
+
+export function useApiServerStatus() {
+    return useQuery({
+        queryKey: settingsQueryKeys.apiStatus(),
+        queryFn: async () => {
+            return await ApiServerStatus()
+        }
+    })
+}
+
+export function useSaveApiSettings() {
+    return useMutation({
+        mutationFn: async (settings: api.Settings) => {
+            return await SaveApiSettings(settings)
+        },
+        onSettled: () => {
+            queryClient.invalidateQueries({ queryKey: settingsQueryKeys.all })
+        }
+    })
+}
+
+export function useRegenerateApiToken() {
+    return useMutation({
+        mutationFn: async () => {
+            return await RegenerateApiToken()
+        },
+        onSettled: () => {
+            queryClient.invalidateQueries({ queryKey: settingsQueryKeys.all })
+        }
+    })
+}
@@ -0,0 +1,87 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+import {
+    CheckWatchlist,
+    CreateWatchItem,
+    DeleteWatchItem,
+    FindWatchItem,
+    FindWatchlist,
+    PromoteWatchItem,
+    UpdateWatchItemNote,
+} from "../../wailsjs/go/controllers/WatchlistController"
+import queryClient from "@/QueryClient";
+
+const watchlistQueryKeys = {
+    all: ["watchlist"] as const,
+    list: (includePromoted: boolean) => [...watchlistQueryKeys.all, "list", includePromoted] as const,
+    detail: (id: string) => [...watchlistQueryKeys.all, "detail", id] as const,
+}
+
+const invalidateWatchlist = () => queryClient.invalidateQueries({ queryKey: watchlistQueryKeys.all })
+
+export function useWatchlist(includePromoted: boolean) {
+    return useQuery({
+        queryKey: watchlistQueryKeys.list(includePromoted),
+        queryFn: async () => {
+            return await FindWatchlist(includePromoted)
+        }
+    })
+}
+
+export function useWatchItem(id: string) {
+    return useQuery({
+        queryKey: watchlistQueryKeys.detail(id),
+        queryFn: async () => {
+            return await FindWatchItem(id)
+        },
+        enabled: id !== "",
+    })
+}
+
+export function useCreateWatchItem() {
+    return useMutation({
+        mutationFn: async ({ caseId, caseType, region, note }: { caseId: string, caseType: string, region: string, note: string }) => {
+            return await CreateWatchItem(caseId, caseType, region, note)
+        },
+        onSettled: invalidateWatchlist,
+    })
+}
+
+export function useUpdateWatchItemNote() {
+    return useMutation({
+        mutationFn: async ({ id, note }: { id: string, note: string }) => {
+            return await UpdateWatchItemNote(id, note)
+        },
+        onSettled: invalidateWatchlist,
+    })
+}
+
+export function useDeleteWatchItem() {
+    return useMutation({
+        mutationFn: async (id: string) => {
+            return await DeleteWatchItem(id)
+        },
+        onSettled: invalidateWatchlist,
+    })
+}
+
+export function useCheckWatchlist() {
+    return useMutation({
+        mutationFn: async (maxSearchBack: number) => {
+            return await CheckWatchlist(maxSearchBack)
+        },
+        onSettled: invalidateWatchlist,
+    })
+}
+
+// Promoting creates a case, so the case queries are refreshed too
+export function usePromoteWatchItem() {
+    return useMutation({
+        mutationFn: async ({ id, alias }: { id: string, alias: string }) => {
+            return await PromoteWatchItem(id, alias)
+        },
+        onSettled: () => {
+            invalidateWatchlist()
+            queryClient.invalidateQueries({ queryKey: ["cases"] })
+        },
+    })
+}
@@ -0,0 +1,84 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+import {
+    CreateWebhook,
+    DeleteWebhook,
+    FindAllWebhooks,
+    FindWebhookDeliveries,
+    FindWebhookEvents,
+    SendTestWebhook,
+    UpdateWebhook,
+} from "../../wailsjs/go/controllers/WebhookController"
+import { db } from "../../wailsjs/go/models";
+import queryClient from "@/QueryClient";
+
+const webhookQueryKeys = {
+    all: ["webhooks"] as const,
+    list: () => [...webhookQueryKeys.all, "list"] as const,
+    events: () => [...webhookQueryKeys.all, "events"] as const,
+    deliveries: (webhookId: string) => [...webhookQueryKeys.all, "deliveries", webhookId] as const,
+}
+
+const invalidateWebhooks = () => queryClient.invalidateQueries({ queryKey: webhookQueryKeys.all })
+
+export function useWebhooks() {
+    return useQuery({
+        queryKey: webhookQueryKeys.list(),
+        queryFn: async () => {
+            return await FindAllWebhooks()
+        }
+    })
+}
+
+export function useWebhookEvents() {
+    return useQuery({
+        queryKey: webhookQueryKeys.events(),
+        queryFn: async () => {
+            return await FindWebhookEvents()
+        }
+    })
+}
+
+export function useWebhookDeliveries(webhookId: string, limit: number) {
+    return useQuery({
+        queryKey: webhookQueryKeys.deliveries(webhookId),
+        queryFn: async () => {
+            return await FindWebhookDeliveries(webhookId, limit)
+        }
+    })
+}
+
+export function useCreateWebhook() {
+    return useMutation({
+        mutationFn: async ({ url, description, events }: { url: string, description: string, events: string[] }) => {
+            return await CreateWebhook(url, description, events)
+        },
+        onSettled: invalidateWebhooks,
+    })
+}
+
+export function useUpdateWebhook() {
+    return useMutation({
+        mutationFn: async (webhook: db.Webhook) => {
+            return await UpdateWebhook(webhook.id, webhook)
+        },
+        onSettled: invalidateWebhooks,
+    })
+}
+
+export function useDeleteWebhook() {
+    return useMutation({
+        mutationFn: async (id: string) => {
+            return await DeleteWebhook(id)
+        },
+        onSettled: invalidateWebhooks,
+    })
+}
+
+export function useSendTestWebhook() {
+    return useMutation({
+        mutationFn: async (id: string) => {
+            return await SendTestWebhook(id)
+        },
+        onSettled: invalidateWebhooks,
+    })
+}
@@ -9,6 +9,7 @@ require (
 	github.com/pressly/goose/v3 v3.24.0
 	github.com/wailsapp/wails/v2 v2.9.2
 	golang.org/x/net v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
 	modernc.org/sqlite v1.34.2
 )
 
@@ -13,6 +13,7 @@ import (
 	"github.com/vladwithcode/lex_app/internal/db"
 	"github.com/vladwithcode/lex_app/internal/fetchers"
 	"github.com/vladwithcode/lex_app/internal/readers"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
 )
 
 type CaseStore interface {
@@ -20,8 +21,14 @@ type CaseStore interface {
 	FindAllKeys(keys []string) ([]*db.LexCase, error)
 	FindById(id string) (*db.LexCase, error)
 	FindByKey(key string) (*db.LexCase, error)
-
-	Save(updates []*UpdatedAccord) error
+	// Returns the regions of the cases indexed by case key
+	FindRegions(keys []string) (map[string][]internal.Region, error)
+
+	// Returns how many of the accords were stored, accords
+	// already stored for the case and date are skipped
+	Save(updates []*UpdatedAccord) (saved int, err error)
+	// Records when the cases were last searched for updates
+	MarkChecked(keys []string, at time.Time) error
 }
 
 type AccUpdter interface {
@@ -38,7 +45,9 @@ type AccUpdter interface {
 } */
 
 type UpdatedAccord struct {
-	CaseKey  string
+	CaseKey string
+	// Region of the court that published the accord, RegionDefault when empty
+	Region   internal.Region
 	CaseType internal.CaseType
 	CaseId   string
 	Content  string
@@ -189,17 +198,23 @@ func (st *DefaultCaseStore) FindById(id string) (*db.LexCase, error) {
 func (st *DefaultCaseStore) FindByKey(key string) (*db.LexCase, error) {
 	return db.FindCase(st.ctx, st.db, key)
 }
-func (st *DefaultCaseStore) Save(updates []*UpdatedAccord) error {
+func (st *DefaultCaseStore) FindRegions(keys []string) (map[string][]internal.Region, error) {
+	return db.FindCaseRegions(st.ctx, st.db, keys)
+}
+func (st *DefaultCaseStore) MarkChecked(keys []string, at time.Time) error {
+	return db.MarkCasesChecked(st.ctx, st.db, keys, at)
+}
+func (st *DefaultCaseStore) Save(updates []*UpdatedAccord) (int, error) {
 	ctx, cancel := context.WithTimeout(st.ctx, 10*time.Second)
 	defer cancel()
 
 	tx, err := st.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	findCase, err := st.db.Prepare(`SELECT id FROM cases WHERE case_id = :CaseId AND case_type = :CaseType`)
+	findCase, err := st.db.Prepare(`SELECT id, alias FROM cases WHERE region = :Region AND case_id = :CaseId AND case_type = :CaseType`)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer findCase.Close()
 
@@ -211,7 +226,7 @@ VALUES (
 	:Date
 )`)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer createAcc.Close()
 
@@ -220,32 +235,63 @@ VALUES (
 	txCreateAcc := tx.StmtContext(ctx, createAcc)
 	defer txCreateAcc.Close()
 
+	created := []*db.CaseAccord{}
 	for _, upd := range updates {
 		id := uuid.Must(uuid.NewV7()).String()
 
+		region := upd.Region
+		if region == "" {
+			region = internal.RegionDefault
+		}
+
 		caseRecordId := ""
+		alias := ""
 		err := txFindCase.QueryRowContext(
 			ctx,
+			sql.Named("Region", region),
 			sql.Named("CaseId", upd.CaseId),
 			sql.Named("CaseType", upd.CaseType),
-		).Scan(&caseRecordId)
+		).Scan(&caseRecordId, &alias)
 		if err != nil {
 			continue
 		}
-		txCreateAcc.ExecContext(
+		_, err = txCreateAcc.ExecContext(
 			ctx,
 			sql.Named("Id", id),
 			sql.Named("ForCase", caseRecordId),
 			sql.Named("Content", upd.Content),
 			sql.Named("Date", upd.Date.Unix()),
 		)
+		if err != nil {
+			continue
+		}
+
+		created = append(created, &db.CaseAccord{
+			Accord: &db.Accord{
+				Id:      id,
+				ForCase: caseRecordId,
+				Content: upd.Content,
+				Date:    upd.Date,
+				DateStr: upd.Date.Format(time.RFC3339),
+			},
+			CaseId:   upd.CaseId,
+			CaseType: string(upd.CaseType),
+			Alias:    alias,
+		})
 	}
 
 	if err := tx.Commit(); err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	for _, ca := range created {
+		if err := webhooks.Emit(st.ctx, st.db, db.WebhookEventAccordCreated, ca); err != nil {
+			fmt.Printf("Webhooks Err: %v\n", err)
+			break
+		}
+	}
+
+	return len(created), nil
 }
 
 // Appends CaseRows and index entries from the mergingTable into the targetTable
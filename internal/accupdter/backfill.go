@@ -0,0 +1,228 @@
+package accupdter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/fetchers"
+	"github.com/vladwithcode/lex_app/internal/readers"
+)
+
+var ErrBackfillNotResumable = errors.New("the backfill job is already running or done")
+
+type BackfillOpts struct {
+	// Documents are read from and saved to the cache when set
+	Cache *fetchers.DocCache
+	// Time between the fetches made to a region,
+	// fetchers.DefaultFetchInterval when 0
+	Interval time.Duration
+	// Called after every searched date with the updated job
+	Progress func(job *db.BackfillJob)
+}
+
+// Searches the dates of the job that weren't searched yet, saving the
+// accords found and the checkpoints after every date.
+//
+// Cancelling ctx pauses the job, running it again resumes it from its
+// checkpoints. The returned job reflects the progress made either way
+func (updter *GeneralUpdater) RunBackfill(
+	ctx context.Context,
+	appDb *sql.DB,
+	jobId string,
+	opts *BackfillOpts,
+) (*db.BackfillJob, error) {
+	if opts == nil {
+		opts = &BackfillOpts{}
+	}
+	if opts.Interval == 0 {
+		opts.Interval = fetchers.DefaultFetchInterval
+	}
+	// Progress must be saved even once ctx is cancelled
+	dbCtx := context.WithoutCancel(ctx)
+
+	job, err := db.FindBackfillJobById(dbCtx, appDb, jobId)
+	if err != nil {
+		return nil, err
+	}
+	if !job.Resumable() {
+		return job, fmt.Errorf("%s is %s:\n\t%w", job.Id, job.Status, ErrBackfillNotResumable)
+	}
+
+	cases, err := db.FindBackfillCases(dbCtx, appDb, job.Id)
+	if err != nil {
+		return job, err
+	}
+	searchGroups := SearchGroupsMap{}
+	for _, c := range cases {
+		group := SearchGroup{internal.Region(c.Region), internal.CaseType(c.CaseType)}
+		searchGroups[group] = append(searchGroups[group], c.CaseId)
+	}
+
+	if err := db.SetBackfillStatus(dbCtx, appDb, job, db.BackfillRunning, ""); err != nil {
+		return job, err
+	}
+
+	run := &backfillRun{
+		ctx:      ctx,
+		dbCtx:    dbCtx,
+		appDb:    appDb,
+		job:      job,
+		opts:     opts,
+		store:    NewDefaultCaseStore(dbCtx, appDb),
+		limiters: map[internal.Region]*fetchers.RateLimiter{},
+	}
+	for _, cp := range job.Checkpoints {
+		if cp.Done(job) {
+			continue
+		}
+
+		group := SearchGroup{internal.Region(cp.Region), internal.CaseType(cp.CaseType)}
+		err := updter.backfillCheckpoint(run, cp, searchGroups[group])
+		if err == nil {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			if sErr := db.SetBackfillStatus(dbCtx, appDb, job, db.BackfillPaused, ""); sErr != nil {
+				fmt.Printf("SetBackfillStatus Err: %v\n", sErr)
+			}
+			return job, ctx.Err()
+		}
+		if sErr := db.SetBackfillStatus(dbCtx, appDb, job, db.BackfillFailed, err.Error()); sErr != nil {
+			fmt.Printf("SetBackfillStatus Err: %v\n", sErr)
+		}
+		return job, err
+	}
+
+	return job, db.SetBackfillStatus(dbCtx, appDb, job, db.BackfillDone, "")
+}
+
+// State shared by the checkpoints of a single run
+type backfillRun struct {
+	ctx   context.Context
+	dbCtx context.Context
+	appDb *sql.DB
+	job   *db.BackfillJob
+	opts  *BackfillOpts
+	store CaseStore
+	// Shared by the case types of a region, which are usually
+	// published by the same site
+	limiters map[internal.Region]*fetchers.RateLimiter
+}
+
+// Walks back from the checkpoint's next date to the start of the job
+func (updter *GeneralUpdater) backfillCheckpoint(run *backfillRun, cp *db.BackfillCheckpoint, caseIds []string) error {
+	group := SearchGroup{internal.Region(cp.Region), internal.CaseType(cp.CaseType)}
+	src, err := updter.sourceFor(group.Region)
+	if err != nil {
+		return err
+	}
+
+	// Nothing can be found for the checkpoint, its remaining dates
+	// are skipped at once
+	skip := len(caseIds) == 0
+	courtMissing := src.region != nil && !src.region.HasCaseType(group.CaseType)
+	if skip || courtMissing {
+		remaining := internal.DaysBetween(run.job.Since, cp.NextDate) + 1
+		cp.SearchedDays += remaining
+		if courtMissing {
+			cp.FailedDays += remaining
+		}
+		cp.NextDate = run.job.Since.AddDate(0, 0, -1)
+
+		return updter.saveBackfillProgress(run, cp, 0)
+	}
+
+	limiter, ok := run.limiters[group.Region]
+	if !ok {
+		limiter = fetchers.NewRateLimiter(run.opts.Interval)
+		run.limiters[group.Region] = limiter
+	}
+	fetch := fetchers.Limited(src.fetch, limiter)
+	if run.opts.Cache != nil {
+		fetch = fetchers.Cached(fetch, run.opts.Cache, group.Region)
+	}
+
+	for !cp.Done(run.job) {
+		if err := run.ctx.Err(); err != nil {
+			return err
+		}
+
+		date := internal.StartOfDay(cp.NextDate)
+		found := []*UpdatedAccord{}
+		// Courts don't publish on weekends and holidays
+		if src.calendar == nil || src.calendar.IsWorkday(date) {
+			found, err = searchBulletin(fetch, src.reader, group, date, caseIds)
+			// A date without a bulletin is searched like any other. Any
+			// other error stops the job on the date, to be searched again
+			// when it's resumed
+			if errors.Is(err, fetchers.ErrDocNotFound) {
+				cp.FailedDays++
+			} else if err != nil {
+				return fmt.Errorf("searching %s of %s on %s:\n\t%w", group.CaseType, group.Region, date.Format("2006-01-02"), err)
+			}
+		}
+
+		// Accords already stored aren't saved again, so only the
+		// saved ones are counted
+		saved := 0
+		if len(found) > 0 {
+			saved, err = run.store.Save(found)
+			if err != nil {
+				return errors.Join(ErrFailSave, err)
+			}
+		}
+
+		cp.NextDate = date.AddDate(0, 0, -1)
+		cp.SearchedDays++
+		if err := updter.saveBackfillProgress(run, cp, saved); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (updter *GeneralUpdater) saveBackfillProgress(run *backfillRun, cp *db.BackfillCheckpoint, newAccords int) error {
+	if err := db.SaveBackfillCheckpoint(run.dbCtx, run.appDb, run.job, cp, newAccords); err != nil {
+		return err
+	}
+	if run.opts.Progress != nil {
+		run.opts.Progress(run.job)
+	}
+
+	return nil
+}
+
+// Returns the accords of the cases in the bulletin of the date
+func searchBulletin(
+	fetch fetchers.DocFetcher,
+	reader readers.DocReader,
+	group SearchGroup,
+	date time.Time,
+	caseIds []string,
+) ([]*UpdatedAccord, error) {
+	doc, err := fetch(date, group.CaseType)
+	if err != nil {
+		return nil, err
+	}
+
+	caseTable, err := readers.ReadDocument(reader, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	accords := []*UpdatedAccord{}
+	for _, cId := range caseIds {
+		if caseRow := caseTable.Find(cId); caseRow != nil {
+			accords = append(accords, newUpdatedAccord(caseRow, group, date))
+		}
+	}
+
+	return accords, nil
+}
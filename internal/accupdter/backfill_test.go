@@ -0,0 +1,164 @@
+package accupdter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/pressly/goose/v3"
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/readers"
+	_ "modernc.org/sqlite"
+)
+
+// Opens a database in a temp dir with every migration applied. Saving
+// accords takes more than one connection, so it can't be in memory
+func openTestDb(t *testing.T) *sql.DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "lex_app.db")
+	appDb, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	t.Cleanup(func() { appDb.Close() })
+
+	goose.SetLogger(goose.NopLogger())
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if err := goose.Up(appDb, "../../data/migrations"); err != nil {
+		t.Fatalf("migrating errored with\n  %v", err)
+	}
+
+	return appDb
+}
+
+func TestRunBackfillResumes(t *testing.T) {
+	// The range crosses the start of DST, when a day lasts 23 hours
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	local := time.Local
+	time.Local = loc
+	defer func() { time.Local = local }()
+
+	ctx := context.Background()
+	appDb := openTestDb(t)
+
+	lc, err := db.NewCase("12/2024", "fam2")
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if err := db.InsertCase(ctx, appDb, lc); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	// Already stored, so finding it again doesn't count as new
+	stored := db.NewAccord(lc.Id)
+	stored.Date = time.Date(2022, time.March, 16, 0, 0, 0, 0, time.Local)
+	stored.Content = "Acuerdo del 2022-03-16"
+	if err := db.InsertAccord(ctx, appDb, stored); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	since := time.Date(2022, time.March, 7, 0, 0, 0, 0, time.Local)
+	until := time.Date(2022, time.March, 18, 0, 0, 0, 0, time.Local)
+	job, err := db.NewBackfillJob([]string{lc.Id}, since, until)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if err := db.InsertBackfillJob(ctx, appDb, job); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	fetched := map[string]int{}
+	updter := NewGeneralUpdater(&GenUpdterConf{
+		FetchFn: func(date time.Time, caseType internal.CaseType) (*[]byte, error) {
+			if h, m, s := date.Clock(); h != 0 || m != 0 || s != 0 {
+				t.Errorf("Expected to fetch local midnights, got %s", date)
+			}
+			day := date.Format("2006-01-02")
+			fetched[day]++
+			// Pauses the job once the date is searched
+			if day == "2022-03-14" {
+				cancel()
+			}
+
+			data := []byte(day)
+			return &data, nil
+		},
+		// Every bulletin of an even day has an accord of the case
+		ReadFn: func(data *[]byte) (*readers.CaseTable, error) {
+			table := readers.NewCaseTable()
+			date, err := time.Parse("2006-01-02", string(*data))
+			if err != nil || date.Day()%2 != 0 {
+				return table, err
+			}
+
+			row, err := readers.NewCaseRow(&readers.CaseData{CaseId: "12/2024", Accord: "Acuerdo del " + string(*data)})
+			if err != nil {
+				return nil, err
+			}
+			table.Add(row)
+
+			return table, nil
+		},
+		ctx: ctx,
+		db:  appDb,
+	})
+	opts := &BackfillOpts{Interval: time.Millisecond}
+
+	paused, err := updter.RunBackfill(runCtx, appDb, job.Id, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the job to be paused by the cancelled context, got %v", err)
+	}
+	if paused.Status != db.BackfillPaused {
+		t.Errorf("Expected the job to be paused, got %s", paused.Status)
+	}
+	if paused.SearchedDays != 5 {
+		t.Errorf("Expected 5 days searched before the pause, got %d", paused.SearchedDays)
+	}
+
+	done, err := updter.RunBackfill(ctx, appDb, job.Id, opts)
+	if err != nil {
+		t.Fatalf("resuming errored with\n  %v", err)
+	}
+
+	done, err = db.FindBackfillJobById(ctx, appDb, done.Id)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if done.Status != db.BackfillDone {
+		t.Errorf("Expected the job to be done, got %s", done.Status)
+	}
+	if done.TotalDays != 12 || done.SearchedDays != 12 {
+		t.Errorf("Expected 12 of 12 days searched, got %d of %d", done.SearchedDays, done.TotalDays)
+	}
+	for d := since; !d.After(until); d = d.AddDate(0, 0, 1) {
+		if day := d.Format("2006-01-02"); fetched[day] != 1 {
+			t.Errorf("Expected %s to be fetched once, got %d", day, fetched[day])
+		}
+	}
+	if len(fetched) != 12 {
+		t.Errorf("Expected 12 dates fetched, got %v", fetched)
+	}
+
+	// Even days from the 8th to the 18th, but the 16th was stored already
+	if done.AccordCount != 5 {
+		t.Errorf("Expected 5 new accords counted, got %d", done.AccordCount)
+	}
+	accords, err := db.FindAccordsForCases(ctx, appDb, []string{lc.Id})
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if len(accords) != 6 {
+		t.Errorf("Expected 6 accords stored, got %d", len(accords))
+	}
+}
@@ -0,0 +1,132 @@
+package accupdter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/fetchers"
+	"github.com/vladwithcode/lex_app/internal/readers"
+)
+
+// Every row of the list a court published for a date
+type Bulletin struct {
+	Region   string         `json:"region"`
+	CaseType string         `json:"caseType"`
+	Date     time.Time      `json:"date"`
+	Url      string         `json:"url"`
+	Rows     []*BulletinRow `json:"rows"`
+	// Rows the reader couldn't find a case id in
+	Unparsed []*BulletinRow `json:"unparsed"`
+}
+
+type BulletinRow struct {
+	CaseId   string   `json:"caseId"`
+	OtherIds []string `json:"otherIds"`
+	Nature   string   `json:"nature"`
+	Accord   string   `json:"accord"`
+	// Id of the registered case for any of the row ids,
+	// empty when the case isn't tracked
+	TrackedCaseId string `json:"trackedCaseId"`
+	// Id of the watchlist item for any of the row ids
+	WatchId string `json:"watchId"`
+}
+
+// Fetches and reads the list of the case type published on the date,
+// marking the rows of the cases already tracked or watched.
+//
+// The document is looked for in the cache first when it isn't nil
+func (updter *GeneralUpdater) ReadBulletin(
+	ctx context.Context,
+	appDb *sql.DB,
+	region internal.Region,
+	caseType internal.CaseType,
+	date time.Time,
+	cache *fetchers.DocCache,
+) (*Bulletin, error) {
+	if region == "" {
+		region = updter.conf.Region
+	}
+	src, err := updter.sourceFor(region)
+	if err != nil {
+		return nil, err
+	}
+	if src.region != nil && !src.region.HasCaseType(caseType) {
+		return nil, fmt.Errorf("CaseType %q isn't registered for Region %q:\n\t%w", caseType, region, ErrNoSuchCourt)
+	}
+
+	y, m, d := date.Local().Date()
+	date = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	fetch := src.fetch
+	if cache != nil {
+		fetch = fetchers.Cached(fetch, cache, region)
+	}
+	doc, err := fetch(date, caseType)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("FetchFail: no list for CaseType %q on %s", caseType, date.Format("2006-01-02")), err)
+	}
+	caseTable, err := readers.ReadDocument(src.reader, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bulletin{
+		Region:   string(region),
+		CaseType: string(caseType),
+		Date:     date,
+		Url:      doc.Url,
+		Rows:     make([]*BulletinRow, len(caseTable.Cases)),
+		Unparsed: make([]*BulletinRow, len(caseTable.UnparsedCases)),
+	}
+
+	// Any id of a row may be the one a case was registered with
+	keys := []string{}
+	for i, row := range caseTable.Cases {
+		b.Rows[i] = &BulletinRow{
+			CaseId:   row.CaseId,
+			OtherIds: row.AllIds,
+			Nature:   row.Nature,
+			Accord:   row.Accord,
+		}
+		for _, id := range row.AllIds {
+			keys = append(keys, id+readers.CaseKeySeparator+string(caseType))
+		}
+	}
+	for i, cd := range caseTable.UnparsedCases {
+		b.Unparsed[i] = &BulletinRow{CaseId: cd.CaseId, OtherIds: []string{}, Nature: cd.Nature, Accord: cd.Accord}
+	}
+
+	caseIds, err := db.FindCaseIdsByKey(ctx, appDb, string(region), keys)
+	if err != nil {
+		return nil, err
+	}
+	watched, err := db.FindWatchItems(ctx, appDb, false)
+	if err != nil {
+		return nil, err
+	}
+	watchIds := map[string]string{}
+	for _, w := range watched {
+		if w.SearchRegion() == region {
+			watchIds[w.GetCaseKey()] = w.Id
+		}
+	}
+
+	for _, row := range b.Rows {
+		for _, id := range row.OtherIds {
+			key := id + readers.CaseKeySeparator + string(caseType)
+			if cId, ok := caseIds[key]; ok && row.TrackedCaseId == "" {
+				row.TrackedCaseId = cId
+			}
+			if wId, ok := watchIds[key]; ok && row.WatchId == "" {
+				row.WatchId = wId
+			}
+		}
+	}
+
+	return b, nil
+}
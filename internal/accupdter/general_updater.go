@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
 	"github.com/vladwithcode/lex_app/internal/fetchers"
 	"github.com/vladwithcode/lex_app/internal/readers"
+	"github.com/vladwithcode/lex_app/internal/regions"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
 )
 
 var (
@@ -19,19 +23,31 @@ var (
 	ErrNoUpdates   = errors.New("found no updates for the provided parameters")
 	ErrNilStore    = errors.New("the configured store is nil. But a store dependant method was called")
 	ErrFailSave    = errors.New("failed to save accords")
+	ErrNoSuchCourt = errors.New("the region doesn't publish accords for the case type")
 )
 
-type CaseTypesMap map[internal.CaseType][]string
+// Identifies a single search: the bulletins of one case type
+// published in one region
+type SearchGroup struct {
+	Region   internal.Region
+	CaseType internal.CaseType
+}
+
+// Case ids to search for, grouped by the search that can find them
+type SearchGroupsMap map[SearchGroup][]string
 
 type GenUpdterConf struct {
 	// Refers to the unique id used in the provided store
 	// to identify individual cases (independant of CaseId and CaseType)
-	Store           CaseStore
+	Store CaseStore
+	// Region used for the cases the store doesn't know the region of
 	Region          internal.Region
 	MaxSearchBack   int
 	SearchStartDate time.Time
-	FetchFn         func(time.Time, internal.CaseType) (*[]byte, error)
-	ReadFn          func(*[]byte) (*readers.CaseTable, error)
+	// When set, FetchFn and ReadFn replace the ones registered for
+	// every region. A replaced fetcher also ignores the region calendar
+	FetchFn func(time.Time, internal.CaseType) (*[]byte, error)
+	ReadFn  func(*[]byte) (*readers.CaseTable, error)
 
 	ctx context.Context
 	db  *sql.DB
@@ -49,12 +65,6 @@ func NewGeneralUpdater(conf *GenUpdterConf) *GeneralUpdater {
 		conf.Store = NewDefaultCaseStore(conf.ctx, conf.db)
 	}
 
-	if conf.FetchFn == nil {
-		conf.FetchFn = fetchers.NewFetcher(conf.Region)
-	}
-	if conf.ReadFn == nil {
-		conf.ReadFn = readers.NewReader(conf.Region)
-	}
 	if conf.ctx == nil {
 		conf.ctx = context.Background()
 	}
@@ -78,21 +88,21 @@ func (updter *GeneralUpdater) Update(
 		maxSearchBack = updter.conf.MaxSearchBack
 	}
 
-	caseTypesMap := genCaseTypeMap(caseKeys)
+	searchGroups := updter.genSearchGroups(caseKeys)
 
 	updatedAccords := []*UpdatedAccord{}
 	searchErrors := []error{}
-	// Refers to the searches per caseType not per caseId
-	pendingSearch := len(caseTypesMap)
+	// Refers to the searches per region and caseType not per caseId
+	pendingSearch := len(searchGroups)
 
 	updates := make(chan []*UpdatedAccord)
-	complete := make(chan error)
+	complete := make(chan *searchCompletion)
 
-	for cType, cIds := range caseTypesMap {
+	for group, cIds := range searchGroups {
 		go updter.getUpdates(&getUpdatesParams{
 			updates:       updates,
 			complete:      complete,
-			caseType:      cType,
+			group:         group,
 			caseIds:       cIds,
 			startDate:     startSearchDate,
 			daysBack:      maxSearchBack,
@@ -100,18 +110,30 @@ func (updter *GeneralUpdater) Update(
 		})
 	}
 
+	checkedKeys := []string{}
 	for pendingSearch > 0 {
 		select {
 		case updt := <-updates:
 			updatedAccords = append(updatedAccords, updt...)
-		case err := <-complete:
+		case done := <-complete:
 			pendingSearch--
-			if err != nil {
-				searchErrors = append(searchErrors, err)
+			if done.err != nil {
+				searchErrors = append(searchErrors, done.err)
+				continue
+			}
+			for _, cId := range searchGroups[done.group] {
+				checkedKeys = append(checkedKeys, cId+readers.CaseKeySeparator+string(done.group.CaseType))
 			}
 		}
 	}
 
+	store := updter.getStore()
+	if store != nil && len(checkedKeys) > 0 {
+		if err := store.MarkChecked(checkedKeys, time.Now()); err != nil {
+			fmt.Printf("MarkChecked Err: %v\n", err)
+		}
+	}
+
 	if len(updatedAccords) == 0 {
 		for i, sErr := range searchErrors {
 			fmt.Printf("Search Err[%d]: %v\n", i, sErr)
@@ -120,12 +142,11 @@ func (updter *GeneralUpdater) Update(
 		return nil, ErrNoUpdates
 	}
 
-	store := updter.getStore()
 	if store == nil {
 		return nil, ErrNilStore
 	}
 
-	err = store.Save(updatedAccords)
+	_, err = store.Save(updatedAccords)
 	if err != nil {
 		return nil, ErrFailSave
 	}
@@ -155,6 +176,17 @@ func (updter *GeneralUpdater) FindUpdates(
 	if len(caseKeys) == 0 {
 		return nil, ErrNoCaseKeys
 	}
+
+	return updter.findUpdates(updter.genSearchGroups(caseKeys), startSearchDate, maxSearchBack, exhaustSearch)
+}
+
+// Same as FindUpdates but searches the already grouped case ids
+func (updter *GeneralUpdater) findUpdates(
+	searchGroups SearchGroupsMap,
+	startSearchDate time.Time,
+	maxSearchBack int,
+	exhaustSearch bool,
+) (accords []*UpdatedAccord, err error) {
 	if startSearchDate.Equal((time.Time{})) {
 		startSearchDate = updter.conf.SearchStartDate
 	}
@@ -162,21 +194,19 @@ func (updter *GeneralUpdater) FindUpdates(
 		maxSearchBack = updter.conf.MaxSearchBack
 	}
 
-	caseTypesMap := genCaseTypeMap(caseKeys)
-
 	accords = []*UpdatedAccord{}
 	searchErrors := []error{}
-	// Refers to the searches per caseType not per caseId
-	pendingSearch := len(caseTypesMap)
+	// Refers to the searches per region and caseType not per caseId
+	pendingSearch := len(searchGroups)
 
 	updates := make(chan []*UpdatedAccord)
-	complete := make(chan error)
+	complete := make(chan *searchCompletion)
 
-	for cType, cIds := range caseTypesMap {
+	for group, cIds := range searchGroups {
 		go updter.getUpdates(&getUpdatesParams{
 			updates:       updates,
 			complete:      complete,
-			caseType:      cType,
+			group:         group,
 			caseIds:       cIds,
 			startDate:     startSearchDate,
 			daysBack:      maxSearchBack,
@@ -188,10 +218,10 @@ func (updter *GeneralUpdater) FindUpdates(
 		select {
 		case updt := <-updates:
 			accords = append(accords, updt...)
-		case err := <-complete:
+		case done := <-complete:
 			pendingSearch--
-			if err != nil {
-				searchErrors = append(searchErrors, err)
+			if done.err != nil {
+				searchErrors = append(searchErrors, done.err)
 			}
 		}
 	}
@@ -207,18 +237,80 @@ func (updter *GeneralUpdater) FindUpdates(
 	return
 }
 
+// Sent once the search for a single group is over.
+// A nil err means every date in range could be searched
+type searchCompletion struct {
+	group SearchGroup
+	err   error
+}
+
 type getUpdatesParams struct {
 	updates  chan<- []*UpdatedAccord
-	complete chan<- error
+	complete chan<- *searchCompletion
 
-	caseType      internal.CaseType
+	group         SearchGroup
 	caseIds       []string
 	startDate     time.Time
 	daysBack      int
 	exhaustSearch bool
 }
 
+// Fetch, read and calendar used to search a region
+type searchSource struct {
+	fetch    fetchers.DocFetcher
+	reader   readers.DocReader
+	calendar regions.Calendar
+	// Nil when both fetch and read are replaced by the conf
+	region *regions.Region
+}
+
+func (updter *GeneralUpdater) sourceFor(region internal.Region) (*searchSource, error) {
+	// The conf fns work on text already extracted from the documents
+	src := &searchSource{}
+	if updter.conf.FetchFn != nil {
+		src.fetch = fetchers.FromFetcher(updter.conf.FetchFn, internal.DocFormatText)
+	}
+	if updter.conf.ReadFn != nil {
+		src.reader = readers.NewDocReader(updter.conf.ReadFn, internal.DocFormatText)
+	}
+	if src.fetch != nil && src.reader != nil {
+		return src, nil
+	}
+
+	r, err := regions.Find(region)
+	if err != nil {
+		return nil, err
+	}
+	src.region = r
+	if src.fetch == nil {
+		src.fetch = r.Fetch
+		src.calendar = r.Calendar
+	}
+	if src.reader == nil {
+		src.reader = r.Reader
+	}
+
+	return src, nil
+}
+
 func (updter *GeneralUpdater) getUpdates(updateParams *getUpdatesParams) {
+	group := updateParams.group
+	src, err := updter.sourceFor(group.Region)
+	if err != nil {
+		updateParams.complete <- &searchCompletion{group, errors.Join(ErrFatalSearch, err)}
+		return
+	}
+	// Avoids fetching documents that will never exist
+	if src.region != nil && !src.region.HasCaseType(group.CaseType) {
+		fatalErr := errors.Join(
+			fmt.Errorf("CaseType %q isn't registered for Region %q", group.CaseType, group.Region),
+			ErrFatalSearch,
+			ErrNoSuchCourt,
+		)
+		updateParams.complete <- &searchCompletion{group, fatalErr}
+		return
+	}
+
 	pendingIds := make([]string, len(updateParams.caseIds))
 	copy(pendingIds, updateParams.caseIds)
 	y, m, d := updateParams.startDate.Date()
@@ -228,16 +320,22 @@ func (updter *GeneralUpdater) getUpdates(updateParams *getUpdatesParams) {
 	for i := 0; i <= updateParams.daysBack; i++ {
 		updatedAccords := []*UpdatedAccord{}
 
-		data, err := updter.conf.FetchFn(searchDate, updateParams.caseType)
+		// Courts don't publish on weekends and holidays
+		if src.calendar != nil && !src.calendar.IsWorkday(searchDate) {
+			searchDate = searchDate.Add(internal.DayBack)
+			continue
+		}
+
+		doc, err := src.fetch(searchDate, group.CaseType)
 		if err != nil {
 			if i == updateParams.daysBack && sent == 0 {
 				fatalErr := errors.Join(
-					fmt.Errorf("FetchFail: fetch for CaseType %q errored on date %s", updateParams.caseType, searchDate),
+					fmt.Errorf("FetchFail: fetch for CaseType %q in Region %q errored on date %s", group.CaseType, group.Region, searchDate),
 					ErrFatalSearch,
 					err,
 				)
 
-				updateParams.complete <- fatalErr
+				updateParams.complete <- &searchCompletion{group, fatalErr}
 				return
 			}
 
@@ -245,15 +343,15 @@ func (updter *GeneralUpdater) getUpdates(updateParams *getUpdatesParams) {
 			continue
 		}
 
-		caseTable, err := updter.conf.ReadFn(data)
+		caseTable, err := readers.ReadDocument(src.reader, doc)
 		if err != nil {
 			if i == updateParams.daysBack && sent == 0 {
 				fatalErr := errors.Join(
-					fmt.Errorf("ReadFail: read for CaseType %q errored on date %s", updateParams.caseType, searchDate),
+					fmt.Errorf("ReadFail: read for CaseType %q in Region %q errored on date %s", group.CaseType, group.Region, searchDate),
 					ErrFatalSearch,
 					err,
 				)
-				updateParams.complete <- fatalErr
+				updateParams.complete <- &searchCompletion{group, fatalErr}
 				return
 			}
 
@@ -271,18 +369,7 @@ func (updter *GeneralUpdater) getUpdates(updateParams *getUpdatesParams) {
 					continue
 				}
 			}
-			caseRow.CaseType = string(updateParams.caseType)
-			acc := UpdatedAccord{
-				CaseKey:  caseRow.GetCaseKey(),
-				CaseType: updateParams.caseType,
-				CaseId:   caseRow.CaseId,
-				Content:  caseRow.Accord,
-				Date:     searchDate,
-				Nature:   caseRow.Nature,
-				OthIds:   caseRow.AllIds,
-			}
-
-			updatedAccords = append(updatedAccords, &acc)
+			updatedAccords = append(updatedAccords, newUpdatedAccord(caseRow, group, searchDate))
 		}
 
 		updateParams.updates <- updatedAccords
@@ -294,7 +381,23 @@ func (updter *GeneralUpdater) getUpdates(updateParams *getUpdatesParams) {
 		}
 	}
 
-	updateParams.complete <- nil
+	updateParams.complete <- &searchCompletion{group, nil}
+}
+
+// Accord of the row found in the bulletin of the group on the date
+func newUpdatedAccord(caseRow *readers.CaseRow, group SearchGroup, date time.Time) *UpdatedAccord {
+	caseRow.CaseType = string(group.CaseType)
+
+	return &UpdatedAccord{
+		CaseKey:  caseRow.GetCaseKey(),
+		Region:   group.Region,
+		CaseType: group.CaseType,
+		CaseId:   caseRow.CaseId,
+		Content:  caseRow.Accord,
+		Date:     date,
+		Nature:   caseRow.Nature,
+		OthIds:   caseRow.AllIds,
+	}
 }
 
 func (updter *GeneralUpdater) getStore() CaseStore {
@@ -305,15 +408,69 @@ func (updter *GeneralUpdater) SetStore(st CaseStore) {
 	updter.conf.Store = st
 }
 
-func genCaseTypeMap(keys []string) CaseTypesMap {
-	caseTypesMap := CaseTypesMap{}
+// Groups the case keys by region and case type. A key is searched in
+// the region of every case with it, falling back to the configured
+// region. Malformed keys are left out
+func (updter *GeneralUpdater) genSearchGroups(keys []string) SearchGroupsMap {
+	caseRegions := map[string][]internal.Region{}
+	if store := updter.getStore(); store != nil {
+		found, err := store.FindRegions(keys)
+		if err != nil {
+			fmt.Printf("FindRegions Err: %v\n", err)
+		} else {
+			caseRegions = found
+		}
+	}
 
+	searchGroups := SearchGroupsMap{}
 	for _, cK := range keys {
-		parts := strings.Split(cK, readers.CaseKeySeparator)
-		caseId, caseType := parts[0], internal.CaseType(parts[1])
+		caseId, caseType, ok := strings.Cut(cK, readers.CaseKeySeparator)
+		if !ok || caseId == "" || caseType == "" {
+			fmt.Printf("genSearchGroups: malformed case key %q\n", cK)
+			continue
+		}
 
-		caseTypesMap[caseType] = append(caseTypesMap[caseType], caseId)
+		keyRegions := caseRegions[cK]
+		if len(keyRegions) == 0 {
+			keyRegions = []internal.Region{updter.conf.Region}
+		}
+
+		for _, region := range keyRegions {
+			group := SearchGroup{region, internal.CaseType(caseType)}
+			if !slices.Contains(searchGroups[group], caseId) {
+				searchGroups[group] = append(searchGroups[group], caseId)
+			}
+		}
+	}
+
+	return searchGroups
+}
+
+// Runs Update over the case keys and records its outcome as an update
+// run of the given source
+func (updter *GeneralUpdater) UpdateAndRecord(
+	ctx context.Context,
+	appDb *sql.DB,
+	source string,
+	caseKeys []string,
+	startSearchDate time.Time,
+	maxSearchBack int,
+	exhaustSearch bool,
+) (run *db.UpdateRun, notFoundKeys []string, err error) {
+	run, err = db.NewUpdateRun(source, len(caseKeys))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notFoundKeys, err = updter.Update(caseKeys, startSearchDate, maxSearchBack, exhaustSearch)
+
+	run.Finish(notFoundKeys, err, errors.Is(err, ErrNoUpdates))
+	if rErr := db.InsertUpdateRun(ctx, appDb, run); rErr != nil {
+		fmt.Printf("InsertUpdateRun Err: %v\n", rErr)
+	}
+	if wErr := webhooks.Emit(ctx, appDb, db.WebhookEventUpdateRunFinished, run); wErr != nil {
+		fmt.Printf("Webhooks Err: %v\n", wErr)
 	}
 
-	return caseTypesMap
+	return run, notFoundKeys, err
 }
@@ -0,0 +1,112 @@
+package accupdter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
+)
+
+// Outcome of a search over the watchlist
+type WatchCheck struct {
+	Checked int            `json:"checked"`
+	NewHits []*db.WatchHit `json:"newHits"`
+}
+
+// Payload of the watchlist.hit webhook event
+type watchHitEvent struct {
+	*db.WatchHit
+	CaseId   string `json:"caseId"`
+	CaseType string `json:"caseType"`
+	Region   string `json:"region"`
+	Note     string `json:"note"`
+}
+
+// Searches the bulletins for the case numbers of the watchlist and
+// records the accords found as hits. Promoted items aren't searched,
+// as their accords are found by the regular updates
+func (updter *GeneralUpdater) CheckWatchlist(
+	ctx context.Context,
+	appDb *sql.DB,
+	startSearchDate time.Time,
+	maxSearchBack int,
+) (*WatchCheck, error) {
+	check := &WatchCheck{NewHits: []*db.WatchHit{}}
+
+	items, err := db.FindWatchItems(ctx, appDb, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return check, nil
+	}
+
+	// The same case key may be watched in several regions
+	type regionKey struct {
+		region internal.Region
+		key    string
+	}
+	searchGroups := SearchGroupsMap{}
+	itemsByKey := map[regionKey]*db.WatchItem{}
+	ids := make([]string, len(items))
+	for i, w := range items {
+		group := SearchGroup{w.SearchRegion(), internal.CaseType(w.CaseType)}
+		searchGroups[group] = append(searchGroups[group], w.CaseId)
+		itemsByKey[regionKey{w.SearchRegion(), w.GetCaseKey()}] = w
+		ids[i] = w.Id
+	}
+
+	// Every date in range is searched, a watched case may have
+	// accords in several of them
+	accords, err := updter.findUpdates(searchGroups, startSearchDate, maxSearchBack, true)
+	if err != nil && !errors.Is(err, ErrNoUpdates) {
+		return nil, err
+	}
+
+	hits := []*db.WatchHit{}
+	for _, acc := range accords {
+		w, ok := itemsByKey[regionKey{acc.Region, acc.CaseKey}]
+		if !ok {
+			continue
+		}
+
+		h, err := db.NewWatchHit(w.Id, acc.Date, acc.Nature, acc.Content)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+
+	check.NewHits, err = db.InsertWatchHits(ctx, appDb, hits)
+	if err != nil {
+		return nil, err
+	}
+	check.Checked = len(items)
+
+	if err := db.MarkWatchItemsChecked(ctx, appDb, ids, time.Now()); err != nil {
+		fmt.Printf("MarkWatchItemsChecked Err: %v\n", err)
+	}
+
+	for _, h := range check.NewHits {
+		var w *db.WatchItem
+		for _, item := range items {
+			if item.Id == h.WatchId {
+				w = item
+				break
+			}
+		}
+
+		ev := &watchHitEvent{h, w.CaseId, w.CaseType, w.Region, w.Note}
+		if err := webhooks.Emit(ctx, appDb, db.WebhookEventWatchHit, ev); err != nil {
+			fmt.Printf("Webhooks Err: %v\n", err)
+			break
+		}
+	}
+
+	return check, nil
+}
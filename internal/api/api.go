@@ -0,0 +1,76 @@
+// Package api serves the case data of the app as JSON over HTTP, for other
+// tools in the office network to read it and start updates
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+// Key of the server settings in the settings table
+const SettingsKey = "api"
+
+const DefaultAddr = "127.0.0.1:8787"
+
+var (
+	ErrMissingToken = errors.New("the API server requires a token")
+	ErrInvalidAddr  = errors.New("the API server address should be formatted as 'host:port'")
+)
+
+type Settings struct {
+	// Starts the server along with the app
+	Enabled bool `json:"enabled"`
+	// Address the server listens on. Use the address of a LAN interface,
+	// or 0.0.0.0, to reach it from other machines
+	Addr string `json:"addr"`
+	// Sent by clients as 'Authorization: Bearer <token>'
+	Token string `json:"token"`
+}
+
+// Reads the server settings, returning disabled defaults if they
+// were never saved
+func LoadSettings(ctx context.Context, appDb *sql.DB) (*Settings, error) {
+	s := &Settings{Addr: DefaultAddr}
+	err := db.FindSetting(ctx, appDb, SettingsKey, s)
+	if err != nil && !errors.Is(err, db.ErrSettingNotSet) {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Validates and stores the settings, generating a token if they have none
+func SaveSettings(ctx context.Context, appDb *sql.DB, s *Settings) error {
+	if s.Addr == "" {
+		s.Addr = DefaultAddr
+	}
+	if _, _, err := net.SplitHostPort(s.Addr); err != nil {
+		return fmt.Errorf("%q:\n\t%w", s.Addr, ErrInvalidAddr)
+	}
+	if s.Token == "" {
+		token, err := NewToken()
+		if err != nil {
+			return err
+		}
+		s.Token = token
+	}
+
+	return db.SaveSetting(ctx, appDb, SettingsKey, s)
+}
+
+// Returns a random token to authenticate API clients
+func NewToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
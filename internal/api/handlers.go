@@ -0,0 +1,197 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+const (
+	defaultPageSize    = 50
+	maxPageSize        = 500
+	defaultAccordCount = 10
+)
+
+var errInvalidParam = errors.New("invalid query parameter")
+
+// GET /cases
+func (srv *Server) handleCases(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	params := r.URL.Query()
+	opts := db.DefaultFindCaseOptions
+	opts.Query = params.Get("q")
+	opts.Search = params.Get("search")
+	opts.CaseId = params.Get("caseId")
+	opts.CaseType = params.Get("caseType")
+	opts.ClientId = params.Get("clientId")
+	opts.Tags = splitParam(params.Get("tags"))
+	opts.SortBy = db.CaseSortField(params.Get("sort"))
+	opts.SortOrder = db.SortOrder(params.Get("order"))
+	opts.Cursor = params.Get("cursor")
+	opts.IncludeArchived = params.Get("archived") == "true"
+
+	var err error
+	if opts.Limit, err = intParam(params, "limit", defaultPageSize); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if opts.Limit <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit should be positive:\n\t%w", errInvalidParam))
+		return
+	}
+	opts.Limit = min(opts.Limit, maxPageSize)
+	if opts.MaxAccords, err = intParam(params, "accords", 0); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if opts.MaxAccords < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("accords can't be negative:\n\t%w", errInvalidParam))
+		return
+	}
+	opts.IncludeAccords = opts.MaxAccords > 0
+
+	page, err := db.FindCasesPage(r.Context(), srv.appDb, &opts)
+	if err != nil {
+		writeDbError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// GET /cases/{id} and GET /cases/{id}/accords
+func (srv *Server) handleCase(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	id, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, basePath+"/cases/"), "/")
+	if id == "" || (sub != "" && sub != "accords") {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	accordCount, err := intParam(r.URL.Query(), "accords", defaultAccordCount)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	// The accords are joined to the case, without any the case isn't read
+	if accordCount <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("accords should be positive:\n\t%w", errInvalidParam))
+		return
+	}
+
+	// Look the case up first, the accords queries return empty results
+	// for unknown ids. Cases in the trash are hidden like in /cases
+	c, err := db.FindCaseById(r.Context(), srv.appDb, id)
+	if err != nil {
+		writeDbError(w, err)
+		return
+	}
+	if c.DeletedAt != nil {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	if sub == "accords" {
+		accords, err := db.FindAllAccordsForCase(r.Context(), srv.appDb, c.Id)
+		if err != nil {
+			writeDbError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, accords)
+		return
+	}
+
+	c, err = db.FindCaseWithAccords(r.Context(), srv.appDb, c.Id, accordCount)
+	if err != nil {
+		writeDbError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c)
+}
+
+// GET /accords/search
+func (srv *Server) handleAccordSearch(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	params := r.URL.Query()
+	limit, err := intParam(params, "limit", defaultPageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	results, err := db.SearchAccords(r.Context(), srv.appDb, &db.AccordSearchOptions{
+		Search:   params.Get("q"),
+		From:     params.Get("from"),
+		To:       params.Get("to"),
+		CaseType: params.Get("caseType"),
+		Limit:    limit,
+	})
+	if err != nil {
+		writeDbError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// GET /update-runs
+func (srv *Server) handleUpdateRuns(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	limit, err := intParam(r.URL.Query(), "limit", defaultPageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	runs, err := db.FindUpdateRuns(r.Context(), srv.appDb, limit)
+	if err != nil {
+		writeDbError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+func intParam(params url.Values, name string, def int) (int, error) {
+	v := params.Get(name)
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s=%q:\n\t%w", name, v, errInvalidParam)
+	}
+
+	return n, nil
+}
+
+func splitParam(s string) []string {
+	list := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vladwithcode/lex_app/internal/accupdter"
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+// Finished jobs kept in memory to be polled
+const maxFinishedJobs = 50
+
+var (
+	ErrJobRunning      = errors.New("an update job is already running")
+	ErrNoCases         = errors.New("no tracked case matches the request")
+	ErrUnknownCaseKeys = errors.New("no tracked case has the keys")
+)
+
+type JobStatus string
+
+const (
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// Body of POST /update-jobs. CaseKeys are formatted as caseId:caseType.
+// Without them the cases are selected with Query and Tags, archived
+// cases are then left out
+type UpdateJobRequest struct {
+	CaseKeys []string `json:"caseKeys"`
+	Query    string   `json:"query"`
+	Tags     []string `json:"tags"`
+	// First date searched, formatted as 2006-01-02. Defaults to today
+	StartDate string `json:"startDate"`
+	DaysBack  int    `json:"daysBack"`
+	Exhaust   bool   `json:"exhaust"`
+}
+
+// A search for updates started through the API
+type UpdateJob struct {
+	Id           string        `json:"id"`
+	Status       JobStatus     `json:"status"`
+	CaseCount    int           `json:"caseCount"`
+	NotFoundKeys []string      `json:"notFoundKeys"`
+	Run          *db.UpdateRun `json:"run"`
+	Error        string        `json:"error"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	FinishedAt   *time.Time    `json:"finishedAt"`
+}
+
+// Runs the update jobs of the API one at a time. Updates started from
+// the app or by the scheduler aren't queued here and may run alongside
+type jobQueue struct {
+	ctx     context.Context
+	appDb   *sql.DB
+	updater *accupdter.GeneralUpdater
+
+	mu   sync.Mutex
+	jobs []*UpdateJob
+}
+
+func newJobQueue(ctx context.Context, appDb *sql.DB, updater *accupdter.GeneralUpdater) *jobQueue {
+	return &jobQueue{ctx: ctx, appDb: appDb, updater: updater, jobs: []*UpdateJob{}}
+}
+
+func (q *jobQueue) start(req *UpdateJobRequest) (UpdateJob, error) {
+	startDate := time.Now()
+	if req.StartDate != "" {
+		d, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+		if err != nil {
+			return UpdateJob{}, fmt.Errorf("startDate %q:\n\t%w", req.StartDate, errInvalidParam)
+		}
+		startDate = d
+	}
+	if req.DaysBack < 0 {
+		req.DaysBack = 0
+	}
+
+	caseKeys, err := q.findCaseKeys(req)
+	if err != nil {
+		return UpdateJob{}, err
+	}
+	if len(caseKeys) == 0 {
+		return UpdateJob{}, ErrNoCases
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return UpdateJob{}, fmt.Errorf("%w\n\t%w", db.ErrGenUUID, err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, j := range q.jobs {
+		if j.Status == JobRunning {
+			return UpdateJob{}, ErrJobRunning
+		}
+	}
+
+	job := &UpdateJob{
+		Id:           id.String(),
+		Status:       JobRunning,
+		CaseCount:    len(caseKeys),
+		NotFoundKeys: []string{},
+		CreatedAt:    time.Now(),
+	}
+	q.jobs = append(q.jobs, job)
+	if len(q.jobs) > maxFinishedJobs {
+		q.jobs = q.jobs[len(q.jobs)-maxFinishedJobs:]
+	}
+
+	go q.run(job, caseKeys, startDate, req.DaysBack, req.Exhaust)
+
+	return *job, nil
+}
+
+func (q *jobQueue) run(job *UpdateJob, caseKeys []string, startDate time.Time, daysBack int, exhaust bool) {
+	run, notFoundKeys, err := q.updater.UpdateAndRecord(
+		q.ctx,
+		q.appDb,
+		db.UpdateRunSourceApi,
+		caseKeys,
+		startDate,
+		daysBack,
+		exhaust,
+	)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Run = run
+	job.Status = JobDone
+	if errors.Is(err, accupdter.ErrNoUpdates) {
+		notFoundKeys = caseKeys
+	} else if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+	}
+	if notFoundKeys != nil {
+		job.NotFoundKeys = notFoundKeys
+	}
+}
+
+func (q *jobQueue) findCaseKeys(req *UpdateJobRequest) ([]string, error) {
+	if len(req.CaseKeys) > 0 {
+		return q.resolveCaseKeys(req.CaseKeys)
+	}
+
+	opts := db.DefaultFindCaseOptions
+	opts.Query = req.Query
+	opts.Tags = req.Tags
+	cases, err := db.FindFilteredCases(q.ctx, q.appDb, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(cases))
+	for i, c := range cases {
+		keys[i] = c.GetCaseKey()
+	}
+
+	return keys, nil
+}
+
+// Returns the keys of the tracked cases, failing with ErrUnknownCaseKeys
+// if any key is malformed or has no case
+func (q *jobQueue) resolveCaseKeys(caseKeys []string) ([]string, error) {
+	cases, err := db.FindCases(q.ctx, q.appDb, caseKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(cases))
+	keys := make([]string, 0, len(cases))
+	for _, c := range cases {
+		key := c.GetCaseKey()
+		if !found[key] {
+			found[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	unknown := []string{}
+	for _, key := range caseKeys {
+		if !found[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%s:\n\t%w", strings.Join(unknown, ", "), ErrUnknownCaseKeys)
+	}
+
+	return keys, nil
+}
+
+// Returns copies of the jobs, newest first
+func (q *jobQueue) list() []UpdateJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]UpdateJob, len(q.jobs))
+	for i, j := range q.jobs {
+		jobs[len(q.jobs)-1-i] = *j
+	}
+
+	return jobs
+}
+
+func (q *jobQueue) find(id string) (UpdateJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, j := range q.jobs {
+		if j.Id == id {
+			return *j, true
+		}
+	}
+
+	return UpdateJob{}, false
+}
+
+// GET and POST /update-jobs
+func (srv *Server) handleUpdateJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, srv.jobs.list())
+		return
+	}
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	req := &UpdateJobRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := srv.jobs.start(req)
+	switch {
+	case errors.Is(err, ErrJobRunning):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrNoCases), errors.Is(err, ErrUnknownCaseKeys), errors.Is(err, errInvalidParam):
+		writeError(w, http.StatusBadRequest, err)
+	case err != nil:
+		writeDbError(w, err)
+	default:
+		w.Header().Set("Location", basePath+"/update-jobs/"+job.Id)
+		writeJSON(w, http.StatusAccepted, job)
+	}
+}
+
+// GET /update-jobs/{id}
+func (srv *Server) handleUpdateJob(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	job, ok := srv.jobs.find(strings.TrimPrefix(r.URL.Path, basePath+"/update-jobs/"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/accupdter"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/query"
+)
+
+const basePath = "/api/v1"
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+type Server struct {
+	ctx   context.Context
+	appDb *sql.DB
+	token string
+	addr  string
+
+	jobs *jobQueue
+	http *http.Server
+}
+
+func NewServer(ctx context.Context, appDb *sql.DB, s *Settings) (*Server, error) {
+	if s.Token == "" {
+		return nil, ErrMissingToken
+	}
+	addr := s.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	updater := accupdter.NewGeneralUpdater(&accupdter.GenUpdterConf{
+		Store:  accupdter.NewDefaultCaseStore(ctx, appDb),
+		Region: internal.RegionDefault,
+	})
+
+	srv := &Server{
+		ctx:   ctx,
+		appDb: appDb,
+		token: s.Token,
+		addr:  addr,
+		jobs:  newJobQueue(ctx, appDb, updater),
+	}
+	srv.http = &http.Server{
+		Addr:              addr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return srv, nil
+}
+
+// Address the server listens on
+func (srv *Server) Addr() string {
+	return srv.addr
+}
+
+// Starts listening and serves the API in the background.
+//
+// Errors binding the address are returned right away
+func (srv *Server) Start() error {
+	ln, err := net.Listen("tcp", srv.addr)
+	if err != nil {
+		return err
+	}
+	srv.addr = ln.Addr().String()
+
+	go srv.http.Serve(ln)
+	return nil
+}
+
+// Stops accepting requests, waiting for the ones in progress
+func (srv *Server) Shutdown(ctx context.Context) error {
+	return srv.http.Shutdown(ctx)
+}
+
+func (srv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(basePath+"/cases", srv.handleCases)
+	mux.HandleFunc(basePath+"/cases/", srv.handleCase)
+	mux.HandleFunc(basePath+"/accords/search", srv.handleAccordSearch)
+	mux.HandleFunc(basePath+"/update-jobs", srv.handleUpdateJobs)
+	mux.HandleFunc(basePath+"/update-jobs/", srv.handleUpdateJob)
+	mux.HandleFunc(basePath+"/update-runs", srv.handleUpdateRuns)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, errNotFound)
+	})
+
+	authed := srv.authenticate(mux)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == basePath+"/openapi.json" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(openAPISpec)
+			return
+		}
+
+		authed.ServeHTTP(w, r)
+	})
+}
+
+// Rejects requests without the server token as a bearer token
+func (srv *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(srv.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+var (
+	errUnauthorized     = errors.New("missing or invalid token")
+	errNotFound         = errors.New("not found")
+	errMethodNotAllowed = errors.New("method not allowed")
+)
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorBody{Error: err.Error()})
+}
+
+// Writes err with the status matching its cause
+func writeDbError(w http.ResponseWriter, err error) {
+	var parseErr *query.ParseError
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, db.ErrCaseNotFound):
+		writeError(w, http.StatusNotFound, errNotFound)
+	case errors.As(err, &parseErr),
+		errors.Is(err, db.ErrInvalidCursor),
+		errors.Is(err, db.ErrInvalidSortField),
+		errors.Is(err, db.ErrEmptySearch):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// Responds with 405 unless the request uses method
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, errMethodNotAllowed)
+	return false
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthentication(t *testing.T) {
+	srv := &Server{token: "secret", jobs: newJobQueue(context.Background(), nil, nil)}
+	h := srv.Handler()
+
+	tests := []struct {
+		path   string
+		auth   string
+		status int
+	}{
+		{basePath + "/openapi.json", "", http.StatusOK},
+		{basePath + "/cases", "", http.StatusUnauthorized},
+		{basePath + "/cases", "Bearer wrong", http.StatusUnauthorized},
+		{basePath + "/cases", "secret", http.StatusUnauthorized},
+		{basePath + "/unknown", "Bearer secret", http.StatusNotFound},
+		{basePath + "/update-jobs/missing", "Bearer secret", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s with %q: expected status %d, got %d", tt.path, tt.auth, tt.status, rec.Code)
+		}
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	spec := map[string]any{}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("the OpenAPI description is not valid JSON:\n  %v", err)
+	}
+	if spec["openapi"] != "3.0.3" {
+		t.Errorf("Expected an OpenAPI 3.0.3 description, got %v", spec["openapi"])
+	}
+}
+
+func TestInvalidParams(t *testing.T) {
+	srv := &Server{token: "secret", jobs: newJobQueue(context.Background(), nil, nil)}
+	h := srv.Handler()
+
+	// Rejected before the database is read
+	for _, path := range []string{
+		basePath + "/cases?limit=0",
+		basePath + "/cases?limit=-1",
+		basePath + "/cases?limit=many",
+		basePath + "/cases?accords=-1",
+		basePath + "/cases/some-id?accords=0",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", path, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
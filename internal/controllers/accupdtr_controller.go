@@ -3,13 +3,13 @@ package controllers
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/vladwithcode/lex_app/internal"
 	"github.com/vladwithcode/lex_app/internal/accupdter"
 	"github.com/vladwithcode/lex_app/internal/db"
 	"github.com/vladwithcode/lex_app/internal/fetchers"
-	"github.com/vladwithcode/lex_app/internal/readers"
 )
 
 type AccUpdterOpts accupdter.AccUpdterOpts
@@ -19,14 +19,13 @@ type AccordUpdaterCtl struct {
 	appDb *sql.DB
 
 	generalUpdater *accupdter.GeneralUpdater
+	cache          *fetchers.DocCache
 }
 
 func NewAccordUpdaterCtl() *AccordUpdaterCtl {
 	return &AccordUpdaterCtl{
 		generalUpdater: accupdter.NewGeneralUpdater(&accupdter.GenUpdterConf{
 			Region:          internal.RegionDefault,
-			ReadFn:          readers.NewReader(internal.RegionDefault),
-			FetchFn:         fetchers.NewFetcher(internal.RegionDefault),
 			SearchStartDate: time.Now(),
 			MaxSearchBack:   0,
 		}),
@@ -43,7 +42,7 @@ func (ctl *AccordUpdaterCtl) FindUpdates(
 }
 
 func (ctl *AccordUpdaterCtl) Update(caseKeys []string, searchStartDate time.Time, maxSearchBack int, exhaustSearch bool) ([]string, error) {
-	return ctl.generalUpdater.Update(caseKeys, searchStartDate, maxSearchBack, exhaustSearch)
+	return ctl.update(db.UpdateRunSourceApp, caseKeys, searchStartDate, maxSearchBack, exhaustSearch)
 }
 
 func (ctl *AccordUpdaterCtl) FindCasesAndUpdate(
@@ -54,7 +53,9 @@ func (ctl *AccordUpdaterCtl) FindCasesAndUpdate(
 ) (notFoundKeys []string, err error) {
 	var caseKeys []string
 	var cases []*db.LexCase
-	if findOpts == nil {
+	if findOpts != nil {
+		// Archived cases are not tracked anymore, keep them out of the run
+		findOpts.IncludeArchived = false
 		cases, err = db.FindFilteredCases(ctl.ctx, ctl.appDb, findOpts)
 	} else {
 		cases, err = db.FindAllCases(ctl.ctx, ctl.appDb)
@@ -65,7 +66,80 @@ func (ctl *AccordUpdaterCtl) FindCasesAndUpdate(
 	for _, c := range cases {
 		caseKeys = append(caseKeys, c.GetCaseKey())
 	}
-	return ctl.generalUpdater.Update(caseKeys, searchStartDate, maxSearchBack, exhaustSearch)
+	return ctl.update(db.UpdateRunSourceApp, caseKeys, searchStartDate, maxSearchBack, exhaustSearch)
+}
+
+// Same as FindCasesAndUpdate, using the cases of a saved search
+func (ctl *AccordUpdaterCtl) FindSavedSearchAndUpdate(
+	savedSearchId string,
+	searchStartDate time.Time,
+	maxSearchBack int,
+	exhaustSearch bool,
+) (notFoundKeys []string, err error) {
+	ss, err := db.FindSavedSearchById(ctl.ctx, ctl.appDb, savedSearchId)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctl.FindCasesAndUpdate(searchStartDate, maxSearchBack, exhaustSearch, ss.Options)
+}
+
+// Updates the cases of every saved search flagged as scheduled.
+//
+// Cases matched by more than one search are only looked up once
+func (ctl *AccordUpdaterCtl) UpdateScheduledSearches(
+	searchStartDate time.Time,
+	maxSearchBack int,
+	exhaustSearch bool,
+) (notFoundKeys []string, err error) {
+	searches, err := db.FindScheduledSavedSearches(ctl.ctx, ctl.appDb)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var caseKeys []string
+	for _, ss := range searches {
+		ss.Options.IncludeArchived = false
+		cases, err := db.FindFilteredCases(ctl.ctx, ctl.appDb, ss.Options)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cases {
+			key := c.GetCaseKey()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			caseKeys = append(caseKeys, key)
+		}
+	}
+	if len(caseKeys) == 0 {
+		return nil, nil
+	}
+
+	return ctl.update(db.UpdateRunSourceSchedule, caseKeys, searchStartDate, maxSearchBack, exhaustSearch)
+}
+
+// Runs the general updater, recording the outcome as an update run
+func (ctl *AccordUpdaterCtl) update(
+	source string,
+	caseKeys []string,
+	searchStartDate time.Time,
+	maxSearchBack int,
+	exhaustSearch bool,
+) ([]string, error) {
+	_, notFoundKeys, err := ctl.generalUpdater.UpdateAndRecord(
+		ctl.ctx,
+		ctl.appDb,
+		source,
+		caseKeys,
+		searchStartDate,
+		maxSearchBack,
+		exhaustSearch,
+	)
+
+	return notFoundKeys, err
 }
 
 func (ctl *AccordUpdaterCtl) Startup(ctx context.Context, db *sql.DB) {
@@ -73,4 +147,23 @@ func (ctl *AccordUpdaterCtl) Startup(ctx context.Context, db *sql.DB) {
 	ctl.appDb = db
 
 	ctl.generalUpdater.SetStore(accupdter.NewDefaultCaseStore(ctx, db))
+
+	cache, err := fetchers.DefaultDocCache()
+	if err != nil {
+		fmt.Printf("DocCache Err: %v\n", err)
+	}
+	ctl.cache = cache
+}
+
+// Every row of the list published for the case type on the date, used
+// to browse it when the case number isn't known
+func (ctl *AccordUpdaterCtl) ReadBulletin(region, caseType string, date time.Time) (*accupdter.Bulletin, error) {
+	return ctl.generalUpdater.ReadBulletin(
+		ctl.ctx,
+		ctl.appDb,
+		internal.Region(region),
+		internal.CaseType(caseType),
+		date,
+		ctl.cache,
+	)
 }
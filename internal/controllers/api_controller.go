@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/api"
+)
+
+type ApiServerStatus struct {
+	Running bool   `json:"running"`
+	Addr    string `json:"addr"`
+	// Why the server could not be started, if it failed to
+	Error string `json:"error"`
+}
+
+// Runs the local API server while it is enabled in the settings
+type ApiController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+
+	mu       sync.Mutex
+	server   *api.Server
+	startErr error
+}
+
+func NewApiController() *ApiController {
+	return &ApiController{}
+}
+
+func (ctl *ApiController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+
+	s, err := api.LoadSettings(ctx, db)
+	if err != nil {
+		fmt.Printf("LoadApiSettings Err: %v\n", err)
+		return
+	}
+
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	ctl.restart(s)
+}
+
+// Stops the server, called when the app closes
+func (ctl *ApiController) Shutdown(ctx context.Context) {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	ctl.stop()
+}
+
+func (ctl *ApiController) FindApiSettings() (*api.Settings, error) {
+	return api.LoadSettings(ctl.ctx, ctl.appDb.Db)
+}
+
+// Saves the settings, starting or stopping the server to match them
+func (ctl *ApiController) SaveApiSettings(s *api.Settings) (*ApiServerStatus, error) {
+	if err := api.SaveSettings(ctl.ctx, ctl.appDb.Db, s); err != nil {
+		return nil, err
+	}
+
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	ctl.restart(s)
+
+	return ctl.status(), nil
+}
+
+// Replaces the token, clients using the previous one are rejected from then on
+func (ctl *ApiController) RegenerateApiToken() (*api.Settings, error) {
+	s, err := api.LoadSettings(ctl.ctx, ctl.appDb.Db)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Token = ""
+	if _, err := ctl.SaveApiSettings(s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (ctl *ApiController) ApiServerStatus() *ApiServerStatus {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+
+	return ctl.status()
+}
+
+func (ctl *ApiController) status() *ApiServerStatus {
+	st := &ApiServerStatus{Running: ctl.server != nil}
+	if ctl.server != nil {
+		st.Addr = ctl.server.Addr()
+	}
+	if ctl.startErr != nil {
+		st.Error = ctl.startErr.Error()
+	}
+
+	return st
+}
+
+// Stops the running server and starts a new one if s is enabled.
+//
+// Must be called with mu held
+func (ctl *ApiController) restart(s *api.Settings) {
+	ctl.stop()
+	if !s.Enabled {
+		return
+	}
+
+	srv, err := api.NewServer(ctl.ctx, ctl.appDb.Db, s)
+	if err == nil {
+		err = srv.Start()
+	}
+	if err != nil {
+		ctl.startErr = err
+		return
+	}
+
+	ctl.server = srv
+}
+
+// Must be called with mu held
+func (ctl *ApiController) stop() {
+	ctl.startErr = nil
+	if ctl.server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := ctl.server.Shutdown(ctx); err != nil {
+		fmt.Printf("ApiServer Shutdown Err: %v\n", err)
+	}
+	ctl.server = nil
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/accupdter"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/fetchers"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Event emitted with the *db.BackfillJob after every searched date,
+// and once more when the job stops
+const BackfillProgressEvent = "backfill:progress"
+
+var ErrBackfillRunning = errors.New("the backfill job is already running")
+
+type BackfillController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+
+	updater *accupdter.GeneralUpdater
+	cache   *fetchers.DocCache
+
+	mu sync.Mutex
+	// Cancels the jobs running in the app, by id
+	running map[string]context.CancelFunc
+}
+
+func NewBackfillController() *BackfillController {
+	return &BackfillController{
+		updater: accupdter.NewGeneralUpdater(&accupdter.GenUpdterConf{
+			Region:          internal.RegionDefault,
+			SearchStartDate: time.Now(),
+		}),
+		running: map[string]context.CancelFunc{},
+	}
+}
+
+func (ctl *BackfillController) Startup(ctx context.Context, appDb *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(appDb)
+
+	cache, err := fetchers.DefaultDocCache()
+	if err != nil {
+		fmt.Printf("DocCache Err: %v\n", err)
+	}
+	ctl.cache = cache
+
+	// Jobs left running were interrupted when the app closed
+	if err := db.PauseInterruptedBackfills(ctx, appDb); err != nil {
+		fmt.Printf("PauseInterruptedBackfills Err: %v\n", err)
+	}
+}
+
+func (ctl *BackfillController) FindBackfillJobs() ([]*db.BackfillJob, error) {
+	return db.FindBackfillJobs(ctl.ctx, ctl.appDb.Db)
+}
+
+func (ctl *BackfillController) FindBackfillJob(id string) (*db.BackfillJob, error) {
+	return db.FindBackfillJobById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Creates a job searching the bulletins from until back to since for
+// the accords of the cases, and starts it in the background
+func (ctl *BackfillController) StartBackfill(caseIds []string, since, until time.Time) (*db.BackfillJob, error) {
+	job, err := db.NewBackfillJob(caseIds, since, until)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.InsertBackfillJob(ctl.ctx, ctl.appDb.Db, job); err != nil {
+		return nil, err
+	}
+
+	return job, ctl.ResumeBackfill(job.Id)
+}
+
+// Same as StartBackfill, for the cases matching the filters
+func (ctl *BackfillController) FindCasesAndBackfill(findOpts *db.FindCaseOptions, since, until time.Time) (*db.BackfillJob, error) {
+	findOpts.IncludeArchived = false
+	findOpts.Limit = 0
+	cases, err := db.FindFilteredCases(ctl.ctx, ctl.appDb.Db, findOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	caseIds := make([]string, len(cases))
+	for i, c := range cases {
+		caseIds[i] = c.Id
+	}
+
+	return ctl.StartBackfill(caseIds, since, until)
+}
+
+// Runs the job from its checkpoints in the background
+func (ctl *BackfillController) ResumeBackfill(id string) error {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	if _, ok := ctl.running[id]; ok {
+		return ErrBackfillRunning
+	}
+
+	job, err := db.FindBackfillJobById(ctl.ctx, ctl.appDb.Db, id)
+	if err != nil {
+		return err
+	}
+	if !job.Resumable() {
+		return fmt.Errorf("%s is %s:\n\t%w", job.Id, job.Status, accupdter.ErrBackfillNotResumable)
+	}
+
+	runCtx, cancel := context.WithCancel(ctl.ctx)
+	ctl.running[id] = cancel
+
+	go func() {
+		defer func() {
+			ctl.mu.Lock()
+			delete(ctl.running, id)
+			ctl.mu.Unlock()
+			cancel()
+		}()
+
+		job, err := ctl.updater.RunBackfill(runCtx, ctl.appDb.Db, id, &accupdter.BackfillOpts{
+			Cache: ctl.cache,
+			Progress: func(job *db.BackfillJob) {
+				runtime.EventsEmit(ctl.ctx, BackfillProgressEvent, job)
+			},
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Printf("Backfill Err: %v\n", err)
+		}
+		if job != nil {
+			runtime.EventsEmit(ctl.ctx, BackfillProgressEvent, job)
+		}
+	}()
+
+	return nil
+}
+
+// Stops the job after the date being searched, it can be resumed later
+func (ctl *BackfillController) PauseBackfill(id string) {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+
+	if cancel, ok := ctl.running[id]; ok {
+		cancel()
+	}
+}
+
+func (ctl *BackfillController) DeleteBackfillJob(id string) error {
+	ctl.PauseBackfill(id)
+	return db.DeleteBackfillJobById(ctl.ctx, ctl.appDb.Db, id)
+}
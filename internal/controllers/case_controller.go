@@ -2,13 +2,28 @@ package controllers
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/vladwithcode/lex_app/internal"
 	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/export"
+	"github.com/vladwithcode/lex_app/internal/query"
 	"github.com/vladwithcode/lex_app/internal/readers"
+	"github.com/vladwithcode/lex_app/internal/report"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"golang.org/x/net/context"
 )
 
+var (
+	ErrPurgeCancelled  = errors.New("purge was cancelled by the user")
+	ErrExportCancelled = errors.New("export was cancelled by the user")
+)
+
 type CaseController struct {
 	ctx   context.Context
 	appDb *internal.AppDb
@@ -27,27 +42,48 @@ func (ctl *CaseController) FindAllCases() ([]*db.LexCase, error) {
 	return db.FindAllCases(ctl.ctx, ctl.appDb.Db)
 }
 
-func (ctl *CaseController) FindCases(opts *db.FindCaseOptions) ([]*db.LexCase, error) {
-	if opts != nil {
-		return db.FindFilteredCases(ctl.ctx, ctl.appDb.Db, opts)
+// Returns a page of the cases matching opts. Use the NextCursor of the
+// result as opts.Cursor to request the following page
+func (ctl *CaseController) FindCases(opts *db.FindCaseOptions) (*db.CasePage, error) {
+	if opts == nil {
+		defaultOpts := db.DefaultFindCaseOptions
+		opts = &defaultOpts
 	}
 
-	return db.FindAllCases(ctl.ctx, ctl.appDb.Db)
+	return db.FindCasesPage(ctl.ctx, ctl.appDb.Db, opts)
+}
+
+// Parses a structured search query, letting the UI point at the
+// offending token before running the search
+func (ctl *CaseController) ParseCaseQuery(q string) (*query.Query, error) {
+	return query.Parse(q)
 }
 
 func (ctl *CaseController) FindCaseById(id string) (*db.LexCase, error) {
 	return db.FindCaseById(ctl.ctx, ctl.appDb.Db, id)
 }
 
-func (ctl *CaseController) FindCase(caseId, caseType string) (*db.LexCase, error) {
+// Finds the case with the number and type in the region, the
+// default one when empty
+func (ctl *CaseController) FindCase(caseId, caseType, region string) (*db.LexCase, error) {
+	if region == "" {
+		region = string(internal.RegionDefault)
+	}
+
 	caseKey := caseId + readers.CaseKeySeparator + caseType
-	return db.FindCase(ctl.ctx, ctl.appDb.Db, caseKey)
+	return db.FindRegionCase(ctl.ctx, ctl.appDb.Db, region, caseKey)
 }
 
 func (ctl *CaseController) FindCaseWithAccords(id string, accordCount int) (*db.LexCase, error) {
 	return db.FindCaseWithAccords(ctl.ctx, ctl.appDb.Db, id, accordCount)
 }
 
+// Returns the case with its latest accords merged with its notes
+// and events in a single chronological timeline
+func (ctl *CaseController) FindCaseWithTimeline(id string, accordCount int) (*db.LexCase, error) {
+	return db.FindCaseWithTimeline(ctl.ctx, ctl.appDb.Db, id, accordCount)
+}
+
 func (ctl *CaseController) CreateCase(caseId, caseType, alias string) (*db.LexCase, error) {
 	newCase, err := db.NewCase(caseId, caseType)
 	if err != nil {
@@ -59,9 +95,282 @@ func (ctl *CaseController) CreateCase(caseId, caseType, alias string) (*db.LexCa
 		return nil, err
 	}
 
+	ctl.emit(db.WebhookEventCaseCreated, newCase)
+	return newCase, nil
+}
+
+// Same as CreateCase, for a court of the given region
+func (ctl *CaseController) CreateRegionCase(caseId, caseType, region, alias string) (*db.LexCase, error) {
+	newCase, err := db.NewRegionCase(caseId, caseType, region)
+	if err != nil {
+		return nil, err
+	}
+
+	newCase.Alias = alias
+	if err := db.InsertCase(ctl.ctx, ctl.appDb.Db, newCase); err != nil {
+		return nil, err
+	}
+
+	ctl.emit(db.WebhookEventCaseCreated, newCase)
 	return newCase, nil
 }
 
 func (ctl *CaseController) UpdateCase(id string, caseData *db.LexCase) error {
 	return db.UpdateCaseById(ctl.ctx, ctl.appDb.Db, id, caseData)
 }
+
+// Adds a note to the case. If accordId is not empty the note
+// is pinned to that accord
+func (ctl *CaseController) CreateCaseNote(caseId, content, accordId string) (*db.CaseNote, error) {
+	note, err := db.NewCaseNote(caseId, content)
+	if err != nil {
+		return nil, err
+	}
+
+	note.AccordId = accordId
+	if err := db.InsertCaseNote(ctl.ctx, ctl.appDb.Db, note); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+func (ctl *CaseController) UpdateCaseNote(id string, noteData *db.CaseNote) error {
+	return db.UpdateCaseNoteById(ctl.ctx, ctl.appDb.Db, id, noteData)
+}
+
+func (ctl *CaseController) DeleteCaseNote(id string) error {
+	return db.DeleteCaseNoteById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+func (ctl *CaseController) FindCaseNotes(caseId string) ([]*db.CaseNote, error) {
+	return db.FindNotesForCase(ctl.ctx, ctl.appDb.Db, caseId)
+}
+
+// Searches the content of the notes, limited to a single case
+// if caseId is not empty
+func (ctl *CaseController) SearchCaseNotes(search, caseId string) ([]*db.CaseNote, error) {
+	return db.SearchCaseNotes(ctl.ctx, ctl.appDb.Db, search, caseId)
+}
+
+// Full text search over the content of the accords of every case
+func (ctl *CaseController) SearchAccords(opts *db.AccordSearchOptions) ([]*db.AccordSearchResult, error) {
+	return db.SearchAccords(ctl.ctx, ctl.appDb.Db, opts)
+}
+
+// Schedules a hearing for the case, printed on its reports until it passes
+func (ctl *CaseController) CreateCaseHearing(caseId string, date time.Time, description string) (*db.CaseHearing, error) {
+	hearing, err := db.NewCaseHearing(caseId, date, description)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.InsertCaseHearing(ctl.ctx, ctl.appDb.Db, hearing); err != nil {
+		return nil, err
+	}
+
+	return hearing, nil
+}
+
+func (ctl *CaseController) DeleteCaseHearing(id string) error {
+	return db.DeleteCaseHearingById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Returns the hearings of the case from today onwards
+func (ctl *CaseController) FindUpcomingHearings(caseId string) ([]*db.CaseHearing, error) {
+	y, m, d := time.Now().Date()
+	return db.FindUpcomingHearings(ctl.ctx, ctl.appDb.Db, caseId, time.Date(y, m, d, 0, 0, 0, 0, time.Local))
+}
+
+func (ctl *CaseController) TagCases(caseIds, tagIds []string) error {
+	return db.TagCases(ctl.ctx, ctl.appDb.Db, caseIds, tagIds)
+}
+
+func (ctl *CaseController) UntagCases(caseIds, tagIds []string) error {
+	return db.UntagCases(ctl.ctx, ctl.appDb.Db, caseIds, tagIds)
+}
+
+func (ctl *CaseController) ArchiveCase(id string) error {
+	if err := db.ArchiveCaseById(ctl.ctx, ctl.appDb.Db, id); err != nil {
+		return err
+	}
+
+	c, err := db.FindCaseById(ctl.ctx, ctl.appDb.Db, id)
+	if err != nil {
+		return err
+	}
+
+	ctl.emit(db.WebhookEventCaseArchived, c)
+	return nil
+}
+
+func (ctl *CaseController) UnarchiveCase(id string) error {
+	return db.UnarchiveCaseById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Sends the case to the trash. See PurgeCase for permanent removal
+func (ctl *CaseController) DeleteCase(id string) error {
+	return db.DeleteCaseById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+func (ctl *CaseController) RestoreCase(id string) error {
+	return db.RestoreCaseById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Restores the case in the trash with the number and type in the region,
+// for when registering it again fails with db.ErrCaseInTrash
+func (ctl *CaseController) RestoreTrashedCase(caseId, caseType, region string) (*db.LexCase, error) {
+	if region == "" {
+		region = string(internal.RegionDefault)
+	}
+
+	id, err := db.FindTrashedCaseId(ctl.ctx, ctl.appDb.Db, caseId, caseType, region)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.RestoreCaseById(ctl.ctx, ctl.appDb.Db, id); err != nil {
+		return nil, err
+	}
+
+	return db.FindCaseById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+func (ctl *CaseController) FindDeletedCases() ([]*db.LexCase, error) {
+	return db.FindDeletedCases(ctl.ctx, ctl.appDb.Db)
+}
+
+// Permanently removes a case in the trash and all of its accords.
+//
+// The user is asked for confirmation through a native dialog
+func (ctl *CaseController) PurgeCase(id string) error {
+	c, err := db.FindCaseById(ctl.ctx, ctl.appDb.Db, id)
+	if err != nil {
+		return err
+	}
+	if c.DeletedAt == nil {
+		return db.ErrCaseNotInTrash
+	}
+
+	res, err := runtime.MessageDialog(ctl.ctx, runtime.MessageDialogOptions{
+		Type:  runtime.QuestionDialog,
+		Title: "Eliminar caso permanentemente",
+		Message: fmt.Sprintf(
+			"El caso %s (%s) y todos sus acuerdos serán eliminados. Esta acción no se puede deshacer.\n\n¿Deseas continuar?",
+			c.CaseId,
+			c.CaseType,
+		),
+		DefaultButton: "No",
+	})
+	if err != nil {
+		return err
+	}
+	if res != "Yes" {
+		return ErrPurgeCancelled
+	}
+
+	return db.PurgeCaseById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Returns the tracked cases without activity for longer than the
+// threshold of their type. A limit <= 0 returns every one of them
+func (ctl *CaseController) FindStaleCases(limit int) ([]*db.StaleCase, error) {
+	return db.FindStaleCases(ctl.ctx, ctl.appDb.Db, limit)
+}
+
+func (ctl *CaseController) FindStaleThresholds() ([]*db.StaleThreshold, error) {
+	return db.FindStaleThresholds(ctl.ctx, ctl.appDb.Db)
+}
+
+func (ctl *CaseController) SetStaleThreshold(caseType string, days int) error {
+	return db.SetStaleThreshold(ctl.ctx, ctl.appDb.Db, caseType, days)
+}
+
+func (ctl *CaseController) DeleteStaleThreshold(caseType string) error {
+	return db.DeleteStaleThreshold(ctl.ctx, ctl.appDb.Db, caseType)
+}
+
+// Exports the cases matching opts, asking the user where to save the
+// file through a native dialog. Returns the path of the written file
+func (ctl *CaseController) ExportCases(opts *db.FindCaseOptions, format string, includeAccords bool) (string, error) {
+	f, err := export.ParseFormat(format)
+	if err != nil {
+		return "", err
+	}
+
+	return ctl.saveFile("Exportar casos", export.FileName(f, time.Now()), string(f), func(w io.Writer) error {
+		return export.Export(ctl.ctx, ctl.appDb.Db, w, opts, &export.Options{
+			Format:         f,
+			IncludeAccords: includeAccords,
+		})
+	})
+}
+
+// Renders a printable PDF report of the case, with its full timeline
+// and notes, asking the user where to save it. Returns the path of the file
+func (ctl *CaseController) ExportCaseReport(id string) (string, error) {
+	cd, err := report.LoadCase(ctl.ctx, ctl.appDb.Db, id)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("reporte_%s_%s.pdf", strings.ReplaceAll(cd.Case.CaseId, "/", "-"), cd.Case.CaseType)
+	return ctl.saveReport(name, []*report.CaseData{cd})
+}
+
+// Same as ExportCaseReport for every tracked case of the client
+func (ctl *CaseController) ExportClientReport(clientId string) (string, error) {
+	client, err := db.FindClientById(ctl.ctx, ctl.appDb.Db, clientId)
+	if err != nil {
+		return "", err
+	}
+
+	cases, err := report.LoadClientCases(ctl.ctx, ctl.appDb.Db, clientId)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("reporte_%s.pdf", strings.ReplaceAll(strings.ToLower(client.Name), " ", "_"))
+	return ctl.saveReport(name, cases)
+}
+
+func (ctl *CaseController) saveReport(defaultName string, cases []*report.CaseData) (string, error) {
+	lh, err := report.LoadLetterhead()
+	if err != nil {
+		return "", err
+	}
+
+	return ctl.saveFile("Guardar reporte", defaultName, "pdf", func(w io.Writer) error {
+		return report.WriteCaseReport(w, cases, lh, time.Now())
+	})
+}
+
+// Asks the user where to save a file through a native dialog and writes it with write
+func (ctl *CaseController) saveFile(title, defaultName, ext string, write func(io.Writer) error) (string, error) {
+	path, err := runtime.SaveFileDialog(ctl.ctx, runtime.SaveDialogOptions{
+		Title:           title,
+		DefaultFilename: defaultName,
+		Filters: []runtime.FileFilter{
+			{DisplayName: strings.ToUpper(ext), Pattern: "*." + ext},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", ErrExportCancelled
+	}
+
+	if err := internal.WriteFileAtomic(path, write); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Notifies the webhooks subscribed to the event. Failing to do so
+// doesn't fail the change that caused it
+func (ctl *CaseController) emit(event string, data any) {
+	if err := webhooks.Emit(ctl.ctx, ctl.appDb.Db, event, data); err != nil {
+		fmt.Printf("Webhooks Err: %v\n", err)
+	}
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+const defaultRecentAccords = 20
+
+type ClientController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+}
+
+func NewClientController() *ClientController {
+	return &ClientController{}
+}
+
+func (ctl *ClientController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+}
+
+func (ctl *ClientController) FindAllClients(search string) ([]*db.Client, error) {
+	return db.FindAllClients(ctl.ctx, ctl.appDb.Db, search)
+}
+
+func (ctl *ClientController) FindClientById(id string) (*db.Client, error) {
+	return db.FindClientById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+func (ctl *ClientController) CreateClient(clientData *db.Client) (*db.Client, error) {
+	client, err := db.NewClient(clientData.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	clientData.Id = client.Id
+	clientData.CreatedAt = client.CreatedAt
+	if err := db.InsertClient(ctl.ctx, ctl.appDb.Db, clientData); err != nil {
+		return nil, err
+	}
+
+	return clientData, nil
+}
+
+func (ctl *ClientController) UpdateClient(id string, clientData *db.Client) error {
+	return db.UpdateClientById(ctl.ctx, ctl.appDb.Db, id, clientData)
+}
+
+func (ctl *ClientController) DeleteClient(id string) error {
+	return db.DeleteClientById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Lists every case the client is linked to
+func (ctl *ClientController) FindClientCases(clientId string) ([]*db.LexCase, error) {
+	return db.FindFilteredCases(ctl.ctx, ctl.appDb.Db, &db.FindCaseOptions{
+		ClientId:       clientId,
+		IncludeAccords: true,
+		MaxAccords:     1,
+	})
+}
+
+// Returns the latest accords across the client's cases.
+//
+// A limit of 0 or less uses the default of 20
+func (ctl *ClientController) FindClientRecentAccords(clientId string, limit int) ([]*db.CaseAccord, error) {
+	if limit <= 0 {
+		limit = defaultRecentAccords
+	}
+
+	return db.FindRecentAccordsForClient(ctl.ctx, ctl.appDb.Db, clientId, limit)
+}
+
+func (ctl *ClientController) LinkClientToCase(caseId, clientId string, role db.PartyRole) error {
+	return db.LinkClientToCase(ctl.ctx, ctl.appDb.Db, caseId, clientId, role)
+}
+
+func (ctl *ClientController) UnlinkClientFromCase(caseId, clientId string) error {
+	return db.UnlinkClientFromCase(ctl.ctx, ctl.appDb.Db, caseId, clientId)
+}
+
+func (ctl *ClientController) FindAllParties(search string) ([]*db.Party, error) {
+	return db.FindAllParties(ctl.ctx, ctl.appDb.Db, search)
+}
+
+func (ctl *ClientController) FindPartyById(id string) (*db.Party, error) {
+	return db.FindPartyById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+func (ctl *ClientController) CreateParty(partyData *db.Party) (*db.Party, error) {
+	party, err := db.NewParty(partyData.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	partyData.Id = party.Id
+	partyData.CreatedAt = party.CreatedAt
+	if err := db.InsertParty(ctl.ctx, ctl.appDb.Db, partyData); err != nil {
+		return nil, err
+	}
+
+	return partyData, nil
+}
+
+func (ctl *ClientController) UpdateParty(id string, partyData *db.Party) error {
+	return db.UpdatePartyById(ctl.ctx, ctl.appDb.Db, id, partyData)
+}
+
+func (ctl *ClientController) DeleteParty(id string) error {
+	return db.DeletePartyById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+func (ctl *ClientController) LinkPartyToCase(caseId, partyId string, role db.PartyRole) error {
+	return db.LinkPartyToCase(ctl.ctx, ctl.appDb.Db, caseId, partyId, role)
+}
+
+func (ctl *ClientController) UnlinkPartyFromCase(caseId, partyId string) error {
+	return db.UnlinkPartyFromCase(ctl.ctx, ctl.appDb.Db, caseId, partyId)
+}
+
+// Returns the clients and parties linked to the case
+func (ctl *ClientController) FindCaseParticipants(caseId string) (*db.CaseParticipants, error) {
+	return db.FindCaseParticipants(ctl.ctx, ctl.appDb.Db, caseId)
+}
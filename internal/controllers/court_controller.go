@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/regions"
+)
+
+type CourtController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+}
+
+func NewCourtController() *CourtController {
+	return &CourtController{}
+}
+
+func (ctl *CourtController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+}
+
+// Courts of every registered region
+func (ctl *CourtController) FindCourts() []*regions.Court {
+	return regions.Catalog()
+}
+
+// Courts of a single region. An empty region means the default one
+func (ctl *CourtController) FindRegionCourts(region string) ([]*regions.Court, error) {
+	r, err := regions.Find(internal.Region(region))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Courts, nil
+}
+
+func (ctl *CourtController) FindCourt(region, caseType string) (*regions.Court, error) {
+	return regions.FindCourt(internal.Region(region), internal.CaseType(caseType))
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/importer"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Event emitted while an import runs, with an ImportProgress as data
+const ImportProgressEvent = "import:progress"
+
+var ErrImportPreviewExpired = errors.New("the import preview is no longer available, preview the file again")
+
+// Cases processed between progress events
+const importProgressStep = 25
+
+type ImportProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type ImportController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+
+	mu sync.Mutex
+	// The last preview built, the only one that can be run
+	preview *importer.Preview
+}
+
+func NewImportController() *ImportController {
+	return &ImportController{}
+}
+
+func (ctl *ImportController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+}
+
+// Asks the user for the file to import through a native dialog.
+//
+// Returns an empty path if the dialog was cancelled
+func (ctl *ImportController) SelectImportFile() (string, error) {
+	return runtime.OpenFileDialog(ctl.ctx, runtime.OpenDialogOptions{
+		Title: "Importar casos",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Hojas de cálculo, CSV y JSON", Pattern: "*.xlsx;*.csv;*.json"},
+		},
+	})
+}
+
+// Reads the source and reports what importing it would do, without
+// writing anything. The preview is kept to be run by RunImport
+func (ctl *ImportController) PreviewImport(src *importer.Source, upsert bool) (*importer.Preview, error) {
+	p, err := importer.BuildPreview(ctl.ctx, ctl.appDb.Db, src, upsert)
+	if err != nil {
+		return nil, err
+	}
+
+	ctl.mu.Lock()
+	ctl.preview = p
+	ctl.mu.Unlock()
+
+	return p, nil
+}
+
+// Imports the cases read for the preview with the id in a single
+// transaction, emitting ImportProgressEvent as they are processed.
+//
+// The source isn't read again, so the import writes what was previewed.
+// Fails with ErrImportPreviewExpired unless previewId is the last preview
+func (ctl *ImportController) RunImport(previewId string) (*importer.Report, error) {
+	ctl.mu.Lock()
+	p := ctl.preview
+	if p == nil || p.Id != previewId {
+		ctl.mu.Unlock()
+		return nil, ErrImportPreviewExpired
+	}
+	ctl.preview = nil
+	ctl.mu.Unlock()
+
+	return importer.Import(ctl.ctx, ctl.appDb.Db, p.Cases(), &importer.Options{
+		Upsert: p.Upsert,
+		Progress: func(done, total int) {
+			if done%importProgressStep == 0 || done == total {
+				runtime.EventsEmit(ctl.ctx, ImportProgressEvent, ImportProgress{Done: done, Total: total})
+			}
+		},
+	})
+}
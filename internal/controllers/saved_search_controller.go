@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+type SavedSearchController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+}
+
+func NewSavedSearchController() *SavedSearchController {
+	return &SavedSearchController{}
+}
+
+func (ctl *SavedSearchController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+}
+
+// Returns every saved search along with the number of cases it matches
+func (ctl *SavedSearchController) FindSavedSearches() ([]*db.SavedSearchCount, error) {
+	return db.FindSavedSearchCounts(ctl.ctx, ctl.appDb.Db)
+}
+
+func (ctl *SavedSearchController) FindSavedSearch(id string) (*db.SavedSearch, error) {
+	return db.FindSavedSearchById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+func (ctl *SavedSearchController) CreateSavedSearch(name string, opts *db.FindCaseOptions, scheduled bool) (*db.SavedSearch, error) {
+	ss, err := db.NewSavedSearch(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	ss.Scheduled = scheduled
+
+	if err := db.InsertSavedSearch(ctl.ctx, ctl.appDb.Db, ss); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+func (ctl *SavedSearchController) UpdateSavedSearch(id string, ssData *db.SavedSearch) error {
+	return db.UpdateSavedSearchById(ctl.ctx, ctl.appDb.Db, id, ssData)
+}
+
+func (ctl *SavedSearchController) DeleteSavedSearch(id string) error {
+	return db.DeleteSavedSearchById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Runs the saved search, returning a single page of its cases.
+//
+// cursor is the NextCursor of the previous page, empty for the first one
+func (ctl *SavedSearchController) RunSavedSearch(id string, limit int, cursor string, includeAccords bool) (*db.CasePage, error) {
+	ss, err := db.FindSavedSearchById(ctl.ctx, ctl.appDb.Db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := *ss.Options
+	opts.Limit = limit
+	opts.Cursor = cursor
+	opts.IncludeAccords = includeAccords
+
+	return db.FindCasesPage(ctl.ctx, ctl.appDb.Db, &opts)
+}
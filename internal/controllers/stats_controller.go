@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+type StatsController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+}
+
+func NewStatsController() *StatsController {
+	return &StatsController{}
+}
+
+func (ctl *StatsController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+}
+
+func (ctl *StatsController) FindDashboardStats(opts *db.StatsOptions) (*db.DashboardStats, error) {
+	return db.FindDashboardStats(ctl.ctx, ctl.appDb.Db, opts)
+}
+
+// Returns the latest update runs, a limit <= 0 returns all of them
+func (ctl *StatsController) FindUpdateRuns(limit int) ([]*db.UpdateRun, error) {
+	return db.FindUpdateRuns(ctl.ctx, ctl.appDb.Db, limit)
+}
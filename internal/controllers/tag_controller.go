@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+type TagController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+}
+
+func NewTagController() *TagController {
+	return &TagController{}
+}
+
+func (ctl *TagController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+}
+
+func (ctl *TagController) FindAllTags() ([]*db.Tag, error) {
+	return db.FindAllTags(ctl.ctx, ctl.appDb.Db)
+}
+
+func (ctl *TagController) CreateTag(name, color string) (*db.Tag, error) {
+	tag, err := db.NewTag(name, color)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.InsertTag(ctl.ctx, ctl.appDb.Db, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (ctl *TagController) UpdateTag(id string, tagData *db.Tag) error {
+	return db.UpdateTagById(ctl.ctx, ctl.appDb.Db, id, tagData)
+}
+
+func (ctl *TagController) DeleteTag(id string) error {
+	return db.DeleteTagById(ctl.ctx, ctl.appDb.Db, id)
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/accupdter"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
+)
+
+type WatchlistController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+
+	updater *accupdter.GeneralUpdater
+}
+
+func NewWatchlistController() *WatchlistController {
+	return &WatchlistController{
+		updater: accupdter.NewGeneralUpdater(&accupdter.GenUpdterConf{
+			Region:          internal.RegionDefault,
+			SearchStartDate: time.Now(),
+			MaxSearchBack:   0,
+		}),
+	}
+}
+
+func (ctl *WatchlistController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+}
+
+func (ctl *WatchlistController) FindWatchlist(includePromoted bool) ([]*db.WatchItem, error) {
+	return db.FindWatchItems(ctl.ctx, ctl.appDb.Db, includePromoted)
+}
+
+func (ctl *WatchlistController) FindWatchItem(id string) (*db.WatchItem, error) {
+	return db.FindWatchItemById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+func (ctl *WatchlistController) CreateWatchItem(caseId, caseType, region, note string) (*db.WatchItem, error) {
+	w, err := db.NewWatchItem(caseId, caseType, region, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.InsertWatchItem(ctl.ctx, ctl.appDb.Db, w); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (ctl *WatchlistController) UpdateWatchItemNote(id, note string) error {
+	return db.UpdateWatchItemNote(ctl.ctx, ctl.appDb.Db, id, note)
+}
+
+func (ctl *WatchlistController) DeleteWatchItem(id string) error {
+	return db.DeleteWatchItemById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Searches the bulletins for every watched case, from today back
+// the given number of days
+func (ctl *WatchlistController) CheckWatchlist(maxSearchBack int) (*accupdter.WatchCheck, error) {
+	return ctl.updater.CheckWatchlist(ctl.ctx, ctl.appDb.Db, time.Now(), maxSearchBack)
+}
+
+// Turns the watched item into a case, copying its hits as accords.
+// If the case already exists the hits are added to it
+func (ctl *WatchlistController) PromoteWatchItem(id, alias string) (*db.LexCase, error) {
+	c, created, err := db.PromoteWatchItem(ctl.ctx, ctl.appDb.Db, id, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		if err := webhooks.Emit(ctl.ctx, ctl.appDb.Db, db.WebhookEventCaseCreated, c); err != nil {
+			fmt.Printf("Webhooks Err: %v\n", err)
+		}
+	}
+
+	return c, nil
+}
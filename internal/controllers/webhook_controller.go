@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
+)
+
+type WebhookController struct {
+	ctx   context.Context
+	appDb *internal.AppDb
+}
+
+func NewWebhookController() *WebhookController {
+	return &WebhookController{}
+}
+
+// Resumes the deliveries left pending when the app was last closed
+func (ctl *WebhookController) Startup(ctx context.Context, db *sql.DB) {
+	ctl.ctx = ctx
+	ctl.appDb = internal.NewAppDb(db)
+
+	if err := webhooks.ResumePending(ctx, db); err != nil {
+		fmt.Printf("ResumePending Err: %v\n", err)
+	}
+}
+
+// Names of the events webhooks can subscribe to
+func (ctl *WebhookController) FindWebhookEvents() []string {
+	return db.AllWebhookEvents
+}
+
+func (ctl *WebhookController) FindAllWebhooks() ([]*db.Webhook, error) {
+	return db.FindAllWebhooks(ctl.ctx, ctl.appDb.Db)
+}
+
+// Creates an enabled webhook with a random secret
+func (ctl *WebhookController) CreateWebhook(url, description string, events []string) (*db.Webhook, error) {
+	wh, err := db.NewWebhook(url, events)
+	if err != nil {
+		return nil, err
+	}
+
+	wh.Description = description
+	if err := db.InsertWebhook(ctl.ctx, ctl.appDb.Db, wh); err != nil {
+		return nil, err
+	}
+
+	return wh, nil
+}
+
+func (ctl *WebhookController) UpdateWebhook(id string, whData *db.Webhook) error {
+	return db.UpdateWebhookById(ctl.ctx, ctl.appDb.Db, id, whData)
+}
+
+func (ctl *WebhookController) DeleteWebhook(id string) error {
+	return db.DeleteWebhookById(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Sends a test event to the webhook and returns the logged delivery
+func (ctl *WebhookController) SendTestWebhook(id string) (*db.WebhookDelivery, error) {
+	return webhooks.SendTest(ctl.ctx, ctl.appDb.Db, id)
+}
+
+// Returns the latest deliveries of the webhook, or of every webhook
+// if webhookId is empty
+func (ctl *WebhookController) FindWebhookDeliveries(webhookId string, limit int) ([]*db.WebhookDelivery, error) {
+	return db.FindWebhookDeliveries(ctl.ctx, ctl.appDb.Db, webhookId, limit)
+}
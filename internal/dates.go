@@ -6,3 +6,20 @@ var (
 	Day     time.Duration = 24 * time.Hour
 	DayBack time.Duration = -1 * Day
 )
+
+// Local midnight of the day of t
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// Calendar days from the day of since to the day of until. Unlike
+// dividing their difference by Day, it isn't thrown off by DST changes
+func DaysBetween(since, until time.Time) int {
+	sy, sm, sd := since.Date()
+	uy, um, ud := until.Date()
+	from := time.Date(sy, sm, sd, 0, 0, 0, 0, time.UTC)
+	to := time.Date(uy, um, ud, 0, 0, 0, 0, time.UTC)
+
+	return int(to.Sub(from) / Day)
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,14 @@ type Accord struct {
 	rawData string
 }
 
+// An accord along with the identifying data of the case it belongs to
+type CaseAccord struct {
+	Accord   *Accord `json:"accord"`
+	CaseId   string  `json:"caseId"`
+	CaseType string  `json:"caseType"`
+	Alias    string  `json:"alias"`
+}
+
 func NewAccord(caseId string) *Accord {
 	return &Accord{
 		Id:      uuid.Must(uuid.NewV7()).String(),
@@ -91,17 +100,17 @@ func FindLatestAccordForCase(ctx context.Context, appDb *sql.DB, caseId string)
 	return &accord, nil
 }
 
-func InsertAccord(ctx context.Context, appDb *sql.DB, accord *Accord) error {
+func InsertAccord(ctx context.Context, appDb DBTX, accord *Accord) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	_, err := appDb.ExecContext(
 		ctx,
-		"INSERT INTO accords (id, for_case, content, date, raw_data) VALUES @Id, @ForCase, @Content, @Date, @RawData",
+		"INSERT INTO accords (id, for_case, content, date, raw_data) VALUES (:Id, :ForCase, :Content, :Date, :RawData)",
 		sql.Named("Id", accord.Id),
 		sql.Named("ForCase", accord.ForCase),
 		sql.Named("Content", accord.Content),
-		sql.Named("Date", accord.Date),
+		sql.Named("Date", accord.Date.Unix()),
 		sql.Named("RawData", accord.rawData),
 	)
 	if err != nil {
@@ -110,3 +119,70 @@ func InsertAccord(ctx context.Context, appDb *sql.DB, accord *Accord) error {
 
 	return nil
 }
+
+// Returns the full accord history of every case in caseIds,
+// grouped by case and oldest first
+func FindAccordsForCases(ctx context.Context, appDb *sql.DB, caseIds []string) ([]*CaseAccord, error) {
+	if len(caseIds) == 0 {
+		return []*CaseAccord{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	inList, args := namedInArgs("caseId", caseIds)
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT accords.id, accords.for_case, accords.content, CAST(accords.date AS INTEGER), cases.case_id, cases.case_type, cases.alias
+			FROM accords
+			INNER JOIN cases ON cases.id = accords.for_case
+			WHERE accords.for_case IN (%s)
+			ORDER BY cases.case_type, cases.case_id, accords.date`,
+			inList,
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCaseAccords(rows)
+}
+
+// Scans rows selecting, in order: accord id, for_case, content,
+// date as unix epoch, case_id, case_type and alias
+func scanCaseAccords(rows *sql.Rows) ([]*CaseAccord, error) {
+	accords := []*CaseAccord{}
+	for rows.Next() {
+		var (
+			ca     = &CaseAccord{Accord: &Accord{}}
+			date   sql.NullInt64
+			nAlias sql.NullString
+		)
+		err := rows.Scan(
+			&ca.Accord.Id,
+			&ca.Accord.ForCase,
+			&ca.Accord.Content,
+			&date,
+			&ca.CaseId,
+			&ca.CaseType,
+			&nAlias,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		ca.Alias = nAlias.String
+		ca.Accord.Date = time.Unix(date.Int64, 0)
+		ca.Accord.DateStr = ca.Accord.Date.Format(time.RFC3339)
+		accords = append(accords, ca)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accords, nil
+}
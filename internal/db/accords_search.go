@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Markers wrapping the matched terms within a snippet. They are control
+// characters instead of markup, as the snippet is accord text from the
+// bulletins and isn't escaped. Clients highlight the text between them
+const (
+	SnippetMatchStart = "\x02"
+	SnippetMatchEnd   = "\x03"
+)
+
+// Approximate number of tokens included in each snippet
+const snippetTokens = 24
+
+const defaultAccordSearchLimit = 50
+
+var ErrEmptySearch = errors.New("search can't be empty")
+
+type AccordSearchOptions struct {
+	Search string
+	// Dates formatted as 2006-01-02. Both ends are inclusive
+	From     string
+	To       string
+	CaseType string
+	Limit    int
+}
+
+// An accord matching a search, with a highlighted excerpt
+// of its content
+type AccordSearchResult struct {
+	Accord   *Accord `json:"accord"`
+	CaseId   string  `json:"caseId"`
+	CaseType string  `json:"caseType"`
+	Alias    string  `json:"alias"`
+	Snippet  string  `json:"snippet"`
+	Rank     float64 `json:"rank"`
+}
+
+// Searches the content of every accord, best matches first
+func SearchAccords(ctx context.Context, appDb *sql.DB, opts *AccordSearchOptions) ([]*AccordSearchResult, error) {
+	if opts == nil || strings.TrimSpace(opts.Search) == "" {
+		return nil, ErrEmptySearch
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 8*time.Second)
+	defer cancel()
+
+	conditions := []string{"accords_fts MATCH :search", "cases.deleted_at IS NULL"}
+	args := []interface{}{
+		sql.Named("search", quoteFtsTerms(opts.Search)),
+		sql.Named("snippetStart", SnippetMatchStart),
+		sql.Named("snippetEnd", SnippetMatchEnd),
+		sql.Named("snippetTokens", snippetTokens),
+	}
+
+	if opts.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", opts.From, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid From date %q: %w", opts.From, err)
+		}
+		conditions = append(conditions, "unixepoch(accords.date, 'unixepoch') >= :from")
+		args = append(args, sql.Named("from", from.Unix()))
+	}
+	if opts.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", opts.To, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid To date %q: %w", opts.To, err)
+		}
+		conditions = append(conditions, "unixepoch(accords.date, 'unixepoch') < :to")
+		args = append(args, sql.Named("to", to.Add(24*time.Hour).Unix()))
+	}
+	if opts.CaseType != "" {
+		conditions = append(conditions, "cases.case_type = :caseType")
+		args = append(args, sql.Named("caseType", opts.CaseType))
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultAccordSearchLimit
+	}
+	args = append(args, sql.Named("limit", limit))
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT
+				accords.id,
+				accords.for_case,
+				accords.content,
+				unixepoch(accords.date, 'unixepoch') as date,
+				cases.case_id,
+				cases.case_type,
+				cases.alias,
+				snippet(accords_fts, 2, :snippetStart, :snippetEnd, '…', :snippetTokens),
+				bm25(accords_fts) as rank
+			FROM accords_fts
+			INNER JOIN accords ON accords.id = accords_fts.uuid
+			INNER JOIN cases ON cases.id = accords.for_case
+			WHERE %s
+			ORDER BY rank, date DESC
+			LIMIT :limit`,
+			strings.Join(conditions, " AND "),
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*AccordSearchResult{}
+	for rows.Next() {
+		var (
+			r      = &AccordSearchResult{Accord: &Accord{}}
+			date   sql.NullInt64
+			nAlias sql.NullString
+		)
+		err := rows.Scan(
+			&r.Accord.Id,
+			&r.Accord.ForCase,
+			&r.Accord.Content,
+			&date,
+			&r.CaseId,
+			&r.CaseType,
+			&nAlias,
+			&r.Snippet,
+			&r.Rank,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Alias = nAlias.String
+		r.Accord.Date = time.Unix(date.Int64, 0)
+		r.Accord.DateStr = r.Accord.Date.Format(time.RFC3339)
+		results = append(results, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,466 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+var (
+	ErrBackfillNotFound     = errors.New("backfill job not found")
+	ErrBackfillNoCases      = errors.New("a backfill job needs at least one case")
+	ErrBackfillInvalidRange = errors.New("the backfill range must start before it ends")
+)
+
+type BackfillStatus string
+
+const (
+	BackfillPending BackfillStatus = "pending"
+	BackfillRunning BackfillStatus = "running"
+	// Interrupted before searching every date, it can be resumed
+	BackfillPaused BackfillStatus = "paused"
+	BackfillDone   BackfillStatus = "done"
+	BackfillFailed BackfillStatus = "failed"
+)
+
+// Search of the bulletins published between two dates for the accords
+// of some cases. Progress is saved as it goes, see BackfillCheckpoint
+type BackfillJob struct {
+	Id     string         `json:"id" db:"id"`
+	Status BackfillStatus `json:"status" db:"status"`
+	// Oldest and newest dates searched
+	Since       time.Time  `json:"since" db:"since_date"`
+	Until       time.Time  `json:"until" db:"until_date"`
+	AccordCount int        `json:"accordCount" db:"accord_count"`
+	Error       string     `json:"error" db:"error"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
+	FinishedAt  *time.Time `json:"finishedAt" db:"finished_at"`
+
+	CaseIds     []string              `json:"caseIds"`
+	Checkpoints []*BackfillCheckpoint `json:"checkpoints"`
+	// Dates to search over every checkpoint, and the ones already searched
+	TotalDays    int `json:"totalDays"`
+	SearchedDays int `json:"searchedDays"`
+}
+
+// Progress of a job over the bulletins of one region and case type
+type BackfillCheckpoint struct {
+	Region   string `json:"region" db:"region"`
+	CaseType string `json:"caseType" db:"case_type"`
+	// Next date to search. Once it's before the job's Since
+	// the checkpoint is complete
+	NextDate     time.Time `json:"nextDate" db:"next_date"`
+	SearchedDays int       `json:"searchedDays" db:"searched_days"`
+	// Dates without a published bulletin
+	FailedDays int `json:"failedDays" db:"failed_days"`
+}
+
+// Creates a job searching from until back to since. Both are
+// truncated to their day
+func NewBackfillJob(caseIds []string, since, until time.Time) (*BackfillJob, error) {
+	if len(caseIds) == 0 {
+		return nil, ErrBackfillNoCases
+	}
+
+	// Dates may come in UTC from the frontend
+	since, until = internal.StartOfDay(since), internal.StartOfDay(until)
+	if since.After(until) {
+		return nil, ErrBackfillInvalidRange
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	now := time.Now()
+	return &BackfillJob{
+		Id:          id.String(),
+		Status:      BackfillPending,
+		Since:       since,
+		Until:       until,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CaseIds:     caseIds,
+		Checkpoints: []*BackfillCheckpoint{},
+	}, nil
+}
+
+// Whether every date of the checkpoint was searched
+func (cp *BackfillCheckpoint) Done(job *BackfillJob) bool {
+	return cp.NextDate.Before(job.Since)
+}
+
+// Whether the job can be started or resumed
+func (job *BackfillJob) Resumable() bool {
+	return job.Status == BackfillPending || job.Status == BackfillPaused || job.Status == BackfillFailed
+}
+
+// Sets TotalDays and SearchedDays from the checkpoints
+func (job *BackfillJob) countDays() {
+	days := internal.DaysBetween(job.Since, job.Until) + 1
+	job.TotalDays = days * len(job.Checkpoints)
+	job.SearchedDays = 0
+	for _, cp := range job.Checkpoints {
+		job.SearchedDays += cp.SearchedDays
+	}
+}
+
+// Stores the job with a checkpoint for every region and case type of
+// its cases. Cases that don't exist are ignored
+func InsertBackfillJob(ctx context.Context, appDb *sql.DB, job *BackfillJob) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tx, err := appDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO backfill_jobs (id, status, since_date, until_date, created_at, updated_at)
+		VALUES (:Id, :Status, :Since, :Until, :CreatedAt, :UpdatedAt)`,
+		sql.Named("Id", job.Id),
+		sql.Named("Status", job.Status),
+		sql.Named("Since", job.Since.Unix()),
+		sql.Named("Until", job.Until.Unix()),
+		sql.Named("CreatedAt", job.CreatedAt.Unix()),
+		sql.Named("UpdatedAt", job.UpdatedAt.Unix()),
+	)
+	if err != nil {
+		return err
+	}
+
+	inList, args := namedInArgs("case", job.CaseIds)
+	args = append(args, sql.Named("JobId", job.Id))
+	_, err = tx.ExecContext(
+		ctx,
+		fmt.Sprintf("INSERT INTO backfill_job_cases (job_id, case_id) SELECT :JobId, id FROM cases WHERE id IN (%s)", inList),
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+
+	args = append(
+		args,
+		sql.Named("Until", job.Until.Unix()),
+		sql.Named("DefaultRegion", internal.RegionDefault),
+	)
+	_, err = tx.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO backfill_checkpoints (job_id, region, case_type, next_date)
+			SELECT DISTINCT :JobId, coalesce(nullif(region, ''), :DefaultRegion), case_type, :Until
+			FROM cases WHERE id IN (%s)`,
+			inList,
+		),
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+
+	found, err := findBackfillJob(ctx, tx, job.Id)
+	if err != nil {
+		return err
+	}
+	if len(found.CaseIds) == 0 {
+		return ErrBackfillNoCases
+	}
+	*job = *found
+
+	return tx.Commit()
+}
+
+// Returns every job, newest first, with its checkpoints but not its cases
+func FindBackfillJobs(ctx context.Context, appDb *sql.DB) ([]*BackfillJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	jobs, err := findBackfillJobs(ctx, appDb, "1 = 1 ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		if err := findBackfillCheckpoints(ctx, appDb, job); err != nil {
+			return nil, err
+		}
+	}
+
+	return jobs, nil
+}
+
+func FindBackfillJobById(ctx context.Context, appDb DBTX, id string) (*BackfillJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return findBackfillJob(ctx, appDb, id)
+}
+
+// Returns the cases of the job with their region
+func FindBackfillCases(ctx context.Context, appDb *sql.DB, jobId string) ([]*LexCase, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT cases.id, cases.case_id, cases.case_type, coalesce(nullif(cases.region, ''), :DefaultRegion)
+		FROM backfill_job_cases
+		JOIN cases ON cases.id = backfill_job_cases.case_id
+		WHERE backfill_job_cases.job_id = :JobId`,
+		sql.Named("JobId", jobId),
+		sql.Named("DefaultRegion", internal.RegionDefault),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cases := []*LexCase{}
+	for rows.Next() {
+		c := &LexCase{}
+		if err := rows.Scan(&c.Id, &c.CaseId, &c.CaseType, &c.Region); err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+
+	return cases, rows.Err()
+}
+
+func findBackfillJob(ctx context.Context, appDb DBTX, id string) (*BackfillJob, error) {
+	jobs, err := findBackfillJobs(ctx, appDb, "id = :Id", sql.Named("Id", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrBackfillNotFound
+	}
+	job := jobs[0]
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		"SELECT case_id FROM backfill_job_cases WHERE job_id = :Id",
+		sql.Named("Id", id),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var caseId string
+		if err := rows.Scan(&caseId); err != nil {
+			return nil, err
+		}
+		job.CaseIds = append(job.CaseIds, caseId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return job, findBackfillCheckpoints(ctx, appDb, job)
+}
+
+func findBackfillJobs(ctx context.Context, appDb DBTX, where string, args ...interface{}) ([]*BackfillJob, error) {
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT id, status, since_date, until_date, accord_count, error, created_at, updated_at, finished_at
+		FROM backfill_jobs
+		WHERE `+where,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*BackfillJob{}
+	for rows.Next() {
+		var (
+			job                                = &BackfillJob{CaseIds: []string{}}
+			since, until, createdAt, updatedAt int64
+			finishedAt                         sql.NullInt64
+		)
+		err := rows.Scan(
+			&job.Id,
+			&job.Status,
+			&since,
+			&until,
+			&job.AccordCount,
+			&job.Error,
+			&createdAt,
+			&updatedAt,
+			&finishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		job.Since = time.Unix(since, 0)
+		job.Until = time.Unix(until, 0)
+		job.CreatedAt = time.Unix(createdAt, 0)
+		job.UpdatedAt = time.Unix(updatedAt, 0)
+		job.FinishedAt = nullUnixToTime(finishedAt)
+
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func findBackfillCheckpoints(ctx context.Context, appDb DBTX, job *BackfillJob) error {
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT region, case_type, next_date, searched_days, failed_days
+		FROM backfill_checkpoints
+		WHERE job_id = :JobId
+		ORDER BY region, case_type`,
+		sql.Named("JobId", job.Id),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	job.Checkpoints = []*BackfillCheckpoint{}
+	for rows.Next() {
+		cp := &BackfillCheckpoint{}
+		var nextDate int64
+		if err := rows.Scan(&cp.Region, &cp.CaseType, &nextDate, &cp.SearchedDays, &cp.FailedDays); err != nil {
+			return err
+		}
+		cp.NextDate = time.Unix(nextDate, 0)
+		job.Checkpoints = append(job.Checkpoints, cp)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	job.countDays()
+	return nil
+}
+
+// Persists the progress of a checkpoint along with the accords found
+// since the last save, so an interrupted job resumes from there
+func SaveBackfillCheckpoint(ctx context.Context, appDb *sql.DB, job *BackfillJob, cp *BackfillCheckpoint, newAccords int) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := appDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE backfill_checkpoints
+		SET next_date = :NextDate, searched_days = :SearchedDays, failed_days = :FailedDays
+		WHERE job_id = :JobId AND region = :Region AND case_type = :CaseType`,
+		sql.Named("NextDate", cp.NextDate.Unix()),
+		sql.Named("SearchedDays", cp.SearchedDays),
+		sql.Named("FailedDays", cp.FailedDays),
+		sql.Named("JobId", job.Id),
+		sql.Named("Region", cp.Region),
+		sql.Named("CaseType", cp.CaseType),
+	)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE backfill_jobs SET accord_count = accord_count + :New, updated_at = :Now WHERE id = :Id",
+		sql.Named("New", newAccords),
+		sql.Named("Now", now.Unix()),
+		sql.Named("Id", job.Id),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	job.AccordCount += newAccords
+	job.UpdatedAt = now
+	job.countDays()
+	return nil
+}
+
+// Sets the status of the job. Jobs that are done or failed are
+// marked as finished
+func SetBackfillStatus(ctx context.Context, appDb *sql.DB, job *BackfillJob, status BackfillStatus, errMsg string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var finishedAt *time.Time
+	if status == BackfillDone || status == BackfillFailed {
+		finishedAt = &now
+	}
+
+	res, err := appDb.ExecContext(
+		ctx,
+		`UPDATE backfill_jobs
+		SET status = :Status, error = :Error, updated_at = :Now, finished_at = :FinishedAt
+		WHERE id = :Id`,
+		sql.Named("Status", status),
+		sql.Named("Error", errMsg),
+		sql.Named("Now", now.Unix()),
+		sql.Named("FinishedAt", timeToNullUnix(finishedAt)),
+		sql.Named("Id", job.Id),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrBackfillNotFound
+	}
+
+	job.Status = status
+	job.Error = errMsg
+	job.UpdatedAt = now
+	job.FinishedAt = finishedAt
+	return nil
+}
+
+// Marks the jobs left running as paused. Meant to be called on startup,
+// when no job can be running anymore
+func PauseInterruptedBackfills(ctx context.Context, appDb *sql.DB) error {
+	_, err := appDb.ExecContext(
+		ctx,
+		"UPDATE backfill_jobs SET status = :Paused WHERE status = :Running",
+		sql.Named("Paused", BackfillPaused),
+		sql.Named("Running", BackfillRunning),
+	)
+
+	return err
+}
+
+func DeleteBackfillJobById(ctx context.Context, appDb *sql.DB, id string) error {
+	res, err := appDb.ExecContext(ctx, "DELETE FROM backfill_jobs WHERE id = :Id", sql.Named("Id", id))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrBackfillNotFound
+	}
+
+	return nil
+}
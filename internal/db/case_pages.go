@@ -0,0 +1,397 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal/query"
+)
+
+var (
+	ErrInvalidCursor    = errors.New("the cursor is invalid or doesn't match the sort options")
+	ErrInvalidSortField = errors.New("unknown sort field")
+)
+
+type CaseSortField string
+
+const (
+	CaseSortCaseNo      CaseSortField = "caseNo"
+	CaseSortYear        CaseSortField = "year"
+	CaseSortAlias       CaseSortField = "alias"
+	CaseSortLastAccord  CaseSortField = "lastAccord"
+	CaseSortLastChecked CaseSortField = "lastChecked"
+)
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+var caseSortExprs = map[CaseSortField]string{
+	CaseSortCaseNo:      "CAST(cases.case_no AS INTEGER)",
+	CaseSortYear:        "CAST(cases.case_year AS INTEGER)",
+	CaseSortAlias:       "lower(coalesce(cases.alias, ''))",
+	CaseSortLastAccord:  "coalesce((SELECT max(unixepoch(accords.date, 'unixepoch')) FROM accords WHERE accords.for_case = cases.id), 0)",
+	CaseSortLastChecked: "coalesce(cases.last_checked_at, 0)",
+}
+
+// A single page of a case listing
+type CasePage struct {
+	Cases []*LexCase `json:"cases"`
+	// Number of cases matching the filters across every page
+	Total int `json:"total"`
+	// Empty when there are no more pages
+	NextCursor string `json:"nextCursor"`
+}
+
+// Position of the last case of a page within the sorted listing
+type caseCursor struct {
+	SortBy    CaseSortField `json:"s"`
+	SortOrder SortOrder     `json:"o"`
+	Value     any           `json:"v"`
+	Id        string        `json:"id"`
+}
+
+func (cur *caseCursor) encode() string {
+	b, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCaseCursor(s string, sortBy CaseSortField, order SortOrder) (*caseCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	cur := &caseCursor{}
+	if err := dec.Decode(cur); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cur.SortBy != sortBy || cur.SortOrder != order || cur.Id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	// Every sort value but the alias is numeric
+	if n, ok := cur.Value.(json.Number); ok {
+		v, err := n.Int64()
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cur.Value = v
+	}
+
+	return cur, nil
+}
+
+// Counts the cases matching the filters in opts
+func CountCases(ctx context.Context, appDb *sql.DB, opts *FindCaseOptions) (int, error) {
+	if opts == nil {
+		return 0, ErrNilOpts
+	}
+
+	join, conditions, args, err := caseFilters(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var n int
+	err = appDb.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT count(*) FROM cases %s WHERE %s", join, strings.Join(conditions, " AND ")),
+		args...,
+	).Scan(&n)
+
+	return n, err
+}
+
+// Returns a page of the cases matching opts along with the
+// total count of matching cases
+func FindCasesPage(ctx context.Context, appDb *sql.DB, opts *FindCaseOptions) (*CasePage, error) {
+	return findCasesPage(ctx, appDb, opts, true)
+}
+
+func findCasesPage(ctx context.Context, appDb *sql.DB, opts *FindCaseOptions, withTotal bool) (*CasePage, error) {
+	if opts == nil {
+		return nil, ErrNilOpts
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 8*time.Second)
+	defer cancel()
+
+	sortBy := opts.SortBy
+	if sortBy == "" {
+		sortBy = CaseSortLastAccord
+	}
+	sortExpr, ok := caseSortExprs[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSortField, sortBy)
+	}
+	order := SortDesc
+	if opts.SortOrder == SortAsc {
+		order = SortAsc
+	}
+
+	join, conditions, args, err := caseFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+	where := strings.Join(conditions, " AND ")
+
+	page := &CasePage{}
+	if withTotal {
+		page.Total, err = CountCases(ctx, appDb, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pageConds := ""
+	if opts.Cursor != "" {
+		if opts.Limit <= 0 {
+			return nil, ErrInvalidCursor
+		}
+		cur, err := decodeCaseCursor(opts.Cursor, sortBy, order)
+		if err != nil {
+			return nil, err
+		}
+
+		cmp := "<"
+		if order == SortAsc {
+			cmp = ">"
+		}
+		pageConds = fmt.Sprintf("WHERE (sort_value, id) %s (:cursorValue, :cursorId)", cmp)
+		args = append(args, sql.Named("cursorValue", cur.Value), sql.Named("cursorId", cur.Id))
+	}
+
+	pageLimit := ""
+	if opts.Limit > 0 {
+		// An extra case tells if there is a next page
+		pageLimit = "LIMIT :limit"
+		args = append(args, sql.Named("limit", opts.Limit+1))
+	}
+
+	accordCols, accordJoin, accordOrder := "", "", ""
+	if opts.IncludeAccords {
+		accordCols = ", accords.accord_id, accords.content, unixepoch(accords.date, 'unixepoch') as date"
+		accordJoin = "LEFT JOIN (SELECT id as accord_id, for_case, content, date, ROW_NUMBER() OVER (PARTITION BY for_case ORDER BY date DESC NULLS LAST) as rn FROM accords) accords ON cases.id = accords.for_case AND accords.rn <= :accordCount"
+		accordOrder = ", accords.date DESC NULLS LAST"
+		args = append(args, sql.Named("accordCount", opts.MaxAccords))
+	}
+
+	pageQuery := fmt.Sprintf(
+		`WITH filtered AS (
+			SELECT cases.id, %[1]s AS sort_value FROM cases %[2]s WHERE %[3]s
+		), page AS (
+			SELECT id, sort_value FROM filtered %[4]s ORDER BY sort_value %[5]s, id %[5]s %[6]s
+		)
+		SELECT cases.id, cases.case_id, cases.case_type, cases.region, cases.case_year, cases.case_no, cases.alias, cases.other_ids, cases.nature, cases.archived_at, cases.last_checked_at, page.sort_value %[7]s
+		FROM page
+		INNER JOIN cases ON cases.id = page.id
+		%[8]s
+		ORDER BY page.sort_value %[5]s, page.id %[5]s %[9]s`,
+		sortExpr,
+		join,
+		where,
+		pageConds,
+		order,
+		pageLimit,
+		accordCols,
+		accordJoin,
+		accordOrder,
+	)
+
+	rows, err := appDb.QueryContext(ctx, pageQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cases := []*LexCase{}
+	caseMap := map[string]int{}
+	sortValues := []any{}
+	for rows.Next() {
+		var (
+			id             = ""
+			caseId         = ""
+			caseType       = ""
+			region         = ""
+			caseYear       = sql.NullString{}
+			caseNo         = sql.NullString{}
+			alias          = sql.NullString{}
+			othIds         = sql.NullString{}
+			nature         = sql.NullString{}
+			archivedAt     = sql.NullInt64{}
+			lastCheckedAt  = sql.NullInt64{}
+			sortValue      any
+			accord         = Accord{}
+			accord_id      = sql.NullString{}
+			accord_content = sql.NullString{}
+			accDate        = sql.NullInt64{}
+		)
+
+		dest := []interface{}{
+			&id,
+			&caseId,
+			&caseType,
+			&region,
+			&caseYear,
+			&caseNo,
+			&alias,
+			&othIds,
+			&nature,
+			&archivedAt,
+			&lastCheckedAt,
+			&sortValue,
+		}
+		if opts.IncludeAccords {
+			dest = append(dest, &accord_id, &accord_content, &accDate)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		if accord_id.Valid {
+			accord.Id = accord_id.String
+			accord.ForCase = id
+
+			if accord_content.Valid {
+				accord.Content = accord_content.String
+			}
+			if accDate.Valid {
+				accord.Date = time.Unix(accDate.Int64, 0)
+				accord.DateStr = accord.Date.Local().Format(time.RFC3339)
+			}
+		}
+
+		if cIdx, ok := caseMap[id]; ok {
+			cases[cIdx].Accords = append(cases[cIdx].Accords, &accord)
+			continue
+		}
+
+		c := NewEmptyCase()
+		c.Id = id
+		c.CaseId = caseId
+		c.CaseType = caseType
+		c.Region = region
+		c.CaseYear = caseYear.String
+		c.CaseNo = caseNo.String
+		c.Alias = alias.String
+		c.Nature = nature.String
+		c.ArchivedAt = nullUnixToTime(archivedAt)
+		c.LastCheckedAt = nullUnixToTime(lastCheckedAt)
+		if accord.Id != "" {
+			c.Accords = []*Accord{&accord}
+		}
+		if othIds.Valid {
+			c.SetIdsFromStr(othIds.String)
+		}
+
+		// Curent length is next idx
+		caseMap[c.Id] = len(cases)
+		cases = append(cases, c)
+		sortValues = append(sortValues, sortValue)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.Limit > 0 && len(cases) > opts.Limit {
+		cases = cases[:opts.Limit]
+		last := len(cases) - 1
+		page.NextCursor = (&caseCursor{
+			SortBy:    sortBy,
+			SortOrder: order,
+			Value:     sortValues[last],
+			Id:        cases[last].Id,
+		}).encode()
+	}
+
+	if err := attachCaseTags(ctx, appDb, cases); err != nil {
+		return nil, err
+	}
+
+	page.Cases = cases
+	if !withTotal {
+		page.Total = len(cases)
+	}
+
+	return page, nil
+}
+
+// Builds the joins, conditions and named args that select the
+// cases matching the filters in opts
+func caseFilters(opts *FindCaseOptions) (join string, conditions []string, args []interface{}, err error) {
+	if strings.TrimSpace(opts.Search) != "" {
+		join = "INNER JOIN cases_fts ON cases.id = cases_fts.uuid"
+		conditions = append(conditions, "cases_fts MATCH :search")
+		args = append(args, sql.Named("search", prefixFtsTerms(opts.Search)))
+	}
+
+	filtersArchived := false
+	if strings.TrimSpace(opts.Query) != "" {
+		q, err := query.Parse(opts.Query)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		queryConds, queryArgs, queryFiltersArchived, err := compileCaseQuery(q, time.Now())
+		if err != nil {
+			return "", nil, nil, err
+		}
+		conditions = append(conditions, queryConds...)
+		args = append(args, queryArgs...)
+		filtersArchived = queryFiltersArchived
+	}
+
+	conditions = append(conditions, "cases.deleted_at IS NULL")
+	if !opts.IncludeArchived && !filtersArchived {
+		conditions = append(conditions, "cases.archived_at IS NULL")
+	}
+
+	if opts.CaseId != "" {
+		conditions = append(conditions, "cases.case_id LIKE '%'||:caseId||'%'")
+		args = append(args, sql.Named("caseId", opts.CaseId))
+	}
+	if opts.CaseType != "" {
+		conditions = append(conditions, "cases.case_type LIKE '%'||:caseType||'%'")
+		args = append(args, sql.Named("caseType", opts.CaseType))
+	}
+	if opts.CaseYear != "" {
+		conditions = append(conditions, "cases.case_year LIKE '%'||:caseYear||'%'")
+		args = append(args, sql.Named("caseYear", opts.CaseYear))
+	}
+	if opts.CaseNo != "" {
+		conditions = append(conditions, "cases.case_no LIKE '%'||:caseNo||'%'")
+		args = append(args, sql.Named("caseNo", opts.CaseNo))
+	}
+	if len(opts.Tags) > 0 {
+		cond, tagArgs := caseTagsCondition(opts.Tags)
+		conditions = append(conditions, cond)
+		args = append(args, tagArgs...)
+	}
+	if opts.ClientId != "" {
+		conditions = append(conditions, "cases.id IN (SELECT case_id FROM case_clients WHERE client_id = :clientId)")
+		args = append(args, sql.Named("clientId", opts.ClientId))
+	}
+	if opts.LastUpdatedAt != "" {
+		conditions = append(conditions, "(SELECT max(julianday(accords.date, 'unixepoch')) FROM accords WHERE accords.for_case = cases.id) >= julianday(:lastUpdated)")
+		args = append(args, sql.Named("lastUpdated", opts.LastUpdatedAt))
+	}
+
+	return join, conditions, args, nil
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal/query"
+)
+
+// Maps comparable query fields to the SQL expression they compare against
+var caseQueryColumns = map[query.Field]string{
+	query.FieldType:   "cases.case_type",
+	query.FieldYear:   "CAST(cases.case_year AS INTEGER)",
+	query.FieldNo:     "CAST(cases.case_no AS INTEGER)",
+	query.FieldId:     "cases.case_id",
+	query.FieldAlias:  "cases.alias",
+	query.FieldNature: "cases.nature",
+	// Cases without accords are considered as never updated
+	query.FieldUpdated: "coalesce((SELECT max(unixepoch(accords.date, 'unixepoch')) FROM accords WHERE accords.for_case = cases.id), 0)",
+}
+
+// Compiles a parsed query into SQL conditions over the `cases` table.
+//
+// Every value is passed as a named arg. The returned filtersArchived
+// reports whether the query already decides on archived cases
+func compileCaseQuery(q *query.Query, now time.Time) (conditions []string, args []interface{}, filtersArchived bool, err error) {
+	ftsTerms := []string{}
+
+	for i, c := range q.Clauses {
+		argName := fmt.Sprintf("q%d", i)
+		cond := ""
+
+		switch c.Field {
+		case query.FieldText:
+			term := ftsTerm(c.Value, !c.Phrase)
+			if !c.Negated {
+				ftsTerms = append(ftsTerms, term)
+				continue
+			}
+			cond = fmt.Sprintf("cases.id NOT IN (SELECT uuid FROM cases_fts WHERE cases_fts MATCH :%s)", argName)
+			args = append(args, sql.Named(argName, term))
+		case query.FieldType, query.FieldId:
+			cond = fmt.Sprintf("%s = :%s", caseQueryColumns[c.Field], argName)
+			args = append(args, sql.Named(argName, c.Value))
+		case query.FieldAlias, query.FieldNature:
+			cond = fmt.Sprintf("%s LIKE '%%'||:%s||'%%'", caseQueryColumns[c.Field], argName)
+			args = append(args, sql.Named(argName, c.Value))
+		case query.FieldYear, query.FieldNo:
+			cond = fmt.Sprintf("%s %s :%s", caseQueryColumns[c.Field], c.Op, argName)
+			args = append(args, sql.Named(argName, c.Value))
+		case query.FieldUpdated:
+			date, dateErr := query.ResolveDate(c.Value, now)
+			if dateErr != nil {
+				return nil, nil, false, dateErr
+			}
+			cond, args = compileDateClause(c, date, argName, args)
+		case query.FieldTag:
+			cond = fmt.Sprintf(
+				"cases.id IN (SELECT case_tags.case_id FROM case_tags INNER JOIN tags ON tags.id = case_tags.tag_id WHERE tags.name = :%s)",
+				argName,
+			)
+			args = append(args, sql.Named(argName, c.Value))
+		case query.FieldClient:
+			cond = fmt.Sprintf(
+				"cases.id IN (SELECT case_id FROM case_party_names WHERE name LIKE '%%'||:%s||'%%')",
+				argName,
+			)
+			args = append(args, sql.Named(argName, c.Value))
+		case query.FieldIs:
+			filtersArchived = true
+			if c.Value == query.IsArchived {
+				cond = "cases.archived_at IS NOT NULL"
+			} else {
+				cond = "cases.archived_at IS NULL"
+			}
+		default:
+			return nil, nil, false, fmt.Errorf("query: field %q can't be compiled", c.Field)
+		}
+
+		if c.Negated && c.Field != query.FieldText {
+			cond = fmt.Sprintf("NOT (%s)", cond)
+		}
+		conditions = append(conditions, cond)
+	}
+
+	if len(ftsTerms) > 0 {
+		conditions = append(conditions, "cases.id IN (SELECT uuid FROM cases_fts WHERE cases_fts MATCH :qText)")
+		args = append(args, sql.Named("qText", strings.Join(ftsTerms, " ")))
+	}
+
+	return conditions, args, filtersArchived, nil
+}
+
+// Dates match whole days, so `updated:2024-12-01` matches any
+// accord on that day and `updated:>2024-12-01` starts on the next one
+func compileDateClause(c *query.Clause, date time.Time, argName string, args []interface{}) (string, []interface{}) {
+	col := caseQueryColumns[c.Field]
+	start := date.Unix()
+	end := date.AddDate(0, 0, 1).Unix()
+
+	switch c.Op {
+	case query.OpGt:
+		return fmt.Sprintf("%s >= :%s", col, argName), append(args, sql.Named(argName, end))
+	case query.OpGte:
+		return fmt.Sprintf("%s >= :%s", col, argName), append(args, sql.Named(argName, start))
+	case query.OpLt:
+		return fmt.Sprintf("%s < :%s", col, argName), append(args, sql.Named(argName, start))
+	case query.OpLte:
+		return fmt.Sprintf("%s < :%s", col, argName), append(args, sql.Named(argName, end))
+	}
+
+	return fmt.Sprintf("%s >= :%[2]sStart AND %[1]s < :%[2]sEnd", col, argName),
+		append(args, sql.Named(argName+"Start", start), sql.Named(argName+"End", end))
+}
+
+// Quotes a single term for FTS5 so operators and special characters
+// within it are matched literally
+func ftsTerm(term string, prefix bool) string {
+	quoted := `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	if prefix {
+		quoted += "*"
+	}
+
+	return quoted
+}
@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vladwithcode/lex_app/internal"
 	"github.com/vladwithcode/lex_app/internal/readers"
+	"github.com/vladwithcode/lex_app/internal/regions"
 )
 
 const (
@@ -22,38 +24,65 @@ const (
 var (
 	ErrorInvalidCaseId = errors.New("caseId invalid format. Should be formatted as '123/2024[-I]'")
 	ErrNilOpts         = errors.New("FindFilteredCases: opts is nil")
+	ErrCaseNotFound    = errors.New("case not found")
+	ErrCaseNotInTrash  = errors.New("case must be deleted before it can be purged")
+	ErrCaseInTrash     = errors.New("case is in the trash, restore it instead")
+	ErrAmbiguousCase   = errors.New("more than one region tracks a case with the key, pass its region")
+	ErrInvalidCaseType = errors.New("caseType invalid. The region doesn't publish accords for it")
 )
 
 type LexCase struct {
-	Id             string    `json:"id" db:"id"`
-	CaseId         string    `json:"caseId" db:"case_id"`
-	CaseType       string    `json:"caseType" db:"case_type"`
-	CaseYear       string    `json:"caseYear" db:"case_year"`
-	CaseNo         string    `json:"caseNo" db:"case_no"`
-	Nature         string    `json:"nature" db:"nature"`
-	LastUpdatedAt  time.Time `json:"lastUpdatedAt" db:"last_updated_at"`
-	LastAccessedAt time.Time `json:"lastAccessedAt" db:"last_accessed_at"`
-	Alias          string    `json:"alias" db:"alias"`
-	OtherIds       []string  `json:"otherIds" db:"other_ids"`
-	Accords        []*Accord `json:"accords"`
+	Id             string           `json:"id" db:"id"`
+	CaseId         string           `json:"caseId" db:"case_id"`
+	CaseType       string           `json:"caseType" db:"case_type"`
+	Region         string           `json:"region" db:"region"`
+	CaseYear       string           `json:"caseYear" db:"case_year"`
+	CaseNo         string           `json:"caseNo" db:"case_no"`
+	Nature         string           `json:"nature" db:"nature"`
+	LastUpdatedAt  time.Time        `json:"lastUpdatedAt" db:"last_updated_at"`
+	LastAccessedAt time.Time        `json:"lastAccessedAt" db:"last_accessed_at"`
+	Alias          string           `json:"alias" db:"alias"`
+	OtherIds       []string         `json:"otherIds" db:"other_ids"`
+	ArchivedAt     *time.Time       `json:"archivedAt" db:"archived_at"`
+	DeletedAt      *time.Time       `json:"deletedAt" db:"deleted_at"`
+	LastCheckedAt  *time.Time       `json:"lastCheckedAt" db:"last_checked_at"`
+	Tags           []*Tag           `json:"tags"`
+	Accords        []*Accord        `json:"accords"`
+	Timeline       []*TimelineEntry `json:"timeline,omitempty"`
 }
 
 func NewEmptyCase() *LexCase {
 	return &LexCase{
 		OtherIds: []string{},
+		Tags:     []*Tag{},
 		Accords:  []*Accord{},
 	}
 }
 
+// Creates a case of the default region
 func NewCase(caseId, caseType string) (*LexCase, error) {
-	if !isValidCaseId(caseId) {
+	return NewRegionCase(caseId, caseType, string(internal.RegionDefault))
+}
+
+// Creates a case of the region. The case type must be one
+// of the registered for the region
+func NewRegionCase(caseId, caseType, region string) (*LexCase, error) {
+	if !IsValidCaseId(caseId) {
 		return nil, fmt.Errorf("%s is not a valid caseId value:\n\t%w", caseId, ErrorInvalidCaseId)
 	}
+	if region == "" {
+		region = string(internal.RegionDefault)
+	}
+	if err := ValidateCaseType(region, caseType); err != nil {
+		return nil, err
+	}
 
 	c := &LexCase{
 		CaseId:   caseId,
 		CaseType: caseType,
+		Region:   region,
 		OtherIds: []string{},
+		Tags:     []*Tag{},
 		Accords:  []*Accord{},
 	}
 
@@ -87,7 +116,7 @@ func (c *LexCase) SetIdsFromStr(str string) error {
 
 	for _, candidate := range ids {
 		candidate = strings.TrimSpace(candidate)
-		if !isValidCaseId(candidate) {
+		if !IsValidCaseId(candidate) {
 			return fmt.Errorf("String %s is not a valid id", candidate)
 		}
 
@@ -98,7 +127,7 @@ func (c *LexCase) SetIdsFromStr(str string) error {
 }
 
 func (c *LexCase) AddOtherId(candidate string) error {
-	if !isValidCaseId(candidate) {
+	if !IsValidCaseId(candidate) {
 		return fmt.Errorf("%s is not a valid caseId value:\n\t%w", candidate, ErrorInvalidCaseId)
 	}
 
@@ -111,7 +140,33 @@ func (c *LexCase) GetCaseKey() string {
 	return fmt.Sprintf("%s%s%s", c.CaseId, readers.CaseKeySeparator, c.CaseType)
 }
 
-func isValidCaseId(candidate string) bool {
+// Checks that the case type is one of the registered for the region
+func ValidateCaseType(region, caseType string) error {
+	r, err := regions.Find(internal.Region(region))
+	if err != nil {
+		return err
+	}
+
+	if !r.HasCaseType(internal.CaseType(caseType)) {
+		valid := make([]string, len(r.CaseTypes))
+		for i, ct := range r.CaseTypes {
+			valid[i] = string(ct)
+		}
+
+		return fmt.Errorf(
+			"%q is not a case type of %s, should be one of %s:\n\t%w",
+			caseType,
+			r.Name,
+			strings.Join(valid, ", "),
+			ErrInvalidCaseType,
+		)
+	}
+
+	return nil
+}
+
+// Reports whether candidate is a case number of the form number/year
+func IsValidCaseId(candidate string) bool {
 	parts := strings.Split(candidate, casePartsSeparator)
 
 	if parts[0] == "" {
@@ -140,6 +195,20 @@ type FindCaseOptions struct {
 	IncludeAccords bool
 	MaxAccords     int
 	Search         string
+	// Structured query, see the query package for its syntax
+	Query string
+	// Names of the tags a case must have all of
+	Tags []string
+	// Only cases linked to this client
+	ClientId string
+	// Archived cases are left out of listings unless requested
+	IncludeArchived bool
+	// Defaults to CaseSortLastAccord
+	SortBy CaseSortField
+	// Defaults to descending order
+	SortOrder SortOrder
+	// Returned as NextCursor by the previous page. Requires a Limit
+	Cursor string
 }
 
 var DefaultFindCaseOptions = FindCaseOptions{
@@ -152,20 +221,32 @@ var DefaultFindCaseOptions = FindCaseOptions{
 	IncludeAccords: false,
 	MaxAccords:     1,
 	Search:         "",
+	Query:          "",
+	Tags:           []string{},
+	ClientId:       "",
+
+	IncludeArchived: false,
+	SortBy:          CaseSortLastAccord,
+	SortOrder:       SortDesc,
+	Cursor:          "",
 }
 
-func InsertCase(ctx context.Context, appDb *sql.DB, caseData *LexCase) error {
+func InsertCase(ctx context.Context, appDb DBTX, caseData *LexCase) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	otherIds := strings.Join(caseData.OtherIds, otherIdsSeparator)
+	if caseData.Region == "" {
+		caseData.Region = string(internal.RegionDefault)
+	}
 	_, err := appDb.ExecContext(
 		ctx,
-		`INSERT INTO cases (id, case_id, case_type, case_year, case_no, alias, other_ids, nature)
-		VALUES (:Id, :CaseId, :CaseType, :CaseYear, :CaseNo, :Alias, :OtherIds, :Nature)`,
+		`INSERT INTO cases (id, case_id, case_type, region, case_year, case_no, alias, other_ids, nature, created_at)
+		VALUES (:Id, :CaseId, :CaseType, :Region, :CaseYear, :CaseNo, :Alias, :OtherIds, :Nature, unixepoch())`,
 		sql.Named("Id", caseData.Id),
 		sql.Named("CaseId", caseData.CaseId),
 		sql.Named("CaseType", caseData.CaseType),
+		sql.Named("Region", caseData.Region),
 		sql.Named("CaseYear", caseData.CaseYear),
 		sql.Named("CaseNo", caseData.CaseNo),
 		sql.Named("Alias", caseData.Alias),
@@ -174,6 +255,10 @@ func InsertCase(ctx context.Context, appDb *sql.DB, caseData *LexCase) error {
 	)
 
 	if err != nil {
+		// A case in the trash still holds its number
+		if trashedId, findErr := FindTrashedCaseId(ctx, appDb, caseData.CaseId, caseData.CaseType, caseData.Region); findErr == nil {
+			return fmt.Errorf("%s (%s):\n\t%w", caseData.GetCaseKey(), trashedId, ErrCaseInTrash)
+		}
 		return err
 	}
 
@@ -185,7 +270,7 @@ func FindAllCases(ctx context.Context, appDb *sql.DB) ([]*LexCase, error) {
 	defer cancel()
 	rows, err := appDb.QueryContext(
 		ctx,
-		"SELECT id, case_id, case_type, case_year, case_no, alias, other_ids, nature FROM cases",
+		"SELECT id, case_id, case_type, region, case_year, case_no, alias, other_ids, nature FROM cases WHERE archived_at IS NULL AND deleted_at IS NULL",
 	)
 	if err != nil {
 		return nil, err
@@ -209,6 +294,7 @@ func FindAllCases(ctx context.Context, appDb *sql.DB) ([]*LexCase, error) {
 			&c.Id,
 			&c.CaseId,
 			&c.CaseType,
+			&c.Region,
 			&nCaseYear,
 			&nCaseNo,
 			&nAlias,
@@ -240,149 +326,20 @@ func FindAllCases(ctx context.Context, appDb *sql.DB) ([]*LexCase, error) {
 		return nil, err
 	}
 
+	if err := attachCaseTags(ctx, appDb, cases); err != nil {
+		return nil, err
+	}
+
 	return cases, nil
 }
 
 func FindFilteredCases(ctx context.Context, appDb *sql.DB, opts *FindCaseOptions) ([]*LexCase, error) {
-	ctx, cancel := context.WithTimeout(ctx, 8*time.Second)
-	defer cancel()
-	baseQuery := "SELECT cases.id, cases.case_id, cases.case_type, cases.case_year, cases.case_no, cases.alias, cases.other_ids, cases.nature"
-	args := []interface{}{}
-	conditions := []string{}
-	if opts == nil {
-		return nil, ErrNilOpts
-	}
-
-	if opts.IncludeAccords {
-		baseQuery = fmt.Sprintf("%s, %s", baseQuery, "accords.accord_id, accords.content, unixepoch(accords.date, 'unixepoch') as date FROM cases LEFT JOIN (SELECT id as accord_id, for_case, content, date, ROW_NUMBER() OVER (PARTITION BY for_case ORDER BY date DESC NULLS LAST) as rn FROM accords) accords ON cases.id = accords.for_case AND accords.rn <= :accordCount")
-		args = append(args, sql.Named("accordCount", opts.MaxAccords))
-	} else {
-		baseQuery = fmt.Sprintf("%s FROM cases", baseQuery)
-	}
-
-	if opts.Search != "" {
-		baseQuery = fmt.Sprintf("%s INNER JOIN cases_fts ON cases.id = cases_fts.uuid WHERE cases_fts MATCH :search||'*'", baseQuery)
-		s := opts.Search
-		if strings.Contains(s, "/") {
-			s = fmt.Sprintf(`"%s\"`, s)
-		}
-		args = append(args, sql.Named("search", s))
-	}
-
-	if opts.CaseId != "" {
-		conditions = append(conditions, "case_id LIKE '%'||:caseId||'%'")
-		args = append(args, sql.Named("caseId", opts.CaseId))
-	}
-	if opts.CaseType != "" {
-		conditions = append(conditions, "case_type LIKE '%'||:caseType||'%'")
-		args = append(args, sql.Named("caseType", opts.CaseType))
-	}
-	if opts.CaseYear != "" {
-		conditions = append(conditions, "case_year LIKE '%'||:caseYear||'%'")
-		args = append(args, sql.Named("caseYear", opts.CaseYear))
-	}
-	if opts.CaseNo != "" {
-		conditions = append(conditions, "case_no LIKE '%'||:caseNo||'%'")
-		args = append(args, sql.Named("caseNo", opts.CaseNo))
-	}
-	if opts.LastUpdatedAt != "" {
-		conditions = append(conditions, "julianday(accords.date) >= julianday(:lastUpdated)")
-		args = append(args, sql.Named("lastUpdated", opts.LastUpdatedAt))
-	}
-
-	if len(conditions) > 0 {
-		baseQuery = fmt.Sprintf("%s WHERE %s", baseQuery, strings.Join(conditions, " AND "))
-	}
-
-	if opts.IncludeAccords {
-		baseQuery = fmt.Sprintf("%s ORDER BY accords.date DESC NULLS LAST", baseQuery)
-	}
-	if opts.Limit > 0 {
-		baseQuery = fmt.Sprintf("%s LIMIT :limit", baseQuery)
-		args = append(args, sql.Named("limit", opts.Limit))
-	}
-
-	rows, err := appDb.QueryContext(
-		ctx,
-		baseQuery,
-		args...,
-	)
+	page, err := findCasesPage(ctx, appDb, opts, false)
 	if err != nil {
 		return nil, err
 	}
 
-	cases := []*LexCase{}
-	caseMap := map[string]int{}
-	for rows.Next() {
-		var (
-			id             = ""
-			caseId         = ""
-			caseType       = ""
-			caseYear       = sql.NullString{}
-			caseNo         = sql.NullString{}
-			alias          = sql.NullString{}
-			othIds         = sql.NullString{}
-			nature         = sql.NullString{}
-			accord         = Accord{}
-			accord_id      = sql.NullString{}
-			accord_content = sql.NullString{}
-			accDate        = sql.NullInt64{}
-		)
-
-		rows.Scan(
-			&id,
-			&caseId,
-			&caseType,
-			&caseYear,
-			&caseNo,
-			&alias,
-			&othIds,
-			&nature,
-			&accord_id,
-			&accord_content,
-			&accDate,
-		)
-
-		if accord_id.Valid {
-			accord.Id = accord_id.String
-
-			if accord_content.Valid {
-				accord.Content = accord_content.String
-			}
-			if accDate.Valid {
-				accord.Date = time.Unix(accDate.Int64, 0)
-				accord.DateStr = accord.Date.Local().Format(time.RFC3339)
-			}
-		}
-
-		if cIdx, ok := caseMap[id]; ok {
-			cases[cIdx].Accords = append(cases[cIdx].Accords, &accord)
-		} else {
-			c := NewEmptyCase()
-			c.Id = id
-			c.CaseId = caseId
-			c.CaseType = caseType
-			c.CaseYear = caseYear.String
-			c.CaseNo = caseNo.String
-			c.Alias = alias.String
-			if accord.Id != "" {
-				c.Accords = []*Accord{&accord}
-			}
-			if othIds.Valid {
-				c.SetIdsFromStr(othIds.String)
-			}
-
-			// Curent length is next idx
-			caseMap[c.Id] = len(cases)
-			cases = append(cases, c)
-		}
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return cases, nil
+	return page.Cases, nil
 }
 
 func FindCasesById(ctx context.Context, appDb *sql.DB, ids []string) ([]*LexCase, error) {
@@ -421,26 +378,33 @@ func FindCasesById(ctx context.Context, appDb *sql.DB, ids []string) ([]*LexCase
 func FindCaseById(ctx context.Context, appDb *sql.DB, id string) (*LexCase, error) {
 	row := appDb.QueryRowContext(
 		ctx,
-		`SELECT id, case_id, case_type, case_year, case_no, alias, other_ids, nature FROM cases WHERE id = :Id`,
+		`SELECT id, case_id, case_type, region, case_year, case_no, alias, other_ids, nature, archived_at, deleted_at FROM cases WHERE id = :Id`,
 		sql.Named("Id", id),
 	)
 
 	c := &LexCase{}
 	otherIds := new(string)
 	nNature := sql.NullString{}
+	nArchivedAt := sql.NullInt64{}
+	nDeletedAt := sql.NullInt64{}
 	err := row.Scan(
 		&c.Id,
 		&c.CaseId,
 		&c.CaseType,
+		&c.Region,
 		&c.CaseYear,
 		&c.CaseNo,
 		&c.Alias,
 		&otherIds,
 		&nNature,
+		&nArchivedAt,
+		&nDeletedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	c.ArchivedAt = nullUnixToTime(nArchivedAt)
+	c.DeletedAt = nullUnixToTime(nDeletedAt)
 
 	if len(*otherIds) > 0 {
 		c.SetIdsFromStr(*otherIds)
@@ -449,33 +413,48 @@ func FindCaseById(ctx context.Context, appDb *sql.DB, id string) (*LexCase, erro
 		c.Nature = nNature.String
 	}
 
+	if err := attachCaseTags(ctx, appDb, []*LexCase{c}); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
+// Returns the cases outside the trash with the given keys. Keys
+// without a case are left out of the result
 func FindCases(ctx context.Context, appDb *sql.DB, caseKeys []string) ([]*LexCase, error) {
+	cases := []*LexCase{}
+	if len(caseKeys) == 0 {
+		return cases, nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	inList, args := namedInArgs("caseKey", caseKeys)
 	rows, err := appDb.QueryContext(
 		ctx,
-		"SELECT id, case_id, case_type, (case_id || ':' || case_type) as case_key, nature FROM cases WHERE case_key IN :CaseKeys",
-		sql.Named("CaseKeys", caseKeys),
+		fmt.Sprintf(
+			`SELECT id, case_id, case_type, region, nature FROM cases
+			WHERE (case_id || '%[1]s' || case_type) IN (%[2]s) AND deleted_at IS NULL`,
+			readers.CaseKeySeparator,
+			inList,
+		),
+		args...,
 	)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	cases := []*LexCase{}
 	for rows.Next() {
 		c := NewEmptyCase()
 		nNature := sql.NullString{}
-		rows.Scan(
-			&c.Id,
-			&c.CaseId,
-			&c.CaseType,
-			&nNature,
-		)
+		if err := rows.Scan(&c.Id, &c.CaseId, &c.CaseType, &c.Region, &nNature); err != nil {
+			return nil, err
+		}
 
+		c.Nature = nNature.String
 		cases = append(cases, c)
 	}
 
@@ -486,13 +465,57 @@ func FindCases(ctx context.Context, appDb *sql.DB, caseKeys []string) ([]*LexCas
 	return cases, nil
 }
 
-func FindCase(ctx context.Context, appDb *sql.DB, caseKey string) (*LexCase, error) {
+// Finds the case with the key in any region.
+//
+// Returns ErrAmbiguousCase when cases of more than one region have
+// the key, use FindRegionCase for those
+func FindCase(ctx context.Context, appDb DBTX, caseKey string) (*LexCase, error) {
+	rows, err := appDb.QueryContext(
+		ctx,
+		"SELECT id, case_id, case_type, region, case_year, case_no, alias, other_ids, nature FROM cases WHERE (case_id || ':' || case_type) = :CaseKey LIMIT 2",
+		sql.Named("CaseKey", caseKey),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cases := []*LexCase{}
+	for rows.Next() {
+		c, err := scanCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	switch len(cases) {
+	case 0:
+		return nil, sql.ErrNoRows
+	case 1:
+		return cases[0], nil
+	}
+
+	return nil, fmt.Errorf("%s:\n\t%w", caseKey, ErrAmbiguousCase)
+}
+
+// Same as FindCase, for the case with the key in the region
+func FindRegionCase(ctx context.Context, appDb DBTX, region, caseKey string) (*LexCase, error) {
 	row := appDb.QueryRowContext(
 		ctx,
-		"SELECT id, case_id, case_type, case_year, case_no, alias, other_ids, nature FROM cases WHERE (case_id || ':' || case_type) = :CaseKey",
+		`SELECT id, case_id, case_type, region, case_year, case_no, alias, other_ids, nature FROM cases
+		WHERE region = :Region AND (case_id || ':' || case_type) = :CaseKey`,
+		sql.Named("Region", region),
 		sql.Named("CaseKey", caseKey),
 	)
 
+	return scanCase(row)
+}
+
+func scanCase(row rowScanner) (*LexCase, error) {
 	c := NewEmptyCase()
 	otherIds := new(string)
 	nNature := sql.NullString{}
@@ -500,6 +523,7 @@ func FindCase(ctx context.Context, appDb *sql.DB, caseKey string) (*LexCase, err
 		&c.Id,
 		&c.CaseId,
 		&c.CaseType,
+		&c.Region,
 		&c.CaseYear,
 		&c.CaseNo,
 		&c.Alias,
@@ -526,11 +550,14 @@ func FindCaseWithAccords(ctx context.Context, appDb *sql.DB, id string, accordCo
 			cases.id,
 			cases.case_id,
 			cases.case_type,
+			cases.region,
 			cases.case_year,
 			cases.case_no,
 			cases.alias,
 			cases.other_ids,
 			cases.nature,
+			cases.archived_at,
+			cases.deleted_at,
 			accords.id,
 			accords.content,
 			unixepoch(accords.date, 'unixepoch') as date,
@@ -550,6 +577,8 @@ func FindCaseWithAccords(ctx context.Context, appDb *sql.DB, id string, accordCo
 
 	nOthIds := sql.NullString{}
 	nNature := sql.NullString{}
+	nArchivedAt := sql.NullInt64{}
+	nDeletedAt := sql.NullInt64{}
 	for rows.Next() {
 		var (
 			acId      sql.NullString
@@ -561,11 +590,14 @@ func FindCaseWithAccords(ctx context.Context, appDb *sql.DB, id string, accordCo
 			&c.Id,
 			&c.CaseId,
 			&c.CaseType,
+			&c.Region,
 			&c.CaseYear,
 			&c.CaseNo,
 			&c.Alias,
 			&nOthIds,
 			&nNature,
+			&nArchivedAt,
+			&nDeletedAt,
 			&acId,
 			&acContent,
 			&acDate,
@@ -592,20 +624,26 @@ func FindCaseWithAccords(ctx context.Context, appDb *sql.DB, id string, accordCo
 	if nNature.Valid {
 		c.Nature = nNature.String
 	}
+	c.ArchivedAt = nullUnixToTime(nArchivedAt)
+	c.DeletedAt = nullUnixToTime(nDeletedAt)
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
+	if err := attachCaseTags(ctx, appDb, []*LexCase{c}); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
-func UpdateCaseById(ctx context.Context, appDb *sql.DB, id string, newCaseData *LexCase) error {
+func UpdateCaseById(ctx context.Context, appDb DBTX, id string, newCaseData *LexCase) error {
 	cols := make([]string, 0)
 	args := make([]interface{}, 0)
 
 	if newCaseData.CaseId != "" {
-		if !isValidCaseId(newCaseData.CaseId) {
+		if !IsValidCaseId(newCaseData.CaseId) {
 			return fmt.Errorf("can't insert/update case with invalid id: %s\n  %w", newCaseData.CaseId, ErrorInvalidCaseId)
 		}
 
@@ -613,11 +651,22 @@ func UpdateCaseById(ctx context.Context, appDb *sql.DB, id string, newCaseData *
 		args = append(args, sql.Named("CaseId", newCaseData.CaseId))
 	}
 
+	if newCaseData.CaseType != "" || newCaseData.Region != "" {
+		if err := validateCaseTypeUpdate(ctx, appDb, id, newCaseData); err != nil {
+			return err
+		}
+	}
+
 	if newCaseData.CaseType != "" {
 		cols = append(cols, "case_type = :CaseType")
 		args = append(args, sql.Named("CaseType", newCaseData.CaseType))
 	}
 
+	if newCaseData.Region != "" {
+		cols = append(cols, "region = :Region")
+		args = append(args, sql.Named("Region", newCaseData.Region))
+	}
+
 	if newCaseData.Alias != "" {
 		cols = append(cols, "alias = :Alias")
 		args = append(args, sql.Named("Alias", newCaseData.Alias))
@@ -644,11 +693,368 @@ func UpdateCaseById(ctx context.Context, appDb *sql.DB, id string, newCaseData *
 	return nil
 }
 
+// Validates the case type and region the case will have after the
+// update, taking the stored value for the one not being changed
+func validateCaseTypeUpdate(ctx context.Context, appDb DBTX, id string, newCaseData *LexCase) error {
+	region, caseType := newCaseData.Region, newCaseData.CaseType
+	if region == "" || caseType == "" {
+		var curRegion, curCaseType string
+		err := appDb.QueryRowContext(
+			ctx,
+			"SELECT region, case_type FROM cases WHERE id = :Id",
+			sql.Named("Id", id),
+		).Scan(&curRegion, &curCaseType)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCaseNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if region == "" {
+			region = curRegion
+		}
+		if caseType == "" {
+			caseType = curCaseType
+		}
+	}
+
+	return ValidateCaseType(region, caseType)
+}
+
+// Sets the time the cases, identified by their case keys, were last
+// searched for updates
+func MarkCasesChecked(ctx context.Context, appDb *sql.DB, caseKeys []string, at time.Time) error {
+	if len(caseKeys) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	inList, args := namedInArgs("caseKey", caseKeys)
+	args = append(args, sql.Named("At", at.Unix()))
+	_, err := appDb.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			"UPDATE cases SET last_checked_at = :At WHERE (case_id || '%s' || case_type) IN (%s)",
+			readers.CaseKeySeparator,
+			inList,
+		),
+		args...,
+	)
+
+	return err
+}
+
+// Returns the regions of the cases with each case key, as the same
+// number and type may be tracked in several regions.
+// Keys without a matching case are left out of the map
+func FindCaseRegions(ctx context.Context, appDb DBTX, caseKeys []string) (map[string][]internal.Region, error) {
+	regions := map[string][]internal.Region{}
+	if len(caseKeys) == 0 {
+		return regions, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	inList, args := namedInArgs("caseKey", caseKeys)
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT (case_id || '%[1]s' || case_type) AS case_key, region FROM cases
+			WHERE (case_id || '%[1]s' || case_type) IN (%[2]s)
+			ORDER BY region`,
+			readers.CaseKeySeparator,
+			inList,
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, region string
+		if err := rows.Scan(&key, &region); err != nil {
+			return nil, err
+		}
+		regions[key] = append(regions[key], internal.Region(region))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
+
+// Returns the id of each case of the region, indexed by its case key. Keys
+// without a matching case, or whose case is in the trash, are left out of the map
+func FindCaseIdsByKey(ctx context.Context, appDb DBTX, region string, caseKeys []string) (map[string]string, error) {
+	ids := map[string]string{}
+	if len(caseKeys) == 0 {
+		return ids, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	inList, args := namedInArgs("caseKey", caseKeys)
+	args = append(args, sql.Named("Region", region))
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT (case_id || '%[1]s' || case_type) AS case_key, id FROM cases
+			WHERE (case_id || '%[1]s' || case_type) IN (%[2]s) AND region = :Region AND deleted_at IS NULL`,
+			readers.CaseKeySeparator,
+			inList,
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, id string
+		if err := rows.Scan(&key, &id); err != nil {
+			return nil, err
+		}
+		ids[key] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// A case whose case type isn't registered for its region
+type InvalidTypeCase struct {
+	*LexCase
+	Problem string `json:"problem"`
+}
+
+// Returns the cases, not in the trash, whose case type or region
+// isn't registered. Their accords can't be searched for
+func FindCasesWithInvalidType(ctx context.Context, appDb *sql.DB) ([]*InvalidTypeCase, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		"SELECT id, case_id, case_type, region, alias, archived_at FROM cases WHERE deleted_at IS NULL ORDER BY region, case_type, case_id",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invalid := []*InvalidTypeCase{}
+	for rows.Next() {
+		c := NewEmptyCase()
+		nAlias := sql.NullString{}
+		nArchivedAt := sql.NullInt64{}
+		if err := rows.Scan(&c.Id, &c.CaseId, &c.CaseType, &c.Region, &nAlias, &nArchivedAt); err != nil {
+			return nil, err
+		}
+		c.Alias = nAlias.String
+		c.ArchivedAt = nullUnixToTime(nArchivedAt)
+
+		if err := ValidateCaseType(c.Region, c.CaseType); err != nil {
+			problem := "unknown case type"
+			if errors.Is(err, regions.ErrUnknownRegion) {
+				problem = "unknown region"
+			}
+			invalid = append(invalid, &InvalidTypeCase{c, problem})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invalid, nil
+}
+
+// Moves the case to the trash. Its accords are kept until
+// the case is purged with PurgeCaseById
 func DeleteCaseById(ctx context.Context, appDb *sql.DB, id string) error {
-	_, err := appDb.ExecContext(ctx, "DELETE FROM cases WHERE id = $1", id)
+	return execCaseUpdate(
+		ctx,
+		appDb,
+		"UPDATE cases SET deleted_at = unixepoch() WHERE id = :Id AND deleted_at IS NULL",
+		id,
+	)
+}
+
+// Takes the case out of the trash
+func RestoreCaseById(ctx context.Context, appDb *sql.DB, id string) error {
+	return execCaseUpdate(
+		ctx,
+		appDb,
+		"UPDATE cases SET deleted_at = NULL WHERE id = :Id AND deleted_at IS NOT NULL",
+		id,
+	)
+}
+
+func ArchiveCaseById(ctx context.Context, appDb *sql.DB, id string) error {
+	return execCaseUpdate(
+		ctx,
+		appDb,
+		"UPDATE cases SET archived_at = unixepoch() WHERE id = :Id AND archived_at IS NULL AND deleted_at IS NULL",
+		id,
+	)
+}
+
+func UnarchiveCaseById(ctx context.Context, appDb *sql.DB, id string) error {
+	return execCaseUpdate(
+		ctx,
+		appDb,
+		"UPDATE cases SET archived_at = NULL WHERE id = :Id AND archived_at IS NOT NULL",
+		id,
+	)
+}
+
+// Permanently removes a case along with its accord history.
+//
+// Only cases already in the trash can be purged
+func PurgeCaseById(ctx context.Context, appDb *sql.DB, id string) error {
+	res, err := appDb.ExecContext(
+		ctx,
+		"DELETE FROM cases WHERE id = :Id AND deleted_at IS NOT NULL",
+		sql.Named("Id", id),
+	)
 	if err != nil {
 		return err
 	}
 
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCaseNotInTrash
+	}
+
 	return nil
 }
+
+// Returns the id of the case in the trash with the number and type in
+// the region. Fails with ErrCaseNotFound if there's none
+func FindTrashedCaseId(ctx context.Context, appDb DBTX, caseId, caseType, region string) (string, error) {
+	var id string
+	err := appDb.QueryRowContext(
+		ctx,
+		`SELECT id FROM cases
+		WHERE region = :Region AND case_id = :CaseId AND case_type = :CaseType AND deleted_at IS NOT NULL`,
+		sql.Named("Region", region),
+		sql.Named("CaseId", caseId),
+		sql.Named("CaseType", caseType),
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrCaseNotFound
+	}
+
+	return id, err
+}
+
+// Lists the cases in the trash, most recently deleted first
+func FindDeletedCases(ctx context.Context, appDb *sql.DB) ([]*LexCase, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT id, case_id, case_type, region, case_year, case_no, alias, other_ids, nature, archived_at, deleted_at
+		FROM cases
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cases := []*LexCase{}
+	for rows.Next() {
+		var (
+			c           = NewEmptyCase()
+			nCaseYear   = sql.NullString{}
+			nCaseNo     = sql.NullString{}
+			nAlias      = sql.NullString{}
+			nOtherIds   = sql.NullString{}
+			nNature     = sql.NullString{}
+			nArchivedAt = sql.NullInt64{}
+			nDeletedAt  = sql.NullInt64{}
+		)
+
+		err := rows.Scan(
+			&c.Id,
+			&c.CaseId,
+			&c.CaseType,
+			&c.Region,
+			&nCaseYear,
+			&nCaseNo,
+			&nAlias,
+			&nOtherIds,
+			&nNature,
+			&nArchivedAt,
+			&nDeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		c.CaseYear = nCaseYear.String
+		c.CaseNo = nCaseNo.String
+		c.Alias = nAlias.String
+		c.Nature = nNature.String
+		c.ArchivedAt = nullUnixToTime(nArchivedAt)
+		c.DeletedAt = nullUnixToTime(nDeletedAt)
+		if nOtherIds.Valid {
+			c.SetIdsFromStr(nOtherIds.String)
+		}
+
+		cases = append(cases, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cases, nil
+}
+
+// Runs a state changing query over a single case and reports
+// ErrCaseNotFound if no row was affected by it
+func execCaseUpdate(ctx context.Context, appDb *sql.DB, query, id string) error {
+	res, err := appDb.ExecContext(ctx, query, sql.Named("Id", id))
+	if err != nil {
+		return err
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrCaseNotFound, id)
+	}
+
+	return nil
+}
+
+func nullUnixToTime(n sql.NullInt64) *time.Time {
+	if !n.Valid {
+		return nil
+	}
+
+	t := time.Unix(n.Int64, 0)
+	return &t
+}
+
+func timeToNullUnix(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.Unix(), Valid: true}
+}
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vladwithcode/lex_app/internal/regions"
+)
+
+func TestNewCaseValidatesCaseType(t *testing.T) {
+	c, err := NewCase("12/2024", "fam2")
+	if err != nil {
+		t.Fatalf("fam2 should be valid for the default region, got %v", err)
+	}
+	if c.Region != "MX_DGO_DGO" {
+		t.Errorf("expected the case to be in the default region, got %q", c.Region)
+	}
+
+	if _, err := NewCase("12/2024", "fam6"); !errors.Is(err, ErrInvalidCaseType) {
+		t.Errorf("expected fam6 to be rejected with ErrInvalidCaseType, got %v", err)
+	}
+	if _, err := NewRegionCase("12/2024", "fam2", "MX_XX_XX"); !errors.Is(err, regions.ErrUnknownRegion) {
+		t.Errorf("expected an unknown region to be rejected, got %v", err)
+	}
+}
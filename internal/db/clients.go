@@ -0,0 +1,488 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tables sharing the layout of a Client
+const (
+	clientsTable = "clients"
+	partiesTable = "parties"
+)
+
+var (
+	ErrInvalidContactName = errors.New("name can't be empty")
+	ErrInvalidRFC         = errors.New("RFC should have 12 (persona moral) or 13 (persona física) characters")
+	ErrInvalidCURP        = errors.New("CURP should have 18 characters")
+	ErrInvalidPartyRole   = errors.New("role should be one of 'actor', 'demandado' or 'tercero'")
+)
+
+var (
+	rfcRegex  = regexp.MustCompile(`^[A-ZÑ&]{3,4}[0-9]{6}[A-Z0-9]{3}$`)
+	curpRegex = regexp.MustCompile(`^[A-Z]{4}[0-9]{6}[HMX][A-Z]{5}[A-Z0-9][0-9]$`)
+)
+
+// The role a client or party has in a case
+type PartyRole string
+
+const (
+	PartyRoleActor     PartyRole = "actor"
+	PartyRoleDemandado PartyRole = "demandado"
+	PartyRoleTercero   PartyRole = "tercero"
+)
+
+func (r PartyRole) IsValid() bool {
+	switch r {
+	case PartyRoleActor, PartyRoleDemandado, PartyRoleTercero:
+		return true
+	}
+
+	return false
+}
+
+// Someone the firm represents
+type Client struct {
+	Id        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	RFC       string    `json:"rfc" db:"rfc"`
+	CURP      string    `json:"curp" db:"curp"`
+	Email     string    `json:"email" db:"email"`
+	Phone     string    `json:"phone" db:"phone"`
+	Address   string    `json:"address" db:"address"`
+	Notes     string    `json:"notes" db:"notes"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Any other person involved in a case, like the opposing party.
+//
+// Shares its layout with Client
+type Party Client
+
+// A client linked to a case with the role it has in it
+type CaseClient struct {
+	Client *Client   `json:"client"`
+	Role   PartyRole `json:"role"`
+}
+
+// A party linked to a case with the role it has in it
+type CaseParty struct {
+	Party *Party    `json:"party"`
+	Role  PartyRole `json:"role"`
+}
+
+// Everyone linked to a single case
+type CaseParticipants struct {
+	Clients []*CaseClient `json:"clients"`
+	Parties []*CaseParty  `json:"parties"`
+}
+
+func NewClient(name string) (*Client, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	c := &Client{
+		Id:        id.String(),
+		Name:      strings.TrimSpace(name),
+		CreatedAt: time.Now(),
+	}
+	if c.Name == "" {
+		return nil, ErrInvalidContactName
+	}
+
+	return c, nil
+}
+
+func NewParty(name string) (*Party, error) {
+	c, err := NewClient(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return (*Party)(c), nil
+}
+
+// Normalizes and validates the identity fields
+func (c *Client) validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.RFC = strings.ToUpper(strings.TrimSpace(c.RFC))
+	c.CURP = strings.ToUpper(strings.TrimSpace(c.CURP))
+
+	if c.Name == "" {
+		return ErrInvalidContactName
+	}
+	if c.RFC != "" && !rfcRegex.MatchString(c.RFC) {
+		return fmt.Errorf("%s is not a valid RFC:\n\t%w", c.RFC, ErrInvalidRFC)
+	}
+	if c.CURP != "" && !curpRegex.MatchString(c.CURP) {
+		return fmt.Errorf("%s is not a valid CURP:\n\t%w", c.CURP, ErrInvalidCURP)
+	}
+
+	return nil
+}
+
+func InsertClient(ctx context.Context, appDb *sql.DB, client *Client) error {
+	return insertContact(ctx, appDb, clientsTable, client)
+}
+
+func InsertParty(ctx context.Context, appDb *sql.DB, party *Party) error {
+	return insertContact(ctx, appDb, partiesTable, (*Client)(party))
+}
+
+func FindAllClients(ctx context.Context, appDb *sql.DB, search string) ([]*Client, error) {
+	return findContacts(ctx, appDb, clientsTable, search)
+}
+
+func FindAllParties(ctx context.Context, appDb *sql.DB, search string) ([]*Party, error) {
+	contacts, err := findContacts(ctx, appDb, partiesTable, search)
+	if err != nil {
+		return nil, err
+	}
+
+	parties := make([]*Party, len(contacts))
+	for i, c := range contacts {
+		parties[i] = (*Party)(c)
+	}
+
+	return parties, nil
+}
+
+func FindClientById(ctx context.Context, appDb *sql.DB, id string) (*Client, error) {
+	return findContactById(ctx, appDb, clientsTable, id)
+}
+
+func FindPartyById(ctx context.Context, appDb *sql.DB, id string) (*Party, error) {
+	c, err := findContactById(ctx, appDb, partiesTable, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return (*Party)(c), nil
+}
+
+func UpdateClientById(ctx context.Context, appDb *sql.DB, id string, clientData *Client) error {
+	return updateContactById(ctx, appDb, clientsTable, id, clientData)
+}
+
+func UpdatePartyById(ctx context.Context, appDb *sql.DB, id string, partyData *Party) error {
+	return updateContactById(ctx, appDb, partiesTable, id, (*Client)(partyData))
+}
+
+// Removes the client and its links to cases. The cases are kept
+func DeleteClientById(ctx context.Context, appDb *sql.DB, id string) error {
+	_, err := appDb.ExecContext(ctx, "DELETE FROM clients WHERE id = :Id", sql.Named("Id", id))
+
+	return err
+}
+
+// Removes the party and its links to cases. The cases are kept
+func DeletePartyById(ctx context.Context, appDb *sql.DB, id string) error {
+	_, err := appDb.ExecContext(ctx, "DELETE FROM parties WHERE id = :Id", sql.Named("Id", id))
+
+	return err
+}
+
+// Links the client to the case, replacing its role if already linked
+func LinkClientToCase(ctx context.Context, appDb *sql.DB, caseId, clientId string, role PartyRole) error {
+	if !role.IsValid() {
+		return fmt.Errorf("%q is not a valid role:\n\t%w", role, ErrInvalidPartyRole)
+	}
+
+	_, err := appDb.ExecContext(
+		ctx,
+		`INSERT INTO case_clients (case_id, client_id, role) VALUES (:CaseId, :ClientId, :Role)
+		ON CONFLICT (case_id, client_id) DO UPDATE SET role = excluded.role`,
+		sql.Named("CaseId", caseId),
+		sql.Named("ClientId", clientId),
+		sql.Named("Role", role),
+	)
+
+	return err
+}
+
+func UnlinkClientFromCase(ctx context.Context, appDb *sql.DB, caseId, clientId string) error {
+	_, err := appDb.ExecContext(
+		ctx,
+		"DELETE FROM case_clients WHERE case_id = :CaseId AND client_id = :ClientId",
+		sql.Named("CaseId", caseId),
+		sql.Named("ClientId", clientId),
+	)
+
+	return err
+}
+
+// Links the party to the case, replacing its role if already linked
+func LinkPartyToCase(ctx context.Context, appDb *sql.DB, caseId, partyId string, role PartyRole) error {
+	if !role.IsValid() {
+		return fmt.Errorf("%q is not a valid role:\n\t%w", role, ErrInvalidPartyRole)
+	}
+
+	_, err := appDb.ExecContext(
+		ctx,
+		`INSERT INTO case_parties (case_id, party_id, role) VALUES (:CaseId, :PartyId, :Role)
+		ON CONFLICT (case_id, party_id) DO UPDATE SET role = excluded.role`,
+		sql.Named("CaseId", caseId),
+		sql.Named("PartyId", partyId),
+		sql.Named("Role", role),
+	)
+
+	return err
+}
+
+func UnlinkPartyFromCase(ctx context.Context, appDb *sql.DB, caseId, partyId string) error {
+	_, err := appDb.ExecContext(
+		ctx,
+		"DELETE FROM case_parties WHERE case_id = :CaseId AND party_id = :PartyId",
+		sql.Named("CaseId", caseId),
+		sql.Named("PartyId", partyId),
+	)
+
+	return err
+}
+
+// Returns the clients and parties linked to a case
+func FindCaseParticipants(ctx context.Context, appDb *sql.DB, caseId string) (*CaseParticipants, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	participants := &CaseParticipants{
+		Clients: []*CaseClient{},
+		Parties: []*CaseParty{},
+	}
+	err := queryLinkedContacts(ctx, appDb, clientsTable, caseId, func(c *Client, role PartyRole) {
+		participants.Clients = append(participants.Clients, &CaseClient{Client: c, Role: role})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = queryLinkedContacts(ctx, appDb, partiesTable, caseId, func(c *Client, role PartyRole) {
+		participants.Parties = append(participants.Parties, &CaseParty{Party: (*Party)(c), Role: role})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
+// Returns the latest accords across every case of the client
+func FindRecentAccordsForClient(ctx context.Context, appDb *sql.DB, clientId string, limit int) ([]*CaseAccord, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT
+			accords.id,
+			accords.for_case,
+			accords.content,
+			unixepoch(accords.date, 'unixepoch') as date,
+			cases.case_id,
+			cases.case_type,
+			cases.alias
+		FROM accords
+		INNER JOIN case_clients ON case_clients.case_id = accords.for_case
+		INNER JOIN cases ON cases.id = accords.for_case
+		WHERE case_clients.client_id = :ClientId AND cases.deleted_at IS NULL
+		ORDER BY date DESC
+		LIMIT :Limit`,
+		sql.Named("ClientId", clientId),
+		sql.Named("Limit", limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCaseAccords(rows)
+}
+
+func insertContact(ctx context.Context, appDb *sql.DB, table string, c *Client) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := appDb.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO %s (id, name, rfc, curp, email, phone, address, notes, created_at)
+			VALUES (:Id, :Name, :RFC, :CURP, :Email, :Phone, :Address, :Notes, :CreatedAt)`,
+			table,
+		),
+		sql.Named("Id", c.Id),
+		sql.Named("Name", c.Name),
+		sql.Named("RFC", c.RFC),
+		sql.Named("CURP", c.CURP),
+		sql.Named("Email", c.Email),
+		sql.Named("Phone", c.Phone),
+		sql.Named("Address", c.Address),
+		sql.Named("Notes", c.Notes),
+		sql.Named("CreatedAt", c.CreatedAt.Unix()),
+	)
+
+	return err
+}
+
+func updateContactById(ctx context.Context, appDb *sql.DB, table, id string, c *Client) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	_, err := appDb.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`UPDATE %s SET
+				name = :Name,
+				rfc = :RFC,
+				curp = :CURP,
+				email = :Email,
+				phone = :Phone,
+				address = :Address,
+				notes = :Notes
+			WHERE id = :Id`,
+			table,
+		),
+		sql.Named("Name", c.Name),
+		sql.Named("RFC", c.RFC),
+		sql.Named("CURP", c.CURP),
+		sql.Named("Email", c.Email),
+		sql.Named("Phone", c.Phone),
+		sql.Named("Address", c.Address),
+		sql.Named("Notes", c.Notes),
+		sql.Named("Id", id),
+	)
+
+	return err
+}
+
+const contactCols = "id, name, rfc, curp, email, phone, address, notes, created_at"
+
+func findContacts(ctx context.Context, appDb *sql.DB, table, search string) ([]*Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT %s FROM %s
+			WHERE :Search = '' OR name LIKE '%%'||:Search||'%%' OR rfc LIKE :Search||'%%' OR curp LIKE :Search||'%%'
+			ORDER BY name`,
+			contactCols,
+			table,
+		),
+		sql.Named("Search", strings.TrimSpace(search)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := []*Client{}
+	for rows.Next() {
+		c, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+func findContactById(ctx context.Context, appDb *sql.DB, table, id string) (*Client, error) {
+	row := appDb.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT %s FROM %s WHERE id = :Id", contactCols, table),
+		sql.Named("Id", id),
+	)
+
+	return scanContact(row)
+}
+
+func queryLinkedContacts(
+	ctx context.Context,
+	appDb *sql.DB,
+	table, caseId string,
+	add func(*Client, PartyRole),
+) error {
+	linkTable, linkCol := "case_clients", "client_id"
+	if table == partiesTable {
+		linkTable, linkCol = "case_parties", "party_id"
+	}
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT %[1]s.id, %[1]s.name, %[1]s.rfc, %[1]s.curp, %[1]s.email, %[1]s.phone, %[1]s.address, %[1]s.notes, %[1]s.created_at, %[2]s.role
+			FROM %[2]s
+			INNER JOIN %[1]s ON %[1]s.id = %[2]s.%[3]s
+			WHERE %[2]s.case_id = :CaseId
+			ORDER BY %[1]s.name`,
+			table,
+			linkTable,
+			linkCol,
+		),
+		sql.Named("CaseId", caseId),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role string
+		c, err := scanContact(rows, &role)
+		if err != nil {
+			return err
+		}
+
+		add(c, PartyRole(role))
+	}
+
+	return rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Scans the contact columns, in the order of contactCols, followed by
+// any extra column selected
+func scanContact(row rowScanner, extra ...any) (*Client, error) {
+	var (
+		c         = &Client{}
+		nRFC      sql.NullString
+		nCURP     sql.NullString
+		nEmail    sql.NullString
+		nPhone    sql.NullString
+		nAddress  sql.NullString
+		nNotes    sql.NullString
+		createdAt int64
+	)
+	dest := append([]any{&c.Id, &c.Name, &nRFC, &nCURP, &nEmail, &nPhone, &nAddress, &nNotes, &createdAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	c.RFC, c.CURP, c.Email = nRFC.String, nCURP.String, nEmail.String
+	c.Phone, c.Address, c.Notes = nPhone.String, nAddress.String, nNotes.String
+	c.CreatedAt = time.Unix(createdAt, 0)
+
+	return c, nil
+}
@@ -1,11 +1,13 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/vladwithcode/lex_app/internal"
 	_ "modernc.org/sqlite"
@@ -18,6 +20,14 @@ var debug = os.Getenv("LEX_DEBUG") == "true"
 
 var db *sql.DB
 
+// Implemented by both *sql.DB and *sql.Tx, so the functions taking it can
+// run as part of a transaction
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 func Connect() (db *sql.DB, err error) {
 	if debug && connStr == "" {
 		connStr = DEFAULT_CONN_STR
@@ -33,20 +43,35 @@ func Connect() (db *sql.DB, err error) {
 		}
 	}
 
-	db, err = sql.Open("sqlite", connStr)
+	db, err = sql.Open("sqlite", withPragmas(connStr))
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = db.Exec("PRAGMA foreign_keys = 1")
-
+	var foreignKeys int
+	err = db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys)
 	if err != nil {
 		return nil, fmt.Errorf("failed PRAGMA exec: %w\n  %w", ErrForeignKeysUnsupported, err)
 	}
+	if foreignKeys != 1 {
+		return nil, ErrForeignKeysUnsupported
+	}
 
 	return db, nil
 }
 
+// Adds the pragmas to the connection string, so the driver runs them on
+// every connection it opens. Running them on the *sql.DB only reaches
+// one connection of the pool
+func withPragmas(connStr string) string {
+	sep := "?"
+	if strings.Contains(connStr, "?") {
+		sep = "&"
+	}
+
+	return connStr + sep + "_pragma=foreign_keys(1)"
+}
+
 func EnsureDBFileExists(dbPath string) error {
 	_, err := os.Stat(dbPath)
 	if err == nil {
@@ -74,6 +99,40 @@ func EnsureDBFileExists(dbPath string) error {
 	return err
 }
 
+// Generates a list of named placeholders, to be used within an `IN (...)`
+// clause, along with the named args for each of the values
+func namedInArgs(prefix string, values []string) (string, []interface{}) {
+	placeholders := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		name := fmt.Sprintf("%s%d", prefix, i)
+		placeholders[i] = ":" + name
+		args[i] = sql.Named(name, v)
+	}
+
+	return strings.Join(placeholders, ", "), args
+}
+
+// Turns user input into a safe FTS5 query by quoting every term,
+// which matches documents containing all of them
+func quoteFtsTerms(search string) string {
+	return joinFtsTerms(search, false)
+}
+
+// Same as quoteFtsTerms but every term also matches as a prefix
+func prefixFtsTerms(search string) string {
+	return joinFtsTerms(search, true)
+}
+
+func joinFtsTerms(search string, prefix bool) string {
+	terms := strings.Fields(search)
+	for i, t := range terms {
+		terms[i] = ftsTerm(t, prefix)
+	}
+
+	return strings.Join(terms, " ")
+}
+
 // Common errors
 var (
 	ErrGenUUID                = errors.New("error generating UUID")
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrEmptyHearing = errors.New("hearing description can't be empty")
+
+// A hearing scheduled for a case
+type CaseHearing struct {
+	Id          string    `json:"id" db:"id"`
+	CaseId      string    `json:"caseId" db:"case_id"`
+	Date        time.Time `json:"date" db:"date"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+func NewCaseHearing(caseId string, date time.Time, description string) (*CaseHearing, error) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return nil, ErrEmptyHearing
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	return &CaseHearing{
+		Id:          id.String(),
+		CaseId:      caseId,
+		Date:        date,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+func InsertCaseHearing(ctx context.Context, appDb *sql.DB, hearing *CaseHearing) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := appDb.ExecContext(
+		ctx,
+		`INSERT INTO case_hearings (id, case_id, date, description, created_at)
+		VALUES (:Id, :CaseId, :Date, :Description, :CreatedAt)`,
+		sql.Named("Id", hearing.Id),
+		sql.Named("CaseId", hearing.CaseId),
+		sql.Named("Date", hearing.Date.Unix()),
+		sql.Named("Description", hearing.Description),
+		sql.Named("CreatedAt", hearing.CreatedAt.Unix()),
+	)
+
+	return err
+}
+
+func DeleteCaseHearingById(ctx context.Context, appDb *sql.DB, id string) error {
+	_, err := appDb.ExecContext(ctx, "DELETE FROM case_hearings WHERE id = :Id", sql.Named("Id", id))
+
+	return err
+}
+
+// Returns the hearings of the case from since onwards, the earliest first
+func FindUpcomingHearings(ctx context.Context, appDb *sql.DB, caseId string, since time.Time) ([]*CaseHearing, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT id, case_id, date, description, created_at
+		FROM case_hearings
+		WHERE case_id = :CaseId AND date >= :Since
+		ORDER BY date`,
+		sql.Named("CaseId", caseId),
+		sql.Named("Since", since.Unix()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hearings := []*CaseHearing{}
+	for rows.Next() {
+		var (
+			h         = &CaseHearing{}
+			date      int64
+			createdAt int64
+		)
+		if err := rows.Scan(&h.Id, &h.CaseId, &date, &h.Description, &createdAt); err != nil {
+			return nil, err
+		}
+
+		h.Date = time.Unix(date, 0)
+		h.CreatedAt = time.Unix(createdAt, 0)
+		hearings = append(hearings, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hearings, nil
+}
@@ -0,0 +1,206 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyNote       = errors.New("note content can't be empty")
+	ErrNoteNotFound    = errors.New("note not found")
+	ErrAccordNotInCase = errors.New("the accord doesn't belong to the case of the note")
+)
+
+// A markdown note attached to a case, optionally pinned to one of its accords
+type CaseNote struct {
+	Id        string    `json:"id" db:"id"`
+	CaseId    string    `json:"caseId" db:"case_id"`
+	AccordId  string    `json:"accordId" db:"accord_id"`
+	Content   string    `json:"content" db:"content"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+func NewCaseNote(caseId, content string) (*CaseNote, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyNote
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	now := time.Now()
+	return &CaseNote{
+		Id:        id.String(),
+		CaseId:    caseId,
+		Content:   content,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func InsertCaseNote(ctx context.Context, appDb *sql.DB, note *CaseNote) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := checkAccordCase(ctx, appDb, note.CaseId, note.AccordId); err != nil {
+		return err
+	}
+
+	_, err := appDb.ExecContext(
+		ctx,
+		`INSERT INTO case_notes (id, case_id, accord_id, content, created_at, updated_at)
+		VALUES (:Id, :CaseId, :AccordId, :Content, :CreatedAt, :UpdatedAt)`,
+		sql.Named("Id", note.Id),
+		sql.Named("CaseId", note.CaseId),
+		sql.Named("AccordId", sql.NullString{String: note.AccordId, Valid: note.AccordId != ""}),
+		sql.Named("Content", note.Content),
+		sql.Named("CreatedAt", note.CreatedAt.Unix()),
+		sql.Named("UpdatedAt", note.UpdatedAt.Unix()),
+	)
+
+	return err
+}
+
+// Replaces the content and pinned accord of the note.
+//
+// An empty AccordId unpins the note
+func UpdateCaseNoteById(ctx context.Context, appDb *sql.DB, id string, noteData *CaseNote) error {
+	if strings.TrimSpace(noteData.Content) == "" {
+		return ErrEmptyNote
+	}
+
+	var caseId string
+	err := appDb.QueryRowContext(ctx, "SELECT case_id FROM case_notes WHERE id = :Id", sql.Named("Id", id)).Scan(&caseId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoteNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkAccordCase(ctx, appDb, caseId, noteData.AccordId); err != nil {
+		return err
+	}
+
+	_, err = appDb.ExecContext(
+		ctx,
+		`UPDATE case_notes SET content = :Content, accord_id = :AccordId, updated_at = unixepoch()
+		WHERE id = :Id`,
+		sql.Named("Content", noteData.Content),
+		sql.Named("AccordId", sql.NullString{String: noteData.AccordId, Valid: noteData.AccordId != ""}),
+		sql.Named("Id", id),
+	)
+
+	return err
+}
+
+// Fails with ErrAccordNotInCase unless the accord is one of the case.
+// An empty accordId passes, as the note isn't pinned
+func checkAccordCase(ctx context.Context, appDb *sql.DB, caseId, accordId string) error {
+	if accordId == "" {
+		return nil
+	}
+
+	var exists bool
+	err := appDb.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM accords WHERE id = :AccordId AND for_case = :CaseId)",
+		sql.Named("AccordId", accordId),
+		sql.Named("CaseId", caseId),
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("accord %s, case %s:\n\t%w", accordId, caseId, ErrAccordNotInCase)
+	}
+
+	return nil
+}
+
+func DeleteCaseNoteById(ctx context.Context, appDb *sql.DB, id string) error {
+	_, err := appDb.ExecContext(ctx, "DELETE FROM case_notes WHERE id = :Id", sql.Named("Id", id))
+
+	return err
+}
+
+// Returns every note of the case, oldest first
+func FindNotesForCase(ctx context.Context, appDb *sql.DB, caseId string) ([]*CaseNote, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT id, case_id, accord_id, content, created_at, updated_at
+		FROM case_notes
+		WHERE case_id = :CaseId
+		ORDER BY created_at`,
+		sql.Named("CaseId", caseId),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCaseNotes(rows)
+}
+
+// Full text search over the content of the notes.
+//
+// If caseId is not empty only the notes for that case are searched
+func SearchCaseNotes(ctx context.Context, appDb *sql.DB, search, caseId string) ([]*CaseNote, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT case_notes.id, case_notes.case_id, case_notes.accord_id, case_notes.content, case_notes.created_at, case_notes.updated_at
+		FROM case_notes_fts
+		INNER JOIN case_notes ON case_notes.id = case_notes_fts.uuid
+		WHERE case_notes_fts MATCH :Search AND (:CaseId = '' OR case_notes.case_id = :CaseId)
+		ORDER BY rank`,
+		sql.Named("Search", quoteFtsTerms(search)),
+		sql.Named("CaseId", caseId),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCaseNotes(rows)
+}
+
+func scanCaseNotes(rows *sql.Rows) ([]*CaseNote, error) {
+	notes := []*CaseNote{}
+	for rows.Next() {
+		var (
+			n         = &CaseNote{}
+			nAccordId sql.NullString
+			createdAt int64
+			updatedAt int64
+		)
+		err := rows.Scan(&n.Id, &n.CaseId, &nAccordId, &n.Content, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		n.AccordId = nAccordId.String
+		n.CreatedAt = time.Unix(createdAt, 0)
+		n.UpdatedAt = time.Unix(updatedAt, 0)
+		notes = append(notes, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
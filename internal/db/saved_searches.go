@@ -0,0 +1,240 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vladwithcode/lex_app/internal/query"
+)
+
+var ErrInvalidSavedSearchName = errors.New("saved search name can't be empty")
+
+// A named set of filters, used as a smart list of cases
+type SavedSearch struct {
+	Id      string           `json:"id" db:"id"`
+	Name    string           `json:"name" db:"name"`
+	Options *FindCaseOptions `json:"options" db:"options"`
+	// Whether the cases of this search are included in scheduled update runs
+	Scheduled bool      `json:"scheduled" db:"scheduled"`
+	Position  int       `json:"position" db:"position"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// A saved search along with the number of cases it currently matches
+type SavedSearchCount struct {
+	Search *SavedSearch `json:"search"`
+	Count  int          `json:"count"`
+}
+
+func NewSavedSearch(name string, opts *FindCaseOptions) (*SavedSearch, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	now := time.Now()
+	ss := &SavedSearch{
+		Id:        id.String(),
+		Name:      name,
+		Options:   opts,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := ss.validate(); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+// Normalizes the search, keeping only the options that select cases
+func (ss *SavedSearch) validate() error {
+	ss.Name = strings.TrimSpace(ss.Name)
+	if ss.Name == "" {
+		return ErrInvalidSavedSearchName
+	}
+
+	opts := DefaultFindCaseOptions
+	if ss.Options != nil {
+		opts = *ss.Options
+	}
+	if strings.TrimSpace(opts.Query) != "" {
+		if _, err := query.Parse(opts.Query); err != nil {
+			return err
+		}
+	}
+
+	// Paging and the accords to include depend on where the search is run
+	opts.Limit = 0
+	opts.Cursor = ""
+	opts.IncludeAccords = false
+	opts.MaxAccords = DefaultFindCaseOptions.MaxAccords
+	ss.Options = &opts
+
+	return nil
+}
+
+func InsertSavedSearch(ctx context.Context, appDb *sql.DB, ss *SavedSearch) error {
+	if err := ss.validate(); err != nil {
+		return err
+	}
+
+	optsJson, err := json.Marshal(ss.Options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err = appDb.ExecContext(
+		ctx,
+		`INSERT INTO saved_searches (id, name, options, scheduled, position, created_at, updated_at)
+		VALUES (:Id, :Name, :Options, :Scheduled, (SELECT coalesce(max(position), -1) + 1 FROM saved_searches), :CreatedAt, :UpdatedAt)`,
+		sql.Named("Id", ss.Id),
+		sql.Named("Name", ss.Name),
+		sql.Named("Options", string(optsJson)),
+		sql.Named("Scheduled", ss.Scheduled),
+		sql.Named("CreatedAt", ss.CreatedAt.Unix()),
+		sql.Named("UpdatedAt", ss.UpdatedAt.Unix()),
+	)
+
+	return err
+}
+
+func UpdateSavedSearchById(ctx context.Context, appDb *sql.DB, id string, ss *SavedSearch) error {
+	if err := ss.validate(); err != nil {
+		return err
+	}
+
+	optsJson, err := json.Marshal(ss.Options)
+	if err != nil {
+		return err
+	}
+
+	_, err = appDb.ExecContext(
+		ctx,
+		`UPDATE saved_searches
+		SET name = :Name, options = :Options, scheduled = :Scheduled, position = :Position, updated_at = unixepoch()
+		WHERE id = :Id`,
+		sql.Named("Name", ss.Name),
+		sql.Named("Options", string(optsJson)),
+		sql.Named("Scheduled", ss.Scheduled),
+		sql.Named("Position", ss.Position),
+		sql.Named("Id", id),
+	)
+
+	return err
+}
+
+func DeleteSavedSearchById(ctx context.Context, appDb *sql.DB, id string) error {
+	_, err := appDb.ExecContext(ctx, "DELETE FROM saved_searches WHERE id = :Id", sql.Named("Id", id))
+
+	return err
+}
+
+func FindSavedSearchById(ctx context.Context, appDb *sql.DB, id string) (*SavedSearch, error) {
+	searches, err := findSavedSearches(ctx, appDb, "WHERE id = :Id", sql.Named("Id", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(searches) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return searches[0], nil
+}
+
+func FindSavedSearchByName(ctx context.Context, appDb *sql.DB, name string) (*SavedSearch, error) {
+	searches, err := findSavedSearches(ctx, appDb, "WHERE name = :Name", sql.Named("Name", strings.TrimSpace(name)))
+	if err != nil {
+		return nil, err
+	}
+	if len(searches) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return searches[0], nil
+}
+
+func FindAllSavedSearches(ctx context.Context, appDb *sql.DB) ([]*SavedSearch, error) {
+	return findSavedSearches(ctx, appDb, "")
+}
+
+// Saved searches flagged to be included in scheduled update runs
+func FindScheduledSavedSearches(ctx context.Context, appDb *sql.DB) ([]*SavedSearch, error) {
+	return findSavedSearches(ctx, appDb, "WHERE scheduled = 1")
+}
+
+// Returns every saved search with the number of cases it matches
+func FindSavedSearchCounts(ctx context.Context, appDb *sql.DB) ([]*SavedSearchCount, error) {
+	searches, err := FindAllSavedSearches(ctx, appDb)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]*SavedSearchCount, 0, len(searches))
+	for _, ss := range searches {
+		n, err := CountCases(ctx, appDb, ss.Options)
+		if err != nil {
+			return nil, fmt.Errorf("counting cases for saved search %q: %w", ss.Name, err)
+		}
+		counts = append(counts, &SavedSearchCount{Search: ss, Count: n})
+	}
+
+	return counts, nil
+}
+
+func findSavedSearches(ctx context.Context, appDb *sql.DB, where string, args ...interface{}) ([]*SavedSearch, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT id, name, options, scheduled, position, created_at, updated_at
+			FROM saved_searches %s
+			ORDER BY position, name`,
+			where,
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := []*SavedSearch{}
+	for rows.Next() {
+		var (
+			ss        = &SavedSearch{Options: &FindCaseOptions{}}
+			optsJson  string
+			createdAt int64
+			updatedAt int64
+		)
+		err := rows.Scan(&ss.Id, &ss.Name, &optsJson, &ss.Scheduled, &ss.Position, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(optsJson), ss.Options); err != nil {
+			return nil, fmt.Errorf("saved search %q has invalid options: %w", ss.Name, err)
+		}
+
+		ss.CreatedAt = time.Unix(createdAt, 0)
+		ss.UpdatedAt = time.Unix(updatedAt, 0)
+		searches = append(searches, ss)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return searches, nil
+}
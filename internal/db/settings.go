@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrSettingNotSet = errors.New("setting has not been set")
+
+// Decodes the JSON value of the setting into v.
+//
+// Returns ErrSettingNotSet if the setting has never been saved
+func FindSetting(ctx context.Context, appDb *sql.DB, key string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var value string
+	err := appDb.QueryRowContext(
+		ctx,
+		"SELECT value FROM settings WHERE key = :Key",
+		sql.Named("Key", key),
+	).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s:\n\t%w", key, ErrSettingNotSet)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(value), v)
+}
+
+// Stores v as the JSON value of the setting, replacing the previous one
+func SaveSetting(ctx context.Context, appDb *sql.DB, key string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = appDb.ExecContext(
+		ctx,
+		`INSERT INTO settings (key, value, updated_at) VALUES (:Key, :Value, unixepoch())
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		sql.Named("Key", key),
+		sql.Named("Value", string(value)),
+	)
+
+	return err
+}
@@ -0,0 +1,212 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/regions"
+)
+
+const (
+	// Threshold used when neither the case type nor the default have one set
+	DefaultStaleThresholdDays = 90
+	// Case type of the threshold applied to types without their own
+	StaleThresholdDefaultType = "*"
+
+	staleExcerptLength = 240
+)
+
+var ErrInvalidStaleThreshold = errors.New("stale threshold should be greater than 0 days")
+
+// Days without activity after which cases of CaseType are reported as stale
+type StaleThreshold struct {
+	CaseType string `json:"caseType" db:"case_type"`
+	Days     int    `json:"days" db:"days"`
+}
+
+// A case without accords for longer than the threshold of its type
+type StaleCase struct {
+	Id       string `json:"id"`
+	CaseId   string `json:"caseId"`
+	CaseType string `json:"caseType"`
+	Alias    string `json:"alias"`
+	// Date of the latest accord, or the creation of the case if it has none
+	LastActivityAt  time.Time `json:"lastActivityAt"`
+	LastActivityStr string    `json:"lastActivityStr"`
+	DaysInactive    int       `json:"daysInactive"`
+	ThresholdDays   int       `json:"thresholdDays"`
+	// Start of the content of the latest accord, empty if it has none
+	LastAccordExcerpt string `json:"lastAccordExcerpt"`
+}
+
+func FindStaleThresholds(ctx context.Context, appDb *sql.DB) ([]*StaleThreshold, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(ctx, "SELECT case_type, days FROM stale_thresholds ORDER BY case_type")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	thresholds := []*StaleThreshold{}
+	for rows.Next() {
+		st := &StaleThreshold{}
+		if err := rows.Scan(&st.CaseType, &st.Days); err != nil {
+			return nil, err
+		}
+		thresholds = append(thresholds, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return thresholds, nil
+}
+
+// Sets the threshold for caseType, StaleThresholdDefaultType sets the default
+func SetStaleThreshold(ctx context.Context, appDb *sql.DB, caseType string, days int) error {
+	if days <= 0 {
+		return ErrInvalidStaleThreshold
+	}
+
+	caseType = strings.TrimSpace(caseType)
+	if caseType != StaleThresholdDefaultType {
+		if err := validateStaleCaseType(caseType); err != nil {
+			return err
+		}
+	}
+
+	_, err := appDb.ExecContext(
+		ctx,
+		`INSERT INTO stale_thresholds (case_type, days) VALUES (:CaseType, :Days)
+		ON CONFLICT (case_type) DO UPDATE SET days = excluded.days`,
+		sql.Named("CaseType", caseType),
+		sql.Named("Days", days),
+	)
+
+	return err
+}
+
+// Thresholds apply to the case type in every region, so it must be
+// a case type of at least one of them
+func validateStaleCaseType(caseType string) error {
+	for _, r := range regions.All() {
+		if ValidateCaseType(string(r.Id), caseType) == nil {
+			return nil
+		}
+	}
+
+	return ValidateCaseType(string(internal.RegionDefault), caseType)
+}
+
+// Removes the threshold for caseType, which falls back to the default one
+func DeleteStaleThreshold(ctx context.Context, appDb *sql.DB, caseType string) error {
+	_, err := appDb.ExecContext(
+		ctx,
+		"DELETE FROM stale_thresholds WHERE case_type = :CaseType",
+		sql.Named("CaseType", caseType),
+	)
+
+	return err
+}
+
+// Returns the tracked cases whose latest activity is older than the
+// threshold of their type, the longest inactive first.
+//
+// A limit <= 0 returns every stale case
+func FindStaleCases(ctx context.Context, appDb *sql.DB, limit int) ([]*StaleCase, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pageLimit := ""
+	args := []interface{}{
+		sql.Named("defaultType", StaleThresholdDefaultType),
+		sql.Named("defaultDays", DefaultStaleThresholdDays),
+		sql.Named("now", time.Now().Unix()),
+	}
+	if limit > 0 {
+		pageLimit = "LIMIT :limit"
+		args = append(args, sql.Named("limit", limit))
+	}
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`WITH activity AS (
+				SELECT
+					cases.id,
+					cases.case_id,
+					cases.case_type,
+					coalesce(cases.alias, '') AS alias,
+					coalesce(CAST(last_accords.date AS INTEGER), cases.created_at, :now) AS last_activity,
+					coalesce(last_accords.content, '') AS content,
+					coalesce(
+						thresholds.days,
+						(SELECT days FROM stale_thresholds WHERE case_type = :defaultType),
+						:defaultDays
+					) AS threshold
+				FROM cases
+				LEFT JOIN (
+					SELECT for_case, content, max(date) AS date FROM accords GROUP BY for_case
+				) AS last_accords ON last_accords.for_case = cases.id
+				LEFT JOIN stale_thresholds AS thresholds ON thresholds.case_type = cases.case_type
+				WHERE cases.archived_at IS NULL AND cases.deleted_at IS NULL
+			)
+			SELECT id, case_id, case_type, alias, last_activity, content, threshold
+			FROM activity
+			WHERE last_activity <= :now - threshold * 86400
+			ORDER BY last_activity, id
+			%s`,
+			pageLimit,
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	stale := []*StaleCase{}
+	for rows.Next() {
+		var (
+			sc           = &StaleCase{}
+			lastActivity int64
+			content      string
+		)
+		err := rows.Scan(&sc.Id, &sc.CaseId, &sc.CaseType, &sc.Alias, &lastActivity, &content, &sc.ThresholdDays)
+		if err != nil {
+			return nil, err
+		}
+
+		sc.LastActivityAt = time.Unix(lastActivity, 0)
+		sc.LastActivityStr = sc.LastActivityAt.Format(time.RFC3339)
+		sc.DaysInactive = int(now.Sub(sc.LastActivityAt).Hours() / 24)
+		sc.LastAccordExcerpt = excerpt(content, staleExcerptLength)
+		stale = append(stale, sc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stale, nil
+}
+
+// Collapses the whitespace in s and cuts it to at most n runes
+func excerpt(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
@@ -0,0 +1,259 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type StatsPeriod string
+
+const (
+	StatsPeriodDay  StatsPeriod = "day"
+	StatsPeriodWeek StatsPeriod = "week"
+)
+
+const (
+	statsDateLayout          = "2006-01-02"
+	defaultStatsRangeDays    = 30
+	defaultBusiestTypesLimit = 5
+)
+
+var ErrInvalidStatsPeriod = errors.New("stats period should be either 'day' or 'week'")
+
+// Labels accords by their day or their week of the year, starting on monday
+var statsPeriodExprs = map[StatsPeriod]string{
+	StatsPeriodDay:  "strftime('%Y-%m-%d', accords.date, 'unixepoch')",
+	StatsPeriodWeek: "strftime('%Y-W%W', accords.date, 'unixepoch')",
+}
+
+type StatsOptions struct {
+	// Range of accord dates formatted as 'YYYY-MM-DD', both inclusive.
+	// Defaults to the last 30 days
+	From string
+	To   string
+	// How accords are grouped, defaults to StatsPeriodDay
+	Period StatsPeriod
+	// Days without accords after which a case counts as inactive
+	InactiveDays int
+	// Number of case types returned in BusiestTypes
+	BusiestLimit int
+}
+
+type StatCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+type UpdateRunStats struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	// Succeeded / Total, 0 when there were no runs
+	SuccessRate float64 `json:"successRate"`
+}
+
+type DashboardStats struct {
+	CasesByType []*StatCount `json:"casesByType"`
+	CasesByYear []*StatCount `json:"casesByYear"`
+	CasesByTag  []*StatCount `json:"casesByTag"`
+	// Accords in the range, keyed by day or week
+	AccordsPerPeriod []*StatCount `json:"accordsPerPeriod"`
+	// Case types with the most accords in the range
+	BusiestTypes  []*StatCount    `json:"busiestTypes"`
+	UpdateRuns    *UpdateRunStats `json:"updateRuns"`
+	InactiveCases int             `json:"inactiveCases"`
+}
+
+// Computes every aggregate shown in the dashboard.
+//
+// Only tracked cases (not archived nor deleted) are counted
+func FindDashboardStats(ctx context.Context, appDb *sql.DB, opts *StatsOptions) (*DashboardStats, error) {
+	if opts == nil {
+		opts = &StatsOptions{}
+	}
+
+	from, to, err := statsRange(opts)
+	if err != nil {
+		return nil, err
+	}
+	period := opts.Period
+	if period == "" {
+		period = StatsPeriodDay
+	}
+	periodExpr, ok := statsPeriodExprs[period]
+	if !ok {
+		return nil, ErrInvalidStatsPeriod
+	}
+	inactiveDays := opts.InactiveDays
+	if inactiveDays <= 0 {
+		inactiveDays = DefaultStaleThresholdDays
+	}
+	busiestLimit := opts.BusiestLimit
+	if busiestLimit <= 0 {
+		busiestLimit = defaultBusiestTypesLimit
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	stats := &DashboardStats{}
+	rangeArgs := []interface{}{
+		sql.Named("from", from.Unix()),
+		sql.Named("to", to.Unix()),
+	}
+
+	stats.CasesByType, err = queryStatCounts(
+		ctx,
+		appDb,
+		`SELECT case_type, count(*) FROM cases
+		WHERE archived_at IS NULL AND deleted_at IS NULL
+		GROUP BY case_type
+		ORDER BY count(*) DESC, case_type`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.CasesByYear, err = queryStatCounts(
+		ctx,
+		appDb,
+		`SELECT case_year, count(*) FROM cases
+		WHERE archived_at IS NULL AND deleted_at IS NULL
+		GROUP BY case_year
+		ORDER BY CAST(case_year AS INTEGER) DESC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.CasesByTag, err = queryStatCounts(
+		ctx,
+		appDb,
+		`SELECT tags.name, count(cases.id) FROM tags
+		LEFT JOIN case_tags ON case_tags.tag_id = tags.id
+		LEFT JOIN cases ON cases.id = case_tags.case_id
+			AND cases.archived_at IS NULL AND cases.deleted_at IS NULL
+		GROUP BY tags.id
+		ORDER BY count(cases.id) DESC, tags.name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.AccordsPerPeriod, err = queryStatCounts(
+		ctx,
+		appDb,
+		fmt.Sprintf(
+			`SELECT %[1]s, count(*) FROM accords
+			INNER JOIN cases ON cases.id = accords.for_case
+			WHERE accords.date >= :from AND accords.date < :to
+				AND cases.archived_at IS NULL AND cases.deleted_at IS NULL
+			GROUP BY %[1]s
+			ORDER BY %[1]s`,
+			periodExpr,
+		),
+		rangeArgs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.BusiestTypes, err = queryStatCounts(
+		ctx,
+		appDb,
+		`SELECT cases.case_type, count(*) FROM accords
+		INNER JOIN cases ON cases.id = accords.for_case
+		WHERE accords.date >= :from AND accords.date < :to
+			AND cases.archived_at IS NULL AND cases.deleted_at IS NULL
+		GROUP BY cases.case_type
+		ORDER BY count(*) DESC, cases.case_type
+		LIMIT :limit`,
+		append(rangeArgs, sql.Named("limit", busiestLimit))...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.UpdateRuns = &UpdateRunStats{}
+	err = appDb.QueryRowContext(
+		ctx,
+		`SELECT count(*), count(*) FILTER (WHERE status != :failed), count(*) FILTER (WHERE status = :failed)
+		FROM update_runs
+		WHERE started_at >= :from AND started_at < :to`,
+		append(rangeArgs, sql.Named("failed", UpdateRunFailed))...,
+	).Scan(&stats.UpdateRuns.Total, &stats.UpdateRuns.Succeeded, &stats.UpdateRuns.Failed)
+	if err != nil {
+		return nil, err
+	}
+	if stats.UpdateRuns.Total > 0 {
+		stats.UpdateRuns.SuccessRate = float64(stats.UpdateRuns.Succeeded) / float64(stats.UpdateRuns.Total)
+	}
+
+	err = appDb.QueryRowContext(
+		ctx,
+		`SELECT count(*) FROM cases
+		WHERE archived_at IS NULL AND deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM accords WHERE accords.for_case = cases.id AND accords.date >= :since
+			)`,
+		sql.Named("since", time.Now().AddDate(0, 0, -inactiveDays).Unix()),
+	).Scan(&stats.InactiveCases)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Parses the date range of opts, to is moved to the end of its day
+func statsRange(opts *StatsOptions) (from, to time.Time, err error) {
+	now := time.Now()
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if opts.To != "" {
+		to, err = time.ParseInLocation(statsDateLayout, opts.To, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid stats range end %q:\n\t%w", opts.To, err)
+		}
+	}
+	to = to.AddDate(0, 0, 1)
+
+	from = to.AddDate(0, 0, -defaultStatsRangeDays)
+	if opts.From != "" {
+		from, err = time.ParseInLocation(statsDateLayout, opts.From, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid stats range start %q:\n\t%w", opts.From, err)
+		}
+	}
+
+	return from, to, nil
+}
+
+func queryStatCounts(ctx context.Context, appDb *sql.DB, query string, args ...interface{}) ([]*StatCount, error) {
+	rows, err := appDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*StatCount{}
+	for rows.Next() {
+		var (
+			sc  = &StatCount{}
+			key sql.NullString
+		)
+		if err := rows.Scan(&key, &sc.Count); err != nil {
+			return nil, err
+		}
+		sc.Key = key.String
+		counts = append(counts, sc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
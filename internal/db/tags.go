@@ -0,0 +1,270 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTagName  = errors.New("tag name can't be empty")
+	ErrInvalidTagColor = errors.New("tag color should be formatted as '#rrggbb'")
+	ErrNoCasesOrTags   = errors.New("at least one case and one tag are required")
+)
+
+var tagColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type Tag struct {
+	Id    string `json:"id" db:"id"`
+	Name  string `json:"name" db:"name"`
+	Color string `json:"color" db:"color"`
+}
+
+func NewTag(name, color string) (*Tag, error) {
+	t := &Tag{
+		Name:  strings.TrimSpace(name),
+		Color: color,
+	}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+	t.Id = id.String()
+
+	return t, nil
+}
+
+func (t *Tag) validate() error {
+	if t.Name == "" {
+		return ErrInvalidTagName
+	}
+	if t.Color != "" && !tagColorRegex.MatchString(t.Color) {
+		return fmt.Errorf("%s is not a valid color:\n\t%w", t.Color, ErrInvalidTagColor)
+	}
+
+	return nil
+}
+
+func InsertTag(ctx context.Context, appDb DBTX, tag *Tag) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := appDb.ExecContext(
+		ctx,
+		"INSERT INTO tags (id, name, color) VALUES (:Id, :Name, :Color)",
+		sql.Named("Id", tag.Id),
+		sql.Named("Name", tag.Name),
+		sql.Named("Color", tag.Color),
+	)
+
+	return err
+}
+
+func FindAllTags(ctx context.Context, appDb DBTX) ([]*Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(ctx, "SELECT id, name, color FROM tags ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []*Tag{}
+	for rows.Next() {
+		t := &Tag{}
+		if err := rows.Scan(&t.Id, &t.Name, &t.Color); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func UpdateTagById(ctx context.Context, appDb *sql.DB, id string, tagData *Tag) error {
+	tagData.Name = strings.TrimSpace(tagData.Name)
+	if err := tagData.validate(); err != nil {
+		return err
+	}
+
+	_, err := appDb.ExecContext(
+		ctx,
+		"UPDATE tags SET name = :Name, color = :Color WHERE id = :Id",
+		sql.Named("Name", tagData.Name),
+		sql.Named("Color", tagData.Color),
+		sql.Named("Id", id),
+	)
+
+	return err
+}
+
+// Removes the tag and every association it had with cases
+func DeleteTagById(ctx context.Context, appDb *sql.DB, id string) error {
+	_, err := appDb.ExecContext(ctx, "DELETE FROM tags WHERE id = :Id", sql.Named("Id", id))
+
+	return err
+}
+
+// Associates every tag in tagIds with every case in caseIds.
+//
+// Existing associations are left untouched
+func TagCases(ctx context.Context, appDb *sql.DB, caseIds, tagIds []string) error {
+	return execCaseTags(
+		ctx,
+		appDb,
+		"INSERT OR IGNORE INTO case_tags (case_id, tag_id) VALUES (:CaseId, :TagId)",
+		caseIds,
+		tagIds,
+	)
+}
+
+// Removes every tag in tagIds from every case in caseIds
+func UntagCases(ctx context.Context, appDb *sql.DB, caseIds, tagIds []string) error {
+	return execCaseTags(
+		ctx,
+		appDb,
+		"DELETE FROM case_tags WHERE case_id = :CaseId AND tag_id = :TagId",
+		caseIds,
+		tagIds,
+	)
+}
+
+// Same as TagCases for a single case, without starting a transaction
+// of its own
+func AddCaseTags(ctx context.Context, appDb DBTX, caseId string, tagIds []string) error {
+	for _, tagId := range tagIds {
+		_, err := appDb.ExecContext(
+			ctx,
+			"INSERT OR IGNORE INTO case_tags (case_id, tag_id) VALUES (:CaseId, :TagId)",
+			sql.Named("CaseId", caseId),
+			sql.Named("TagId", tagId),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func execCaseTags(ctx context.Context, appDb *sql.DB, query string, caseIds, tagIds []string) error {
+	if len(caseIds) == 0 || len(tagIds) == 0 {
+		return ErrNoCasesOrTags
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tx, err := appDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, caseId := range caseIds {
+		for _, tagId := range tagIds {
+			_, err := stmt.ExecContext(
+				ctx,
+				sql.Named("CaseId", caseId),
+				sql.Named("TagId", tagId),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Loads the tags of every case in the slice in a single query
+func attachCaseTags(ctx context.Context, appDb *sql.DB, cases []*LexCase) error {
+	if len(cases) == 0 {
+		return nil
+	}
+
+	caseMap := make(map[string]*LexCase, len(cases))
+	for _, c := range cases {
+		c.Tags = []*Tag{}
+		caseMap[c.Id] = c
+	}
+
+	ids := make([]string, 0, len(caseMap))
+	for id := range caseMap {
+		ids = append(ids, id)
+	}
+	inList, args := namedInArgs("caseId", ids)
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT case_tags.case_id, tags.id, tags.name, tags.color
+			FROM case_tags
+			INNER JOIN tags ON tags.id = case_tags.tag_id
+			WHERE case_tags.case_id IN (%s)
+			ORDER BY tags.name`,
+			inList,
+		),
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var caseId string
+		t := &Tag{}
+		if err := rows.Scan(&caseId, &t.Id, &t.Name, &t.Color); err != nil {
+			return err
+		}
+
+		if c, ok := caseMap[caseId]; ok {
+			c.Tags = append(c.Tags, t)
+		}
+	}
+
+	return rows.Err()
+}
+
+// Builds a condition matching cases that have every tag named in tagNames.
+//
+// Returns the condition and the named args it references
+func caseTagsCondition(tagNames []string) (string, []interface{}) {
+	inList, args := namedInArgs("tag", tagNames)
+	args = append(args, sql.Named("tagCount", len(tagNames)))
+
+	cond := fmt.Sprintf(
+		`cases.id IN (
+			SELECT case_tags.case_id FROM case_tags
+			INNER JOIN tags ON tags.id = case_tags.tag_id
+			WHERE tags.name IN (%s)
+			GROUP BY case_tags.case_id
+			HAVING count(DISTINCT tags.id) = :tagCount
+		)`,
+		inList,
+	)
+
+	return cond, args
+}
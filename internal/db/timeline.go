@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+)
+
+type TimelineEntryKind string
+
+const (
+	TimelineAccord TimelineEntryKind = "accord"
+	TimelineNote   TimelineEntryKind = "note"
+	TimelineEvent  TimelineEntryKind = "event"
+)
+
+// Events generated from the state of the case
+const (
+	TimelineEventArchived = "case.archived"
+	TimelineEventDeleted  = "case.deleted"
+)
+
+// A single entry in the timeline of a case.
+//
+// Only the field matching Kind is set
+type TimelineEntry struct {
+	Kind    TimelineEntryKind `json:"kind"`
+	Date    time.Time         `json:"date"`
+	DateStr string            `json:"dateStr"`
+	Accord  *Accord           `json:"accord,omitempty"`
+	Note    *CaseNote         `json:"note,omitempty"`
+	Event   string            `json:"event,omitempty"`
+}
+
+// Same as FindCaseWithAccords but also fills the Timeline of the case
+func FindCaseWithTimeline(ctx context.Context, appDb *sql.DB, id string, accordCount int) (*LexCase, error) {
+	c, err := FindCaseWithAccords(ctx, appDb, id, accordCount)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Timeline, err = BuildCaseTimeline(ctx, appDb, c)
+	if err != nil {
+		return nil, err
+	}
+
+	// The older accords were left out, so are the entries older
+	// than the last accord loaded
+	if accordCount > 0 && len(c.Accords) == accordCount {
+		c.Timeline = timelineSince(c.Timeline, c.Accords[len(c.Accords)-1].Date)
+	}
+
+	return c, nil
+}
+
+// Drops the entries of the sorted timeline dated before since
+func timelineSince(timeline []*TimelineEntry, since time.Time) []*TimelineEntry {
+	for i, entry := range timeline {
+		if entry.Date.Before(since) {
+			return timeline[:i]
+		}
+	}
+
+	return timeline
+}
+
+// Merges the accords already loaded in the case with its notes and
+// generated events, sorted from the newest to the oldest entry
+func BuildCaseTimeline(ctx context.Context, appDb *sql.DB, c *LexCase) ([]*TimelineEntry, error) {
+	notes, err := FindNotesForCase(ctx, appDb, c.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := make([]*TimelineEntry, 0, len(c.Accords)+len(notes)+2)
+	for _, a := range c.Accords {
+		timeline = append(timeline, &TimelineEntry{
+			Kind:   TimelineAccord,
+			Date:   a.Date,
+			Accord: a,
+		})
+	}
+	for _, n := range notes {
+		timeline = append(timeline, &TimelineEntry{
+			Kind: TimelineNote,
+			Date: n.CreatedAt,
+			Note: n,
+		})
+	}
+	if c.ArchivedAt != nil {
+		timeline = append(timeline, &TimelineEntry{
+			Kind:  TimelineEvent,
+			Date:  *c.ArchivedAt,
+			Event: TimelineEventArchived,
+		})
+	}
+	if c.DeletedAt != nil {
+		timeline = append(timeline, &TimelineEntry{
+			Kind:  TimelineEvent,
+			Date:  *c.DeletedAt,
+			Event: TimelineEventDeleted,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Date.After(timeline[j].Date)
+	})
+	for _, entry := range timeline {
+		entry.DateStr = entry.Date.Format(time.RFC3339)
+	}
+
+	return timeline, nil
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UpdateRunStatus string
+
+const (
+	// Accords were found and saved
+	UpdateRunOk UpdateRunStatus = "ok"
+	// The search completed but no case had new accords
+	UpdateRunNoUpdates UpdateRunStatus = "no_updates"
+	UpdateRunFailed    UpdateRunStatus = "failed"
+)
+
+// Where an update run was started from
+const (
+	UpdateRunSourceApp      = "app"
+	UpdateRunSourceCli      = "cli"
+	UpdateRunSourceSchedule = "schedule"
+	UpdateRunSourceApi      = "api"
+)
+
+// Record of a single search for accord updates
+type UpdateRun struct {
+	Id            string          `json:"id" db:"id"`
+	Source        string          `json:"source" db:"source"`
+	Status        UpdateRunStatus `json:"status" db:"status"`
+	CaseCount     int             `json:"caseCount" db:"case_count"`
+	NotFoundCount int             `json:"notFoundCount" db:"not_found_count"`
+	Error         string          `json:"error" db:"error"`
+	StartedAt     time.Time       `json:"startedAt" db:"started_at"`
+	FinishedAt    time.Time       `json:"finishedAt" db:"finished_at"`
+}
+
+func NewUpdateRun(source string, caseCount int) (*UpdateRun, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	return &UpdateRun{
+		Id:        id.String(),
+		Source:    source,
+		CaseCount: caseCount,
+		StartedAt: time.Now(),
+	}, nil
+}
+
+// Sets the outcome of the run from the results of the update.
+//
+// noUpdates reports whether err only means that there was nothing new
+func (run *UpdateRun) Finish(notFoundKeys []string, err error, noUpdates bool) {
+	run.FinishedAt = time.Now()
+
+	switch {
+	case noUpdates:
+		run.Status = UpdateRunNoUpdates
+		run.NotFoundCount = run.CaseCount
+	case err != nil:
+		run.Status = UpdateRunFailed
+		run.NotFoundCount = run.CaseCount
+		run.Error = err.Error()
+	default:
+		run.Status = UpdateRunOk
+		run.NotFoundCount = len(notFoundKeys)
+	}
+}
+
+func InsertUpdateRun(ctx context.Context, appDb *sql.DB, run *UpdateRun) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := appDb.ExecContext(
+		ctx,
+		`INSERT INTO update_runs (id, source, status, case_count, not_found_count, error, started_at, finished_at)
+		VALUES (:Id, :Source, :Status, :CaseCount, :NotFoundCount, :Error, :StartedAt, :FinishedAt)`,
+		sql.Named("Id", run.Id),
+		sql.Named("Source", run.Source),
+		sql.Named("Status", run.Status),
+		sql.Named("CaseCount", run.CaseCount),
+		sql.Named("NotFoundCount", run.NotFoundCount),
+		sql.Named("Error", run.Error),
+		sql.Named("StartedAt", run.StartedAt.Unix()),
+		sql.Named("FinishedAt", run.FinishedAt.Unix()),
+	)
+
+	return err
+}
+
+// Returns the latest update runs, newest first
+func FindUpdateRuns(ctx context.Context, appDb *sql.DB, limit int) ([]*UpdateRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT id, source, status, case_count, not_found_count, error, started_at, finished_at
+		FROM update_runs
+		ORDER BY started_at DESC, id DESC
+		LIMIT :limit`,
+		sql.Named("limit", limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []*UpdateRun{}
+	for rows.Next() {
+		var (
+			run        = &UpdateRun{}
+			startedAt  int64
+			finishedAt int64
+		)
+		err := rows.Scan(&run.Id, &run.Source, &run.Status, &run.CaseCount, &run.NotFoundCount, &run.Error, &startedAt, &finishedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		run.StartedAt = time.Unix(startedAt, 0)
+		run.FinishedAt = time.Unix(finishedAt, 0)
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,409 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/readers"
+)
+
+var (
+	ErrWatchItemNotFound = errors.New("watchlist item not found")
+	ErrWatchItemPromoted = errors.New("watchlist item was already promoted to a case")
+)
+
+// A case number searched for before it's registered as a case,
+// e.g. the matter of a prospective client
+type WatchItem struct {
+	Id            string     `json:"id" db:"id"`
+	CaseId        string     `json:"caseId" db:"case_id"`
+	CaseType      string     `json:"caseType" db:"case_type"`
+	Region        string     `json:"region" db:"region"`
+	Note          string     `json:"note" db:"note"`
+	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt" db:"last_checked_at"`
+	// Set once the item is promoted into a case
+	PromotedCaseId string     `json:"promotedCaseId" db:"promoted_case_id"`
+	PromotedAt     *time.Time `json:"promotedAt" db:"promoted_at"`
+
+	HitCount  int         `json:"hitCount"`
+	LastHitAt *time.Time  `json:"lastHitAt"`
+	Hits      []*WatchHit `json:"hits"`
+}
+
+// An accord found for a watched case number
+type WatchHit struct {
+	Id      string    `json:"id" db:"id"`
+	WatchId string    `json:"watchId" db:"watch_id"`
+	Date    time.Time `json:"date" db:"date"`
+	Nature  string    `json:"nature" db:"nature"`
+	Content string    `json:"content" db:"content"`
+	FoundAt time.Time `json:"foundAt" db:"found_at"`
+}
+
+func NewWatchItem(caseId, caseType, region, note string) (*WatchItem, error) {
+	// Validated the same way as the case it may become
+	c, err := NewRegionCase(caseId, caseType, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WatchItem{
+		Id:        c.Id,
+		CaseId:    c.CaseId,
+		CaseType:  c.CaseType,
+		Region:    c.Region,
+		Note:      strings.TrimSpace(note),
+		CreatedAt: time.Now(),
+		Hits:      []*WatchHit{},
+	}, nil
+}
+
+func (w *WatchItem) GetCaseKey() string {
+	return w.CaseId + readers.CaseKeySeparator + w.CaseType
+}
+
+// Region the item is searched in
+func (w *WatchItem) SearchRegion() internal.Region {
+	if w.Region == "" {
+		return internal.RegionDefault
+	}
+
+	return internal.Region(w.Region)
+}
+
+func NewWatchHit(watchId string, date time.Time, nature, content string) (*WatchHit, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	return &WatchHit{
+		Id:      id.String(),
+		WatchId: watchId,
+		Date:    date,
+		Nature:  nature,
+		Content: content,
+		FoundAt: time.Now(),
+	}, nil
+}
+
+func InsertWatchItem(ctx context.Context, appDb *sql.DB, w *WatchItem) error {
+	_, err := appDb.ExecContext(
+		ctx,
+		`INSERT INTO watchlist (id, case_id, case_type, region, note, created_at)
+		VALUES (:Id, :CaseId, :CaseType, :Region, :Note, :CreatedAt)`,
+		sql.Named("Id", w.Id),
+		sql.Named("CaseId", w.CaseId),
+		sql.Named("CaseType", w.CaseType),
+		sql.Named("Region", w.Region),
+		sql.Named("Note", w.Note),
+		sql.Named("CreatedAt", w.CreatedAt.Unix()),
+	)
+
+	return err
+}
+
+func UpdateWatchItemNote(ctx context.Context, appDb *sql.DB, id, note string) error {
+	res, err := appDb.ExecContext(
+		ctx,
+		"UPDATE watchlist SET note = :Note WHERE id = :Id",
+		sql.Named("Note", strings.TrimSpace(note)),
+		sql.Named("Id", id),
+	)
+	if err != nil {
+		return err
+	}
+
+	return watchItemAffected(res)
+}
+
+// Deletes the item and its hits. A case it was promoted to is kept
+func DeleteWatchItemById(ctx context.Context, appDb *sql.DB, id string) error {
+	res, err := appDb.ExecContext(ctx, "DELETE FROM watchlist WHERE id = :Id", sql.Named("Id", id))
+	if err != nil {
+		return err
+	}
+
+	return watchItemAffected(res)
+}
+
+// Returns the watchlist, newest first. Promoted items are left
+// out unless includePromoted is set
+func FindWatchItems(ctx context.Context, appDb DBTX, includePromoted bool) ([]*WatchItem, error) {
+	where := "watchlist.promoted_at IS NULL"
+	if includePromoted {
+		where = "1 = 1"
+	}
+
+	return findWatchItems(ctx, appDb, where+" ORDER BY watchlist.created_at DESC, watchlist.id DESC")
+}
+
+// Returns the item with all of its hits, newest first
+func FindWatchItemById(ctx context.Context, appDb DBTX, id string) (*WatchItem, error) {
+	items, err := findWatchItems(ctx, appDb, "watchlist.id = :Id", sql.Named("Id", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrWatchItemNotFound
+	}
+	w := items[0]
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		"SELECT id, watch_id, date, nature, content, found_at FROM watchlist_hits WHERE watch_id = :Id ORDER BY date DESC",
+		sql.Named("Id", id),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		h := &WatchHit{}
+		var date, foundAt int64
+		if err := rows.Scan(&h.Id, &h.WatchId, &date, &h.Nature, &h.Content, &foundAt); err != nil {
+			return nil, err
+		}
+		h.Date = time.Unix(date, 0)
+		h.FoundAt = time.Unix(foundAt, 0)
+		w.Hits = append(w.Hits, h)
+	}
+
+	return w, rows.Err()
+}
+
+func findWatchItems(ctx context.Context, appDb DBTX, where string, args ...interface{}) ([]*WatchItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT
+			watchlist.id,
+			watchlist.case_id,
+			watchlist.case_type,
+			watchlist.region,
+			watchlist.note,
+			watchlist.created_at,
+			watchlist.last_checked_at,
+			watchlist.promoted_case_id,
+			watchlist.promoted_at,
+			(SELECT count(*) FROM watchlist_hits WHERE watch_id = watchlist.id),
+			(SELECT max(date) FROM watchlist_hits WHERE watch_id = watchlist.id)
+		FROM watchlist
+		WHERE `+where,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*WatchItem{}
+	for rows.Next() {
+		w := &WatchItem{Hits: []*WatchHit{}}
+		var (
+			createdAt      int64
+			lastCheckedAt  sql.NullInt64
+			promotedCaseId sql.NullString
+			promotedAt     sql.NullInt64
+			lastHitAt      sql.NullInt64
+		)
+		err := rows.Scan(
+			&w.Id,
+			&w.CaseId,
+			&w.CaseType,
+			&w.Region,
+			&w.Note,
+			&createdAt,
+			&lastCheckedAt,
+			&promotedCaseId,
+			&promotedAt,
+			&w.HitCount,
+			&lastHitAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		w.CreatedAt = time.Unix(createdAt, 0)
+		w.LastCheckedAt = nullUnixToTime(lastCheckedAt)
+		w.PromotedCaseId = promotedCaseId.String
+		w.PromotedAt = nullUnixToTime(promotedAt)
+		w.LastHitAt = nullUnixToTime(lastHitAt)
+
+		items = append(items, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Stores the hits, skipping the ones already recorded for the same
+// item and date. Returns the hits that were new
+func InsertWatchHits(ctx context.Context, appDb *sql.DB, hits []*WatchHit) ([]*WatchHit, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tx, err := appDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	inserted := []*WatchHit{}
+	for _, h := range hits {
+		res, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO watchlist_hits (id, watch_id, date, nature, content, found_at)
+			VALUES (:Id, :WatchId, :Date, :Nature, :Content, :FoundAt)
+			ON CONFLICT (watch_id, date) DO NOTHING`,
+			sql.Named("Id", h.Id),
+			sql.Named("WatchId", h.WatchId),
+			sql.Named("Date", h.Date.Unix()),
+			sql.Named("Nature", h.Nature),
+			sql.Named("Content", h.Content),
+			sql.Named("FoundAt", h.FoundAt.Unix()),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			inserted = append(inserted, h)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
+
+func MarkWatchItemsChecked(ctx context.Context, appDb *sql.DB, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	inList, args := namedInArgs("id", ids)
+	args = append(args, sql.Named("At", at.Unix()))
+	_, err := appDb.ExecContext(
+		ctx,
+		fmt.Sprintf("UPDATE watchlist SET last_checked_at = :At WHERE id IN (%s)", inList),
+		args...,
+	)
+
+	return err
+}
+
+// Turns the item into a case and copies its hits as the accords of
+// the case. If the case was registered in the meantime the hits are
+// added to it instead, created reports which one happened.
+//
+// Returns ErrCaseInTrash when the case is in the trash, so it's
+// restored before promoting the item again
+func PromoteWatchItem(ctx context.Context, appDb *sql.DB, id, alias string) (c *LexCase, created bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tx, err := appDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	w, err := FindWatchItemById(ctx, tx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if w.PromotedAt != nil {
+		return nil, false, ErrWatchItemPromoted
+	}
+
+	c, err = FindRegionCase(ctx, tx, string(w.SearchRegion()), w.GetCaseKey())
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c, err = NewRegionCase(w.CaseId, w.CaseType, w.Region)
+		if err != nil {
+			return nil, false, err
+		}
+		c.Alias = strings.TrimSpace(alias)
+		// Hits are sorted newest first
+		if len(w.Hits) > 0 {
+			c.Nature = w.Hits[0].Nature
+		}
+		if err := InsertCase(ctx, tx, c); err != nil {
+			return nil, false, err
+		}
+		created = true
+	case err != nil:
+		return nil, false, err
+	default:
+		trashedId, findErr := FindTrashedCaseId(ctx, tx, w.CaseId, w.CaseType, string(w.SearchRegion()))
+		if findErr == nil {
+			return nil, false, fmt.Errorf("%s (%s):\n\t%w", w.GetCaseKey(), trashedId, ErrCaseInTrash)
+		}
+		if !errors.Is(findErr, ErrCaseNotFound) {
+			return nil, false, findErr
+		}
+	}
+
+	for _, h := range w.Hits {
+		accId, err := uuid.NewV7()
+		if err != nil {
+			return nil, false, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+		}
+
+		// Dates the case already has an accord for are skipped
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO accords (id, for_case, content, date) VALUES (:Id, :ForCase, :Content, :Date)
+			ON CONFLICT (for_case, date) DO NOTHING`,
+			sql.Named("Id", accId.String()),
+			sql.Named("ForCase", c.Id),
+			sql.Named("Content", h.Content),
+			sql.Named("Date", h.Date.Unix()),
+		)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE watchlist SET promoted_case_id = :CaseId, promoted_at = unixepoch() WHERE id = :Id",
+		sql.Named("CaseId", c.Id),
+		sql.Named("Id", w.Id),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	return c, created, nil
+}
+
+func watchItemAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrWatchItemNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,415 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events webhooks can subscribe to
+const (
+	// An accord of a tracked case was found by an update
+	WebhookEventAccordCreated = "accord.created"
+	WebhookEventCaseCreated   = "case.created"
+	WebhookEventCaseArchived  = "case.archived"
+	// An update run finished, whatever its outcome
+	WebhookEventUpdateRunFinished = "update_run.finished"
+	// A case number of the watchlist appeared in a bulletin
+	WebhookEventWatchHit = "watchlist.hit"
+	// Only sent by test deliveries, webhooks don't need to subscribe to it
+	WebhookEventTest = "webhook.test"
+)
+
+var AllWebhookEvents = []string{
+	WebhookEventAccordCreated,
+	WebhookEventCaseCreated,
+	WebhookEventCaseArchived,
+	WebhookEventUpdateRunFinished,
+	WebhookEventWatchHit,
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	// Not delivered yet, retried at NextAttemptAt
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// Every attempt failed
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+var (
+	ErrInvalidWebhookUrl   = errors.New("webhook url should be an absolute http or https url")
+	ErrInvalidWebhookEvent = errors.New("unknown webhook event")
+	ErrNoWebhookEvents     = errors.New("webhook should subscribe to at least one event")
+)
+
+type Webhook struct {
+	Id          string `json:"id" db:"id"`
+	Url         string `json:"url" db:"url"`
+	Description string `json:"description" db:"description"`
+	// Key of the HMAC signature sent with every delivery
+	Secret    string    `json:"secret" db:"secret"`
+	Events    []string  `json:"events" db:"events"`
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// A single event sent, or to be sent, to a webhook
+type WebhookDelivery struct {
+	Id        string                `json:"id" db:"id"`
+	WebhookId string                `json:"webhookId" db:"webhook_id"`
+	Event     string                `json:"event" db:"event"`
+	Payload   string                `json:"payload" db:"payload"`
+	Status    WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts  int                   `json:"attempts" db:"attempts"`
+	// HTTP status of the last response, 0 if there was none
+	ResponseStatus int        `json:"responseStatus" db:"response_status"`
+	Error          string     `json:"error" db:"error"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt" db:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"deliveredAt" db:"delivered_at"`
+}
+
+// Creates an enabled webhook with a random secret
+func NewWebhook(rawUrl string, events []string) (*Webhook, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	wh := &Webhook{
+		Id:        id.String(),
+		Url:       rawUrl,
+		Secret:    hex.EncodeToString(secret),
+		Events:    events,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := wh.validate(); err != nil {
+		return nil, err
+	}
+
+	return wh, nil
+}
+
+func (wh *Webhook) validate() error {
+	wh.Url = strings.TrimSpace(wh.Url)
+	u, err := url.Parse(wh.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q:\n\t%w", wh.Url, ErrInvalidWebhookUrl)
+	}
+
+	if len(wh.Events) == 0 {
+		return ErrNoWebhookEvents
+	}
+	for _, ev := range wh.Events {
+		if !isWebhookEvent(ev) {
+			return fmt.Errorf("%q:\n\t%w", ev, ErrInvalidWebhookEvent)
+		}
+	}
+
+	return nil
+}
+
+func isWebhookEvent(event string) bool {
+	for _, ev := range AllWebhookEvents {
+		if ev == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func InsertWebhook(ctx context.Context, appDb *sql.DB, wh *Webhook) error {
+	if err := wh.validate(); err != nil {
+		return err
+	}
+
+	eventsJson, err := json.Marshal(wh.Events)
+	if err != nil {
+		return err
+	}
+
+	_, err = appDb.ExecContext(
+		ctx,
+		`INSERT INTO webhooks (id, url, description, secret, events, enabled, created_at, updated_at)
+		VALUES (:Id, :Url, :Description, :Secret, :Events, :Enabled, :CreatedAt, :UpdatedAt)`,
+		sql.Named("Id", wh.Id),
+		sql.Named("Url", wh.Url),
+		sql.Named("Description", wh.Description),
+		sql.Named("Secret", wh.Secret),
+		sql.Named("Events", string(eventsJson)),
+		sql.Named("Enabled", wh.Enabled),
+		sql.Named("CreatedAt", wh.CreatedAt.Unix()),
+		sql.Named("UpdatedAt", wh.UpdatedAt.Unix()),
+	)
+
+	return err
+}
+
+// Updates the url, description, events and enabled flag of the webhook.
+// The secret is kept
+func UpdateWebhookById(ctx context.Context, appDb *sql.DB, id string, wh *Webhook) error {
+	if err := wh.validate(); err != nil {
+		return err
+	}
+
+	eventsJson, err := json.Marshal(wh.Events)
+	if err != nil {
+		return err
+	}
+
+	_, err = appDb.ExecContext(
+		ctx,
+		`UPDATE webhooks
+		SET url = :Url, description = :Description, events = :Events, enabled = :Enabled, updated_at = unixepoch()
+		WHERE id = :Id`,
+		sql.Named("Url", wh.Url),
+		sql.Named("Description", wh.Description),
+		sql.Named("Events", string(eventsJson)),
+		sql.Named("Enabled", wh.Enabled),
+		sql.Named("Id", id),
+	)
+
+	return err
+}
+
+// Deletes the webhook along with its delivery log
+func DeleteWebhookById(ctx context.Context, appDb *sql.DB, id string) error {
+	_, err := appDb.ExecContext(ctx, "DELETE FROM webhooks WHERE id = :Id", sql.Named("Id", id))
+
+	return err
+}
+
+func FindWebhookById(ctx context.Context, appDb *sql.DB, id string) (*Webhook, error) {
+	webhooks, err := findWebhooks(ctx, appDb, "WHERE id = :Id", sql.Named("Id", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return webhooks[0], nil
+}
+
+func FindAllWebhooks(ctx context.Context, appDb *sql.DB) ([]*Webhook, error) {
+	return findWebhooks(ctx, appDb, "")
+}
+
+// Enabled webhooks subscribed to the event
+func FindWebhooksForEvent(ctx context.Context, appDb *sql.DB, event string) ([]*Webhook, error) {
+	return findWebhooks(
+		ctx,
+		appDb,
+		"WHERE enabled = 1 AND EXISTS (SELECT 1 FROM json_each(webhooks.events) WHERE json_each.value = :Event)",
+		sql.Named("Event", event),
+	)
+}
+
+func findWebhooks(ctx context.Context, appDb *sql.DB, where string, args ...interface{}) ([]*Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT id, url, description, secret, events, enabled, created_at, updated_at
+			FROM webhooks %s
+			ORDER BY created_at, id`,
+			where,
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*Webhook{}
+	for rows.Next() {
+		var (
+			wh         = &Webhook{}
+			eventsJson string
+			createdAt  int64
+			updatedAt  int64
+		)
+		err := rows.Scan(&wh.Id, &wh.Url, &wh.Description, &wh.Secret, &eventsJson, &wh.Enabled, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(eventsJson), &wh.Events); err != nil {
+			return nil, fmt.Errorf("webhook %q has invalid events: %w", wh.Url, err)
+		}
+
+		wh.CreatedAt = time.Unix(createdAt, 0)
+		wh.UpdatedAt = time.Unix(updatedAt, 0)
+		webhooks = append(webhooks, wh)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func NewWebhookDelivery(webhookId, event, payload string) (*WebhookDelivery, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n\t%w", ErrGenUUID, err)
+	}
+
+	return &WebhookDelivery{
+		Id:        id.String(),
+		WebhookId: webhookId,
+		Event:     event,
+		Payload:   payload,
+		Status:    WebhookDeliveryPending,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func InsertWebhookDelivery(ctx context.Context, appDb *sql.DB, d *WebhookDelivery) error {
+	_, err := appDb.ExecContext(
+		ctx,
+		`INSERT INTO webhook_deliveries (id, webhook_id, event, payload, status, attempts, response_status, error, created_at, next_attempt_at, delivered_at)
+		VALUES (:Id, :WebhookId, :Event, :Payload, :Status, :Attempts, :ResponseStatus, :Error, :CreatedAt, :NextAttemptAt, :DeliveredAt)`,
+		sql.Named("Id", d.Id),
+		sql.Named("WebhookId", d.WebhookId),
+		sql.Named("Event", d.Event),
+		sql.Named("Payload", d.Payload),
+		sql.Named("Status", d.Status),
+		sql.Named("Attempts", d.Attempts),
+		sql.Named("ResponseStatus", d.ResponseStatus),
+		sql.Named("Error", d.Error),
+		sql.Named("CreatedAt", d.CreatedAt.Unix()),
+		sql.Named("NextAttemptAt", timeToNullUnix(d.NextAttemptAt)),
+		sql.Named("DeliveredAt", timeToNullUnix(d.DeliveredAt)),
+	)
+
+	return err
+}
+
+// Records the outcome of the latest attempt of the delivery
+func UpdateWebhookDelivery(ctx context.Context, appDb *sql.DB, d *WebhookDelivery) error {
+	_, err := appDb.ExecContext(
+		ctx,
+		`UPDATE webhook_deliveries
+		SET status = :Status,
+			attempts = :Attempts,
+			response_status = :ResponseStatus,
+			error = :Error,
+			next_attempt_at = :NextAttemptAt,
+			delivered_at = :DeliveredAt
+		WHERE id = :Id`,
+		sql.Named("Status", d.Status),
+		sql.Named("Attempts", d.Attempts),
+		sql.Named("ResponseStatus", d.ResponseStatus),
+		sql.Named("Error", d.Error),
+		sql.Named("NextAttemptAt", timeToNullUnix(d.NextAttemptAt)),
+		sql.Named("DeliveredAt", timeToNullUnix(d.DeliveredAt)),
+		sql.Named("Id", d.Id),
+	)
+
+	return err
+}
+
+// Returns the latest deliveries of the webhook, newest first.
+// An empty webhookId returns the deliveries of every webhook
+func FindWebhookDeliveries(ctx context.Context, appDb *sql.DB, webhookId string, limit int) ([]*WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+
+	where := ""
+	args := []interface{}{sql.Named("limit", limit)}
+	if webhookId != "" {
+		where = "WHERE webhook_id = :WebhookId"
+		args = append(args, sql.Named("WebhookId", webhookId))
+	}
+
+	return findWebhookDeliveries(ctx, appDb, where+" ORDER BY created_at DESC, id DESC LIMIT :limit", args...)
+}
+
+// Deliveries still to be sent, oldest first
+func FindPendingWebhookDeliveries(ctx context.Context, appDb *sql.DB) ([]*WebhookDelivery, error) {
+	return findWebhookDeliveries(
+		ctx,
+		appDb,
+		"WHERE status = :Status ORDER BY created_at, id",
+		sql.Named("Status", WebhookDeliveryPending),
+	)
+}
+
+func findWebhookDeliveries(ctx context.Context, appDb *sql.DB, rest string, args ...interface{}) ([]*WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := appDb.QueryContext(
+		ctx,
+		`SELECT id, webhook_id, event, payload, status, attempts, response_status, error, created_at, next_attempt_at, delivered_at
+		FROM webhook_deliveries `+rest,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*WebhookDelivery{}
+	for rows.Next() {
+		var (
+			d             = &WebhookDelivery{}
+			createdAt     int64
+			nextAttemptAt sql.NullInt64
+			deliveredAt   sql.NullInt64
+		)
+		err := rows.Scan(
+			&d.Id,
+			&d.WebhookId,
+			&d.Event,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.ResponseStatus,
+			&d.Error,
+			&createdAt,
+			&nextAttemptAt,
+			&deliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		d.CreatedAt = time.Unix(createdAt, 0)
+		d.NextAttemptAt = nullUnixToTime(nextAttemptAt)
+		d.DeliveredAt = nullUnixToTime(deliveredAt)
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
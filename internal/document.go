@@ -0,0 +1,27 @@
+package internal
+
+import "time"
+
+// Format of the documents courts publish their lists in
+type DocFormat string
+
+const (
+	DocFormatPdf  DocFormat = "pdf"
+	DocFormatHtml DocFormat = "html"
+	DocFormatText DocFormat = "text"
+)
+
+var AllDocFormats = []DocFormat{DocFormatPdf, DocFormatHtml, DocFormatText}
+
+// Document published by a court for a date and case type
+type Document struct {
+	Format DocFormat
+	Data   []byte
+
+	// Metadata of the fetch, empty when unknown
+	Url         string
+	ContentType string
+	Date        time.Time
+	CaseType    CaseType
+	FetchedAt   time.Time
+}
@@ -0,0 +1,45 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// Writes one row per case. When accords are included each case takes one
+// row per accord, with the case columns repeated
+func writeCSV(w io.Writer, cases []*Case, includeAccords bool) error {
+	cw := csv.NewWriter(w)
+
+	headers := caseHeaders
+	if includeAccords {
+		headers = append(append([]string{}, caseHeaders...), "accord_date", "accord_content")
+	}
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+
+	for _, c := range cases {
+		row := c.row()
+		if !includeAccords {
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(c.Accords) == 0 {
+			if err := cw.Write(append(row, "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, a := range c.Accords {
+			if err := cw.Write(append(row[:len(row):len(row)], a.Date, a.Content)); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,197 @@
+// Package export writes sets of cases, and optionally their full accord
+// history, as CSV, XLSX or JSON
+package export
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatJSON Format = "json"
+)
+
+var AllFormats = []Format{FormatCSV, FormatXLSX, FormatJSON}
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+const (
+	dateLayout = "2006-01-02"
+	// Cases whose accords are loaded per query
+	accordsBatchSize = 500
+)
+
+// Column headers, shared with the importer so exported files can be
+// imported back
+var (
+	caseHeaders   = []string{"case_id", "case_type", "region", "case_year", "case_no", "alias", "nature", "other_ids", "tags", "archived_at"}
+	accordHeaders = []string{"case_id", "case_type", "region", "date", "content"}
+)
+
+type Options struct {
+	Format Format
+	// Whether the full accord history of each case is exported
+	IncludeAccords bool
+}
+
+// A case as written to an export
+type Case struct {
+	CaseId     string    `json:"case_id"`
+	CaseType   string    `json:"case_type"`
+	Region     string    `json:"region"`
+	CaseYear   string    `json:"case_year,omitempty"`
+	CaseNo     string    `json:"case_no,omitempty"`
+	Alias      string    `json:"alias,omitempty"`
+	Nature     string    `json:"nature,omitempty"`
+	OtherIds   []string  `json:"other_ids,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	ArchivedAt string    `json:"archived_at,omitempty"`
+	Accords    []*Accord `json:"accords,omitempty"`
+}
+
+type Accord struct {
+	Date    string `json:"date"`
+	Content string `json:"content"`
+}
+
+// Parses a format name, ignoring case and a leading dot
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "."))
+	for _, known := range AllFormats {
+		if f == known {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("%q:\n\t%w", name, ErrUnsupportedFormat)
+}
+
+// Loads every case matching findOpts and writes them to w.
+//
+// A nil findOpts exports every tracked case
+func Export(ctx context.Context, appDb *sql.DB, w io.Writer, findOpts *db.FindCaseOptions, opts *Options) error {
+	cases, err := Load(ctx, appDb, findOpts, opts.IncludeAccords)
+	if err != nil {
+		return err
+	}
+
+	return Write(w, cases, opts)
+}
+
+// Loads the cases matching findOpts in their export representation
+func Load(ctx context.Context, appDb *sql.DB, findOpts *db.FindCaseOptions, includeAccords bool) ([]*Case, error) {
+	fOpts := db.DefaultFindCaseOptions
+	if findOpts != nil {
+		fOpts = *findOpts
+	}
+	// Every matching case is exported, with its history loaded separately
+	fOpts.Limit = 0
+	fOpts.Cursor = ""
+	fOpts.IncludeAccords = false
+
+	lexCases, err := db.FindFilteredCases(ctx, appDb, &fOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]*Case, 0, len(lexCases))
+	byId := make(map[string]*Case, len(lexCases))
+	ids := make([]string, 0, len(lexCases))
+	for _, lc := range lexCases {
+		c := fromLexCase(lc)
+		cases = append(cases, c)
+		byId[lc.Id] = c
+		ids = append(ids, lc.Id)
+	}
+
+	if !includeAccords {
+		return cases, nil
+	}
+
+	for start := 0; start < len(ids); start += accordsBatchSize {
+		end := min(start+accordsBatchSize, len(ids))
+		accords, err := db.FindAccordsForCases(ctx, appDb, ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ca := range accords {
+			c, ok := byId[ca.Accord.ForCase]
+			if !ok {
+				continue
+			}
+			c.Accords = append(c.Accords, &Accord{
+				Date:    ca.Accord.Date.Format(dateLayout),
+				Content: ca.Accord.Content,
+			})
+		}
+	}
+
+	return cases, nil
+}
+
+// Writes the cases to w in the format of opts
+func Write(w io.Writer, cases []*Case, opts *Options) error {
+	switch opts.Format {
+	case FormatCSV:
+		return writeCSV(w, cases, opts.IncludeAccords)
+	case FormatXLSX:
+		return writeXLSX(w, cases, opts.IncludeAccords)
+	case FormatJSON:
+		return writeJSON(w, cases)
+	}
+
+	return fmt.Errorf("%q:\n\t%w", opts.Format, ErrUnsupportedFormat)
+}
+
+// Default file name for an export made at t
+func FileName(format Format, t time.Time) string {
+	return fmt.Sprintf("casos_%s.%s", t.Format("20060102_150405"), format)
+}
+
+func fromLexCase(lc *db.LexCase) *Case {
+	c := &Case{
+		CaseId:   lc.CaseId,
+		CaseType: lc.CaseType,
+		Region:   lc.Region,
+		CaseYear: lc.CaseYear,
+		CaseNo:   lc.CaseNo,
+		Alias:    lc.Alias,
+		Nature:   lc.Nature,
+		OtherIds: lc.OtherIds,
+	}
+	for _, t := range lc.Tags {
+		c.Tags = append(c.Tags, t.Name)
+	}
+	if lc.ArchivedAt != nil {
+		c.ArchivedAt = lc.ArchivedAt.Format(dateLayout)
+	}
+
+	return c
+}
+
+func (c *Case) row() []string {
+	return []string{
+		c.CaseId,
+		c.CaseType,
+		c.Region,
+		c.CaseYear,
+		c.CaseNo,
+		c.Alias,
+		c.Nature,
+		strings.Join(c.OtherIds, ","),
+		strings.Join(c.Tags, ","),
+		c.ArchivedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+var testCases = []*Case{
+	{
+		CaseId:   "12/2024",
+		CaseType: "fam2",
+		Region:   "MX_DGO_DGO",
+		Alias:    "Pérez, Juan",
+		Tags:     []string{"urgente", "vip"},
+		Accords: []*Accord{
+			{Date: "2024-03-01", Content: "Se admite la demanda"},
+			{Date: "2024-04-12", Content: "Se fija fecha de audiencia"},
+		},
+	},
+	{CaseId: "13/2023", CaseType: "civ2"},
+}
+
+func TestWriteCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := Write(buf, testCases, &Options{Format: FormatCSV, IncludeAccords: true}); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a header and 3 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	expect := `12/2024,fam2,MX_DGO_DGO,,,"Pérez, Juan",,,"urgente,vip",,2024-04-12,Se fija fecha de audiencia`
+	if lines[2] != expect {
+		t.Errorf("Expected row\n  %s\ngot\n  %s", expect, lines[2])
+	}
+	if !strings.HasSuffix(lines[3], ",,") {
+		t.Errorf("Expected empty accord columns for a case without accords, got %s", lines[3])
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := Write(buf, testCases, &Options{Format: FormatXLSX, IncludeAccords: true}); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("output is not a zip file:\n  %v", err)
+	}
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Expected part %s in workbook", name)
+		}
+	}
+	if !strings.Contains(files["xl/worksheets/sheet2.xml"], `<c r="E3" t="inlineStr"><is><t xml:space="preserve">Se fija fecha de audiencia</t></is></c>`) {
+		t.Errorf("Expected accord content in sheet2, got\n%s", files["xl/worksheets/sheet2.xml"])
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, expect := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != expect {
+			t.Errorf("columnName(%d) should be %s, got %s", i, expect, got)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Writes the cases as an array using the same keys read by the importer
+func writeJSON(w io.Writer, cases []*Case) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(cases)
+}
@@ -0,0 +1,162 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Longest text a spreadsheet cell can hold
+const xlsxMaxCellLength = 32767
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+%s</Types>`
+	xlsxSheetContentType = `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>%s</sheets>
+</workbook>`
+	xlsxWorkbookSheet = `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`
+	xlsxWorkbookRels  = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+%s</Relationships>`
+	xlsxWorkbookSheetRel = `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>
+`
+)
+
+type xlsxSheet struct {
+	name string
+	rows [][]string
+}
+
+// Writes a workbook with a sheet for the cases and, when included,
+// another one for their accords.
+//
+// Every cell is written as an inline string so no shared strings
+// or styles parts are needed
+func writeXLSX(w io.Writer, cases []*Case, includeAccords bool) error {
+	sheets := []*xlsxSheet{{name: "Casos", rows: [][]string{caseHeaders}}}
+	for _, c := range cases {
+		sheets[0].rows = append(sheets[0].rows, c.row())
+	}
+
+	if includeAccords {
+		accords := &xlsxSheet{name: "Acuerdos", rows: [][]string{accordHeaders}}
+		for _, c := range cases {
+			for _, a := range c.Accords {
+				accords.rows = append(accords.rows, []string{c.CaseId, c.CaseType, c.Region, a.Date, a.Content})
+			}
+		}
+		sheets = append(sheets, accords)
+	}
+
+	return writeWorkbook(w, sheets)
+}
+
+func writeWorkbook(w io.Writer, sheets []*xlsxSheet) error {
+	zw := zip.NewWriter(w)
+
+	var contentTypes, wbSheets, wbRels strings.Builder
+	for i, sh := range sheets {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, xlsxSheetContentType, n)
+		fmt.Fprintf(&wbSheets, xlsxWorkbookSheet, xmlEscape(sh.name), n, n)
+		fmt.Fprintf(&wbRels, xlsxWorkbookSheetRel, n, n)
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, contentTypes.String())},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, wbSheets.String())},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(xlsxWorkbookRels, wbRels.String())},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, p.content); err != nil {
+			return err
+		}
+	}
+
+	for i, sh := range sheets {
+		f, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeSheet(f, sh); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeSheet(w io.Writer, sh *xlsxSheet) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for r, row := range sh.rows {
+		fmt.Fprintf(bw, `<row r="%d">`, r+1)
+		for c, value := range row {
+			if value == "" {
+				continue
+			}
+			if runes := []rune(value); len(runes) > xlsxMaxCellLength {
+				value = string(runes[:xlsxMaxCellLength])
+			}
+			fmt.Fprintf(
+				bw,
+				`<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+				columnName(c),
+				r+1,
+				xmlEscape(value),
+			)
+		}
+		bw.WriteString(`</row>`)
+	}
+
+	bw.WriteString(`</sheetData></worksheet>`)
+	return bw.Flush()
+}
+
+// Converts a zero based column index into its letters: 0 -> A, 26 -> AA
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+
+	return name
+}
+
+// Escapes s for use as XML text, dropping the characters XML can't hold
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || r >= 0x20 && r != 0xFFFE && r != 0xFFFF {
+			return r
+		}
+		return -1
+	}, s)))
+
+	return sb.String()
+}
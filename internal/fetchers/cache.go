@@ -0,0 +1,101 @@
+package fetchers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+// Name of the document cache directory inside the app data dir
+const DocCacheDirName = "cache"
+
+// Stores the documents of past dates on disk. Courts don't change a
+// bulletin once its day is over, so cached documents never expire
+type DocCache struct {
+	dir string
+}
+
+func NewDocCache(dir string) *DocCache {
+	return &DocCache{dir}
+}
+
+// Cache in the app data dir
+func DefaultDocCache() (*DocCache, error) {
+	dir, err := internal.GetAppDataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDocCache(filepath.Join(dir, DocCacheDirName)), nil
+}
+
+func (c *DocCache) path(region internal.Region, date time.Time, caseType internal.CaseType) string {
+	return filepath.Join(c.dir, string(region), string(caseType), date.Format("2006-01-02")+".json")
+}
+
+// Returns the cached document, or nil if there is none
+func (c *DocCache) Get(region internal.Region, date time.Time, caseType internal.CaseType) (*internal.Document, error) {
+	data, err := os.ReadFile(c.path(region, date, caseType))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &internal.Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("cached document for %s on %s:\n\t%w", caseType, date.Format("2006-01-02"), err)
+	}
+
+	return doc, nil
+}
+
+func (c *DocCache) Put(region internal.Region, doc *internal.Document) error {
+	path := c.path(region, doc.Date, doc.CaseType)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Returns a DocFetcher that looks for the documents in the cache
+// before fetching them. Only documents of past dates are cached, as
+// today's bulletin may still grow
+func Cached(fetch DocFetcher, cache *DocCache, region internal.Region) DocFetcher {
+	return func(date time.Time, caseType internal.CaseType) (*internal.Document, error) {
+		doc, err := cache.Get(region, date, caseType)
+		if err != nil {
+			fmt.Printf("DocCache Err: %v\n", err)
+		}
+		if doc != nil {
+			return doc, nil
+		}
+
+		doc, err = fetch(date, caseType)
+		if err != nil {
+			return nil, err
+		}
+
+		y, m, d := time.Now().Date()
+		if date.Before(time.Date(y, m, d, 0, 0, 0, 0, time.Local)) {
+			if err := cache.Put(region, doc); err != nil {
+				fmt.Printf("DocCache Err: %v\n", err)
+			}
+		}
+
+		return doc, nil
+	}
+}
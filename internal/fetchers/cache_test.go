@@ -0,0 +1,89 @@
+package fetchers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+func TestDocCacheGetPut(t *testing.T) {
+	cache := NewDocCache(t.TempDir())
+	date := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.Local)
+
+	doc, err := cache.Get(internal.RegionDefault, date, internal.CaseTypeFam2)
+	if err != nil || doc != nil {
+		t.Fatalf("Expected no document in an empty cache, got %+v, %v", doc, err)
+	}
+
+	put := &internal.Document{
+		Format:   internal.DocFormatPdf,
+		Data:     []byte("%PDF-1.4"),
+		Url:      "https://example.com/fam2.pdf",
+		Date:     date,
+		CaseType: internal.CaseTypeFam2,
+	}
+	if err := cache.Put(internal.RegionDefault, put); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	doc, err = cache.Get(internal.RegionDefault, date, internal.CaseTypeFam2)
+	if err != nil || doc == nil {
+		t.Fatalf("Expected the cached document, got %+v, %v", doc, err)
+	}
+	if doc.Format != put.Format || string(doc.Data) != string(put.Data) || doc.Url != put.Url || !doc.Date.Equal(date) {
+		t.Errorf("Expected the document as it was put, got %+v", doc)
+	}
+
+	// Documents are kept per region, case type and date
+	for _, miss := range []struct {
+		region   internal.Region
+		caseType internal.CaseType
+		date     time.Time
+	}{
+		{"MX_TST_TST", internal.CaseTypeFam2, date},
+		{internal.RegionDefault, internal.CaseTypeFam3, date},
+		{internal.RegionDefault, internal.CaseTypeFam2, date.AddDate(0, 0, 1)},
+	} {
+		if doc, _ := cache.Get(miss.region, miss.date, miss.caseType); doc != nil {
+			t.Errorf("Expected no document for %+v, got %+v", miss, doc)
+		}
+	}
+}
+
+func TestCachedOnlyStoresPastDates(t *testing.T) {
+	cache := NewDocCache(t.TempDir())
+	fetches := 0
+	fetch := func(date time.Time, caseType internal.CaseType) (*internal.Document, error) {
+		fetches++
+		return &internal.Document{Format: internal.DocFormatText, Data: []byte("lista"), Date: date, CaseType: caseType}, nil
+	}
+	cached := Cached(fetch, cache, internal.RegionDefault)
+
+	y, m, d := time.Now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	yesterday := today.AddDate(0, 0, -1)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cached(yesterday, internal.CaseTypeFam2); err != nil {
+			t.Fatalf("errored with\n  %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected a past date to be fetched once and then read from the cache, got %d fetches", fetches)
+	}
+
+	// Today's bulletin may still grow
+	fetches = 0
+	for i := 0; i < 2; i++ {
+		if _, err := cached(today, internal.CaseTypeFam2); err != nil {
+			t.Fatalf("errored with\n  %v", err)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("Expected today to be fetched every time, got %d fetches", fetches)
+	}
+	if doc, _ := cache.Get(internal.RegionDefault, today, internal.CaseTypeFam2); doc != nil {
+		t.Errorf("Expected today's document not to be cached, got %+v", doc)
+	}
+}
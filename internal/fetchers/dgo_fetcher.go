@@ -38,25 +38,38 @@ func dgoFetchResource(date time.Time, caseType internal.CaseType) (data *[]byte,
 	formattedDate := date.Format("212006")
 	resourceUrl := fmt.Sprintf(DGO_URLF, formattedDate, caseType)
 
+	return fetchResource(resourceUrl)
+}
+
+// Downloads the document at the url. Responses outside of the
+// 2xx-3xx range are reported as ErrDocNotFound
+func fetchResource(resourceUrl string) (data *[]byte, err error) {
+	data, _, err = fetchResponse(resourceUrl)
+
+	return data, err
+}
+
+// Same as fetchResource but also returns the Content-Type of the response
+func fetchResponse(resourceUrl string) (data *[]byte, contentType string, err error) {
 	response, err := http.Get(resourceUrl)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	defer response.Body.Close()
 
 	if response.StatusCode < 200 || response.StatusCode >= 400 {
-		return nil, ErrDocNotFound
+		return nil, "", ErrDocNotFound
 	}
 
 	data = new([]byte)
 	*data, err = io.ReadAll(response.Body)
 
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return data, nil
+	return data, response.Header.Get("Content-Type"), nil
 }
 
 func PDFToData(data *[]byte) error {
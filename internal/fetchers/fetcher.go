@@ -8,6 +8,27 @@ import (
 
 type Fetcher func(time.Time, internal.CaseType) (*[]byte, error)
 
+// Fetches the document a source published for the date and case type
+type DocFetcher func(time.Time, internal.CaseType) (*internal.Document, error)
+
+// Adapts a Fetcher returning data in the given format
+func FromFetcher(fetch Fetcher, format internal.DocFormat) DocFetcher {
+	return func(date time.Time, caseType internal.CaseType) (*internal.Document, error) {
+		data, err := fetch(date, caseType)
+		if err != nil {
+			return nil, err
+		}
+
+		return &internal.Document{
+			Format:    format,
+			Data:      *data,
+			Date:      date,
+			CaseType:  caseType,
+			FetchedAt: time.Now(),
+		}, nil
+	}
+}
+
 func NewFetcher(region internal.Region) Fetcher {
 	switch region {
 	case internal.RegionDgo:
@@ -0,0 +1,45 @@
+package fetchers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+// Default time between the requests made to a court during long
+// searches, so they don't flood its site
+const DefaultFetchInterval = 2 * time.Second
+
+// Spaces calls to Wait at least interval apart. Safe for concurrent use
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func NewRateLimiter(interval time.Duration) *RateLimiter {
+	return &RateLimiter{interval: interval}
+}
+
+// Blocks until the next call is allowed
+func (l *RateLimiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	wait := l.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	l.next = now.Add(wait + l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(wait)
+}
+
+// Returns a DocFetcher that waits for the limiter before every fetch
+func Limited(fetch DocFetcher, limiter *RateLimiter) DocFetcher {
+	return func(date time.Time, caseType internal.CaseType) (*internal.Document, error) {
+		limiter.Wait()
+		return fetch(date, caseType)
+	}
+}
@@ -0,0 +1,55 @@
+package fetchers
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+func TestLimitedSpacesFetches(t *testing.T) {
+	const interval = 20 * time.Millisecond
+
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	fetch := Limited(func(date time.Time, caseType internal.CaseType) (*internal.Document, error) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		return &internal.Document{}, nil
+	}, NewRateLimiter(interval))
+
+	// Concurrent fetches share the limiter too. Each one waits for the
+	// previous, so the last can't start before the others' intervals
+	const fetches = 4
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for i := 0; i < fetches; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetch(time.Now(), internal.CaseTypeFam2)
+		}()
+	}
+	wg.Wait()
+
+	if len(times) != fetches {
+		t.Fatalf("Expected %d fetches, got %d", fetches, len(times))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	if elapsed := times[fetches-1].Sub(start); elapsed < (fetches-1)*interval {
+		t.Errorf("Expected the last fetch at least %s after the fetches started, got %s", (fetches-1)*interval, elapsed)
+	}
+
+	// The first fetch after a long pause isn't delayed
+	time.Sleep(interval)
+	start = time.Now()
+	fetch(time.Now(), internal.CaseTypeFam2)
+	if waited := time.Since(start); waited > interval/2 {
+		t.Errorf("Expected no wait after the interval passed, waited %s", waited)
+	}
+}
@@ -0,0 +1,76 @@
+package fetchers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+const (
+	UrlDatePlaceholder     = "{date}"
+	UrlCaseTypePlaceholder = "{caseType}"
+)
+
+var (
+	ErrUnknownDocFormat = errors.New("unknown document format")
+	ErrInvalidTemplate  = errors.New("url template must contain {date} and {caseType}")
+)
+
+// Describes where a source publishes its documents
+type TemplateSource struct {
+	// Url of the documents, e.g. "http://example.com/{date}/{caseType}.pdf"
+	UrlTemplate string
+	// Go time layout for the {date} placeholder
+	DateLayout string
+	Format     internal.DocFormat
+	// Replaces the case type in the url when the source
+	// uses a different name for it
+	Slugs map[internal.CaseType]string
+}
+
+// Returns a DocFetcher that builds the document url from the template.
+// Documents are returned as published, readers convert them if needed
+func NewTemplateFetcher(src TemplateSource) (DocFetcher, error) {
+	if !strings.Contains(src.UrlTemplate, UrlDatePlaceholder) ||
+		!strings.Contains(src.UrlTemplate, UrlCaseTypePlaceholder) {
+		return nil, ErrInvalidTemplate
+	}
+	if !slices.Contains(internal.AllDocFormats, src.Format) {
+		return nil, fmt.Errorf("%q:\n\t%w", src.Format, ErrUnknownDocFormat)
+	}
+
+	return func(date time.Time, caseType internal.CaseType) (*internal.Document, error) {
+		url := src.Url(date, caseType)
+		data, contentType, err := fetchResponse(url)
+		if err != nil {
+			return nil, fmt.Errorf("Fetch file err: %w", err)
+		}
+
+		return &internal.Document{
+			Format:      src.Format,
+			Data:        *data,
+			Url:         url,
+			ContentType: contentType,
+			Date:        date,
+			CaseType:    caseType,
+			FetchedAt:   time.Now(),
+		}, nil
+	}, nil
+}
+
+// Url of the document for the date and case type
+func (src TemplateSource) Url(date time.Time, caseType internal.CaseType) string {
+	slug := string(caseType)
+	if s, ok := src.Slugs[caseType]; ok && s != "" {
+		slug = s
+	}
+
+	return strings.NewReplacer(
+		UrlDatePlaceholder, date.Format(src.DateLayout),
+		UrlCaseTypePlaceholder, slug,
+	).Replace(src.UrlTemplate)
+}
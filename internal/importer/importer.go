@@ -0,0 +1,311 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/regions"
+	"github.com/vladwithcode/lex_app/internal/webhooks"
+)
+
+var ErrImportRolledBack = errors.New("some cases failed to import, no changes were saved")
+
+type RowStatus string
+
+// In a dry run the statuses describe what the import would do
+const (
+	RowCreated RowStatus = "created"
+	RowUpdated RowStatus = "updated"
+	// The case already exists and Upsert was not set
+	RowSkipped RowStatus = "skipped"
+	RowInvalid RowStatus = "invalid"
+	RowFailed  RowStatus = "failed"
+)
+
+type Options struct {
+	// Only reads the database, reporting what the import would do
+	DryRun bool
+	// Updates the alias and tags of the cases that already exist
+	Upsert bool
+	// Called after each case is processed
+	Progress func(done, total int)
+}
+
+type RowResult struct {
+	Line     int       `json:"line"`
+	CaseId   string    `json:"caseId"`
+	CaseType string    `json:"caseType"`
+	Status   RowStatus `json:"status"`
+	Errors   []string  `json:"errors,omitempty"`
+}
+
+// Machine readable outcome of an import
+type Report struct {
+	DryRun  bool `json:"dryRun"`
+	Upsert  bool `json:"upsert"`
+	Total   int  `json:"total"`
+	Created int  `json:"created"`
+	Updated int  `json:"updated"`
+	Skipped int  `json:"skipped"`
+	Invalid int  `json:"invalid"`
+	Failed  int  `json:"failed"`
+	// Set when a failure undid the whole import
+	RolledBack bool         `json:"rolledBack"`
+	Rows       []*RowResult `json:"rows"`
+}
+
+func (r *Report) add(res *RowResult) {
+	r.Rows = append(r.Rows, res)
+	r.Total++
+
+	switch res.Status {
+	case RowCreated:
+		r.Created++
+	case RowUpdated:
+		r.Updated++
+	case RowSkipped:
+		r.Skipped++
+	case RowInvalid:
+		r.Invalid++
+	case RowFailed:
+		r.Failed++
+	}
+}
+
+// Returns every problem found in the case.
+//
+// seen holds the line where each case key was first read
+func validateCase(c *Case, seen map[string]int) []string {
+	problems := []string{}
+
+	if !db.IsValidCaseId(c.CaseId) {
+		problems = append(problems, fmt.Sprintf("invalid case id %q", c.CaseId))
+	}
+	region := string(c.ImportRegion())
+	if err := db.ValidateCaseType(region, c.CaseType); errors.Is(err, regions.ErrUnknownRegion) {
+		problems = append(problems, fmt.Sprintf("unknown region %q", region))
+	} else if err != nil {
+		problems = append(problems, fmt.Sprintf("unknown case type %q", c.CaseType))
+	}
+	for _, otherId := range c.OtherIds {
+		if !db.IsValidCaseId(otherId) {
+			problems = append(problems, fmt.Sprintf("invalid other id %q", otherId))
+		}
+	}
+	for _, a := range c.Accords {
+		if _, err := ParseAccordDate(a.Date); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	key := region + ":" + c.CaseId + ":" + c.CaseType
+	if line, ok := seen[key]; ok {
+		problems = append(problems, fmt.Sprintf("duplicates the case in line %d", line))
+	} else {
+		seen[key] = c.Line
+	}
+
+	return problems
+}
+
+// Validates and imports every case in a single transaction.
+//
+// Invalid cases are left out. If any valid case fails to be written the
+// whole import is rolled back and ErrImportRolledBack is returned along
+// with the report
+func Import(ctx context.Context, appDb *sql.DB, cases []*Case, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	report := &Report{
+		DryRun: opts.DryRun,
+		Upsert: opts.Upsert,
+		Rows:   []*RowResult{},
+	}
+
+	tx, err := appDb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tagIds, err := loadTagIds(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]int{}
+	created := []*db.LexCase{}
+	for i, c := range cases {
+		res, newCase := importCase(ctx, tx, tagIds, seen, c, opts)
+		report.add(res)
+		if newCase != nil {
+			created = append(created, newCase)
+		}
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(cases))
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+	if report.Failed > 0 {
+		report.RolledBack = true
+		return report, ErrImportRolledBack
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, c := range created {
+		if err := webhooks.Emit(ctx, appDb, db.WebhookEventCaseCreated, c); err != nil {
+			fmt.Printf("Webhooks Err: %v\n", err)
+			break
+		}
+	}
+
+	return report, nil
+}
+
+func importCase(
+	ctx context.Context,
+	tx *sql.Tx,
+	tagIds map[string]string,
+	seen map[string]int,
+	c *Case,
+	opts *Options,
+) (res *RowResult, created *db.LexCase) {
+	res = &RowResult{Line: c.Line, CaseId: c.CaseId, CaseType: c.CaseType}
+
+	if problems := validateCase(c, seen); len(problems) > 0 {
+		res.Status = RowInvalid
+		res.Errors = problems
+		return res, nil
+	}
+
+	existing, err := db.FindRegionCase(ctx, tx, string(c.ImportRegion()), c.CaseId+":"+c.CaseType)
+	if errors.Is(err, sql.ErrNoRows) {
+		existing, err = nil, nil
+	}
+	if err != nil {
+		res.Status = RowFailed
+		res.Errors = []string{err.Error()}
+		return res, nil
+	}
+
+	switch {
+	case existing == nil:
+		res.Status = RowCreated
+		if !opts.DryRun {
+			created, err = createCase(ctx, tx, tagIds, c)
+		}
+	case opts.Upsert:
+		res.Status = RowUpdated
+		if !opts.DryRun {
+			err = updateCase(ctx, tx, tagIds, existing, c)
+		}
+	default:
+		res.Status = RowSkipped
+	}
+
+	if err != nil {
+		res.Status = RowFailed
+		res.Errors = []string{err.Error()}
+		return res, nil
+	}
+
+	return res, created
+}
+
+func createCase(ctx context.Context, tx *sql.Tx, tagIds map[string]string, c *Case) (*db.LexCase, error) {
+	newCase, err := db.NewRegionCase(c.CaseId, c.CaseType, string(c.ImportRegion()))
+	if err != nil {
+		return nil, err
+	}
+	newCase.Alias = c.Alias
+	newCase.Nature = c.Nature
+	for _, otherId := range c.OtherIds {
+		if otherId != c.CaseId {
+			newCase.AddOtherId(otherId)
+		}
+	}
+
+	if err := db.InsertCase(ctx, tx, newCase); err != nil {
+		return nil, err
+	}
+
+	for _, a := range c.Accords {
+		date, err := ParseAccordDate(a.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		accord := db.NewAccord(newCase.Id)
+		accord.Content = a.Content
+		accord.Date = date
+		if err := db.InsertAccord(ctx, tx, accord); err != nil {
+			return nil, fmt.Errorf("inserting accord of %s: %w", a.Date, err)
+		}
+	}
+
+	return newCase, tagCase(ctx, tx, tagIds, newCase.Id, c.Tags)
+}
+
+// Updates the alias of an existing case and adds the imported tags to it
+func updateCase(ctx context.Context, tx *sql.Tx, tagIds map[string]string, existing *db.LexCase, c *Case) error {
+	if c.Alias != "" && c.Alias != existing.Alias {
+		if err := db.UpdateCaseById(ctx, tx, existing.Id, &db.LexCase{Alias: c.Alias}); err != nil {
+			return err
+		}
+	}
+
+	return tagCase(ctx, tx, tagIds, existing.Id, c.Tags)
+}
+
+// Maps the lowercased name of every existing tag to its id
+func loadTagIds(ctx context.Context, tx *sql.Tx) (map[string]string, error) {
+	tags, err := db.FindAllTags(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIds := make(map[string]string, len(tags))
+	for _, t := range tags {
+		tagIds[strings.ToLower(t.Name)] = t.Id
+	}
+
+	return tagIds, nil
+}
+
+// Tags the case with every tag in names, creating the missing ones
+func tagCase(ctx context.Context, tx *sql.Tx, tagIds map[string]string, caseId string, names []string) error {
+	ids := []string{}
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" {
+			continue
+		}
+
+		id, ok := tagIds[key]
+		if !ok {
+			tag, err := db.NewTag(name, "")
+			if err != nil {
+				return err
+			}
+			if err := db.InsertTag(ctx, tx, tag); err != nil {
+				return err
+			}
+			id = tag.Id
+			tagIds[key] = id
+		}
+		ids = append(ids, id)
+	}
+
+	return db.AddCaseTags(ctx, tx, caseId, ids)
+}
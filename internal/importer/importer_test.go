@@ -0,0 +1,188 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/export"
+	"github.com/vladwithcode/lex_app/internal/regions"
+	_ "modernc.org/sqlite"
+)
+
+// Opens an in-memory database with every migration applied
+func openTestDb(t *testing.T) *sql.DB {
+	t.Helper()
+
+	appDb, err := sql.Open("sqlite", ":memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	// Every connection to :memory: opens a different database
+	appDb.SetMaxOpenConns(1)
+	t.Cleanup(func() { appDb.Close() })
+
+	goose.SetLogger(goose.NopLogger())
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if err := goose.Up(appDb, "../../data/migrations"); err != nil {
+		t.Fatalf("migrating errored with\n  %v", err)
+	}
+
+	return appDb
+}
+
+func TestExportedJSONImportsBack(t *testing.T) {
+	// West of UTC a date read as UTC midnight falls on the previous local day
+	local := time.Local
+	time.Local = time.FixedZone("CST", -6*60*60)
+	defer func() { time.Local = local }()
+
+	ctx := context.Background()
+	srcDb := openTestDb(t)
+
+	lc, err := db.NewCase("12/2024", "fam2")
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if err := db.InsertCase(ctx, srcDb, lc); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	dates := []time.Time{
+		time.Date(2024, time.March, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2024, time.April, 12, 0, 0, 0, 0, time.Local),
+	}
+	for _, date := range dates {
+		accord := db.NewAccord(lc.Id)
+		accord.Date = date
+		accord.Content = "Acuerdo del " + date.Format("2006-01-02")
+		if err := db.InsertAccord(ctx, srcDb, accord); err != nil {
+			t.Fatalf("errored with\n  %v", err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	err = export.Export(ctx, srcDb, buf, nil, &export.Options{Format: export.FormatJSON, IncludeAccords: true})
+	if err != nil {
+		t.Fatalf("exporting errored with\n  %v", err)
+	}
+	cases, err := readJSONCases(buf)
+	if err != nil {
+		t.Fatalf("reading errored with\n  %v", err)
+	}
+
+	dstDb := openTestDb(t)
+	report, err := Import(ctx, dstDb, cases, nil)
+	if err != nil {
+		t.Fatalf("importing errored with\n  %v", err)
+	}
+	if report.Created != 1 {
+		t.Fatalf("Expected 1 created case, got %+v", report)
+	}
+
+	imported, err := db.FindRegionCase(ctx, dstDb, lc.Region, lc.CaseId+":"+lc.CaseType)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	accords, err := db.FindAccordsForCases(ctx, dstDb, []string{imported.Id})
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if len(accords) != len(dates) {
+		t.Fatalf("Expected %d accords, got %d", len(dates), len(accords))
+	}
+	got := map[int64]bool{}
+	for _, ca := range accords {
+		got[ca.Accord.Date.Unix()] = true
+	}
+	for _, date := range dates {
+		if !got[date.Unix()] {
+			t.Errorf("Expected an accord stored at %s, got %v", date, got)
+		}
+	}
+}
+
+func TestParseAccordDateIsLocal(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("CST", -6*60*60)
+	defer func() { time.Local = local }()
+
+	expect := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.Local)
+	for _, s := range []string{"2024-03-01", "01/03/2024", "45352"} {
+		got, err := ParseAccordDate(s)
+		if err != nil {
+			t.Fatalf("errored with\n  %v", err)
+		}
+		if !got.Equal(expect) {
+			t.Errorf("Expected %q to be parsed as %s, got %s", s, expect, got)
+		}
+	}
+}
+
+func TestExportedRegionsImportBack(t *testing.T) {
+	def, err := regions.Find(internal.RegionDefault)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	err = regions.Register(&regions.Region{
+		Id:        "MX_TST_TST",
+		CaseTypes: []internal.CaseType{internal.CaseTypeFam2},
+		Fetch:     def.Fetch,
+		Reader:    def.Reader,
+	})
+	if err != nil && !errors.Is(err, regions.ErrDuplicatedRegion) {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	ctx := context.Background()
+	srcDb := openTestDb(t)
+	for _, region := range []string{"MX_DGO_DGO", "MX_TST_TST"} {
+		lc, err := db.NewRegionCase("12/2024", "fam2", region)
+		if err != nil {
+			t.Fatalf("errored with\n  %v", err)
+		}
+		if err := db.InsertCase(ctx, srcDb, lc); err != nil {
+			t.Fatalf("errored with\n  %v", err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	err = export.Export(ctx, srcDb, buf, nil, &export.Options{Format: export.FormatJSON})
+	if err != nil {
+		t.Fatalf("exporting errored with\n  %v", err)
+	}
+	cases, err := readJSONCases(buf)
+	if err != nil {
+		t.Fatalf("reading errored with\n  %v", err)
+	}
+
+	dstDb := openTestDb(t)
+	report, err := Import(ctx, dstDb, cases, nil)
+	if err != nil {
+		t.Fatalf("importing errored with\n  %v", err)
+	}
+	if report.Created != 2 {
+		t.Fatalf("Expected the case of each region to be created, got %+v", report)
+	}
+	for _, region := range []string{"MX_DGO_DGO", "MX_TST_TST"} {
+		if _, err := db.FindRegionCase(ctx, dstDb, region, "12/2024:fam2"); err != nil {
+			t.Errorf("Expected 12/2024:fam2 in %s, got %v", region, err)
+		}
+	}
+	if _, err := db.FindCase(ctx, dstDb, "12/2024:fam2"); !errors.Is(err, db.ErrAmbiguousCase) {
+		t.Errorf("Expected a key tracked in two regions to be ambiguous, got %v", err)
+	}
+
+	unknown := []*Case{{Line: 1, CaseId: "13/2024", CaseType: "fam2", Region: "MX_XX_XX"}}
+	report, err = Import(ctx, dstDb, unknown, nil)
+	if err != nil || report.Invalid != 1 {
+		t.Errorf("Expected a case of an unknown region to be invalid, got %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// A case read from the source along with what importing it would do
+type PreviewRow struct {
+	Case   *Case      `json:"case"`
+	Result *RowResult `json:"result"`
+}
+
+// Everything needed to review a source before importing it
+type Preview struct {
+	// Identifies the preview when running it, see Preview.Cases
+	Id     string `json:"id"`
+	Upsert bool   `json:"upsert"`
+	Format string `json:"format"`
+	// Columns found in the source, to be mapped to AllFields
+	Columns []string `json:"columns"`
+	// Sheets of XLSX sources, empty for any other format
+	Sheets []string      `json:"sheets"`
+	Rows   []*PreviewRow `json:"rows"`
+	Report *Report       `json:"report"`
+}
+
+// Reads the source and runs a dry import of it
+func BuildPreview(ctx context.Context, appDb *sql.DB, src *Source, upsert bool) (*Preview, error) {
+	format, err := ResolveFormat(src.Path, src.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Preview{Id: id.String(), Upsert: upsert, Format: format, Sheets: []string{}}
+	if format == "xlsx" {
+		p.Sheets, err = ReadSheets(src.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.Columns, err = ReadColumns(src)
+	if err != nil {
+		return nil, err
+	}
+
+	cases, err := Read(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Report, err = Import(ctx, appDb, cases, &Options{DryRun: true, Upsert: upsert})
+	if err != nil {
+		return nil, err
+	}
+
+	p.Rows = make([]*PreviewRow, len(cases))
+	for i, c := range cases {
+		p.Rows[i] = &PreviewRow{Case: c, Result: p.Report.Rows[i]}
+	}
+
+	return p, nil
+}
+
+// Returns the cases read for the preview, so importing them writes
+// exactly what was reviewed even if the source changed since
+func (p *Preview) Cases() []*Case {
+	cases := make([]*Case, len(p.Rows))
+	for i, row := range p.Rows {
+		cases[i] = row.Case
+	}
+
+	return cases
+}
@@ -0,0 +1,342 @@
+// Package importer reads cases from JSON, CSV and XLSX files, validates
+// them and imports them into the app database
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+// Fields a column of a CSV or XLSX file can be mapped to
+const (
+	FieldCaseId        = "case_id"
+	FieldCaseType      = "case_type"
+	FieldRegion        = "region"
+	FieldAlias         = "alias"
+	FieldNature        = "nature"
+	FieldOtherIds      = "other_ids"
+	FieldTags          = "tags"
+	FieldAccordDate    = "accord_date"
+	FieldAccordContent = "accord_content"
+)
+
+var AllFields = []string{
+	FieldCaseId,
+	FieldCaseType,
+	FieldRegion,
+	FieldAlias,
+	FieldNature,
+	FieldOtherIds,
+	FieldTags,
+	FieldAccordDate,
+	FieldAccordContent,
+}
+
+var (
+	ErrUnknownFormat  = errors.New("unknown input format, should be json, csv or xlsx")
+	ErrMissingColumns = errors.New("the file has no case_id or case_type column")
+	ErrInvalidMapping = errors.New("column mappings should be formatted as 'field=Column,field=Column'")
+)
+
+// Matches the cases written by the JSON export, every field
+// but case_id and case_type is optional. Cases without a region
+// belong to the default one
+type Case struct {
+	// Line of the file, or position in the JSON array, the case was read from
+	Line     int       `json:"-"`
+	CaseId   string    `json:"case_id"`
+	CaseType string    `json:"case_type"`
+	Region   string    `json:"region"`
+	Alias    string    `json:"alias"`
+	Nature   string    `json:"nature"`
+	OtherIds []string  `json:"other_ids"`
+	Tags     []string  `json:"tags"`
+	Accords  []*Accord `json:"accords"`
+}
+
+// Region the case is imported into
+func (c *Case) ImportRegion() internal.Region {
+	if c.Region == "" {
+		return internal.RegionDefault
+	}
+
+	return internal.Region(c.Region)
+}
+
+type Accord struct {
+	Date    string `json:"date"`
+	Content string `json:"content"`
+}
+
+// Name of the column in the file holding each field.
+//
+// Fields without a mapping are read from the column named as the field
+type Mapping map[string]string
+
+// Parses mappings formatted as "case_id=Expediente,case_type=Juzgado"
+func ParseMapping(s string) (Mapping, error) {
+	mapping := Mapping{}
+	if strings.TrimSpace(s) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.TrimSpace(field)
+		if !ok || field == "" || strings.TrimSpace(column) == "" {
+			return nil, fmt.Errorf("%q:\n\t%w", pair, ErrInvalidMapping)
+		}
+		if !isField(field) {
+			return nil, fmt.Errorf("unknown field %q, should be one of %s:\n\t%w", field, strings.Join(AllFields, ", "), ErrInvalidMapping)
+		}
+		mapping[field] = strings.TrimSpace(column)
+	}
+
+	return mapping, nil
+}
+
+func isField(field string) bool {
+	for _, f := range AllFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Infers the format of the file from its extension when format is empty
+func ResolveFormat(path, format string) (string, error) {
+	if format == "" {
+		format = filepath.Ext(path)
+	}
+	format = strings.TrimPrefix(strings.ToLower(format), ".")
+
+	switch format {
+	case "json", "csv", "xlsx":
+		return format, nil
+	}
+
+	return "", fmt.Errorf("%q:\n\t%w", format, ErrUnknownFormat)
+}
+
+// A file to import and how to read it
+type Source struct {
+	Path string `json:"path"`
+	// json, csv or xlsx. Inferred from the extension of Path when empty
+	Format  string  `json:"format"`
+	Mapping Mapping `json:"mapping"`
+	// XLSX sheet to read, the first one when empty
+	Sheet string `json:"sheet"`
+}
+
+// Reads every case in the source
+func Read(src *Source) ([]*Case, error) {
+	format, err := ResolveFormat(src.Path, src.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "json" {
+		f, err := os.Open(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return readJSONCases(f)
+	}
+
+	rows, err := readTable(src.Path, format, src.Sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	return casesFromTable(rows, src.Mapping)
+}
+
+// Returns the column names of a CSV or XLSX source, used to map them to
+// fields. JSON sources always have AllFields as columns
+func ReadColumns(src *Source) ([]string, error) {
+	format, err := ResolveFormat(src.Path, src.Format)
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		return AllFields, nil
+	}
+
+	rows, err := readTable(src.Path, format, src.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []string{}, nil
+	}
+
+	return rows[0], nil
+}
+
+func readTable(path, format, sheet string) ([][]string, error) {
+	if format == "xlsx" {
+		return readXLSX(path, sheet)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	return cr.ReadAll()
+}
+
+func readJSONCases(r io.Reader) ([]*Case, error) {
+	var cases []*Case
+	if err := json.NewDecoder(r).Decode(&cases); err != nil {
+		return nil, err
+	}
+	for i, c := range cases {
+		c.Line = i + 1
+	}
+
+	return cases, nil
+}
+
+// Builds the cases from the rows of a table whose first row holds the
+// column names.
+//
+// Consecutive rows for the same case, like the ones written by the CSV
+// export with accords, are merged into a single case
+func casesFromTable(rows [][]string, mapping Mapping) ([]*Case, error) {
+	if len(rows) == 0 {
+		return []*Case{}, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	index := map[string]int{}
+	for _, field := range AllFields {
+		name := field
+		if mapped, ok := mapping[field]; ok {
+			name = mapped
+		}
+		if i, ok := columns[strings.ToLower(name)]; ok {
+			index[field] = i
+		}
+	}
+	if _, ok := index[FieldCaseId]; !ok {
+		return nil, ErrMissingColumns
+	}
+	if _, ok := index[FieldCaseType]; !ok {
+		return nil, ErrMissingColumns
+	}
+
+	cell := func(row []string, field string) string {
+		i, ok := index[field]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	cases := []*Case{}
+	var last *Case
+	for i, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+
+		c := &Case{
+			Line:     i + 2,
+			CaseId:   cell(row, FieldCaseId),
+			CaseType: strings.ToLower(cell(row, FieldCaseType)),
+			Region:   strings.ToUpper(cell(row, FieldRegion)),
+			Alias:    cell(row, FieldAlias),
+			Nature:   cell(row, FieldNature),
+			OtherIds: splitList(cell(row, FieldOtherIds)),
+			Tags:     splitList(cell(row, FieldTags)),
+		}
+
+		var accord *Accord
+		if date := cell(row, FieldAccordDate); date != "" {
+			accord = &Accord{Date: date, Content: cell(row, FieldAccordContent)}
+		}
+
+		if accord != nil && last != nil && last.CaseId == c.CaseId && last.CaseType == c.CaseType && last.Region == c.Region {
+			last.Accords = append(last.Accords, accord)
+			continue
+		}
+		if accord != nil {
+			c.Accords = []*Accord{accord}
+		}
+
+		cases = append(cases, c)
+		last = c
+	}
+
+	return cases, nil
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func splitList(s string) []string {
+	list := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// Days between the epoch used by spreadsheet date serials and the unix epoch
+const spreadsheetEpochOffset = 25569
+
+var accordDateLayouts = []string{"2006-01-02", "02/01/2006", time.RFC3339}
+
+// Parses accord dates written as 'YYYY-MM-DD', 'DD/MM/YYYY', RFC3339 or as
+// the day serial used by spreadsheets.
+//
+// Dates without a zone are read as local midnight, like the dates the
+// updater stores, so imported accords match the ones found later
+func ParseAccordDate(s string) (time.Time, error) {
+	for _, layout := range accordDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if serial, err := strconv.ParseFloat(s, 64); err == nil && serial > 0 {
+		days := int(serial) - spreadsheetEpochOffset
+		return time.Date(1970, time.January, 1+days, 0, 0, 0, 0, time.Local), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid accord date %q", s)
+}
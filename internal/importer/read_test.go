@@ -0,0 +1,46 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCasesFromTable(t *testing.T) {
+	rows := [][]string{
+		{"Expediente", "Juzgado", "alias", "accord_date", "accord_content"},
+		{"12/2024", "FAM2", "Pérez", "2024-03-01", "Se admite la demanda"},
+		{"12/2024", "fam2", "Pérez", "2024-04-12", "Se fija fecha de audiencia"},
+		{"", "", "", "", ""},
+		{"13/2023", "civ2", "", "", ""},
+	}
+	mapping, err := ParseMapping("case_id=Expediente, case_type=juzgado")
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	cases, err := casesFromTable(rows, mapping)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if len(cases) != 2 {
+		t.Fatalf("Expected 2 cases, got %d", len(cases))
+	}
+	if c := cases[0]; c.CaseType != "fam2" || len(c.Accords) != 2 || c.Line != 2 {
+		t.Errorf("Expected the accord rows merged into line 2 of fam2, got %+v", c)
+	}
+	if c := cases[1]; c.CaseId != "13/2023" || len(c.Accords) != 0 || c.Line != 5 {
+		t.Errorf("Expected 13/2023 without accords in line 5, got %+v", c)
+	}
+
+	if _, err := casesFromTable(rows, nil); !errors.Is(err, ErrMissingColumns) {
+		t.Errorf("Expected ErrMissingColumns without a mapping, got %v", err)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	for _, s := range []string{"case_id", "case_id=", "court=Juzgado"} {
+		if _, err := ParseMapping(s); !errors.Is(err, ErrInvalidMapping) {
+			t.Errorf("Expected ErrInvalidMapping for %q, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var ErrSheetNotFound = errors.New("sheet not found in workbook")
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RId  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// Text of a shared or inline string, either plain or split in rich text runs
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t *xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+
+	var sb strings.Builder
+	for _, r := range t.Runs {
+		sb.WriteString(r.Text)
+	}
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string    `xml:"r,attr"`
+			Type   string    `xml:"t,attr"`
+			Value  string    `xml:"v"`
+			Inline *xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// Returns the names of the sheets in the workbook, in order
+func ReadSheets(filePath string) ([]string, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	wb := &xlsxWorkbook{}
+	if err := decodeZipXML(files, "xl/workbook.xml", wb); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(wb.Sheets))
+	for i, s := range wb.Sheets {
+		names[i] = s.Name
+	}
+
+	return names, nil
+}
+
+// Reads the cells of a sheet of the workbook as text.
+//
+// sheet is the name of the sheet, an empty name reads the first one
+func readXLSX(filePath, sheet string) ([][]string, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	wb := &xlsxWorkbook{}
+	if err := decodeZipXML(files, "xl/workbook.xml", wb); err != nil {
+		return nil, err
+	}
+	rels := &xlsxRelationships{}
+	if err := decodeZipXML(files, "xl/_rels/workbook.xml.rels", rels); err != nil {
+		return nil, err
+	}
+
+	rId := ""
+	for _, s := range wb.Sheets {
+		if sheet == "" || strings.EqualFold(s.Name, sheet) {
+			rId = s.RId
+			break
+		}
+	}
+	sheetPath := ""
+	for _, r := range rels.Relationships {
+		if r.Id == rId {
+			sheetPath = strings.TrimPrefix(r.Target, "/")
+			if !strings.HasPrefix(sheetPath, "xl/") {
+				sheetPath = path.Join("xl", sheetPath)
+			}
+		}
+	}
+	if rId == "" || sheetPath == "" {
+		return nil, fmt.Errorf("%q:\n\t%w", sheet, ErrSheetNotFound)
+	}
+
+	shared := &xlsxSharedStrings{}
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(files, "xl/sharedStrings.xml", shared); err != nil {
+			return nil, err
+		}
+	}
+
+	ws := &xlsxWorksheet{}
+	if err := decodeZipXML(files, sheetPath, ws); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(ws.Rows))
+	for _, r := range ws.Rows {
+		row := []string{}
+		for i, c := range r.Cells {
+			col := i
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+
+			switch c.Type {
+			case "s":
+				var idx int
+				fmt.Sscanf(c.Value, "%d", &idx)
+				if idx >= 0 && idx < len(shared.Items) {
+					row[col] = shared.Items[idx].String()
+				}
+			case "inlineStr":
+				if c.Inline != nil {
+					row[col] = c.Inline.String()
+				}
+			default:
+				row[col] = c.Value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func decodeZipXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("workbook is missing %s", name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// Zero based column of a cell reference: "A1" -> 0, "AB12" -> 27
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+
+	return col - 1
+}
@@ -0,0 +1,109 @@
+package pdf
+
+import "unicode/utf8"
+
+// One of the standard Type1 fonts every PDF reader provides,
+// so no font data has to be embedded
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var fontNames = map[Font]string{
+	Helvetica:     "Helvetica",
+	HelveticaBold: "Helvetica-Bold",
+}
+
+// Glyph widths, in thousandths of the font size, for the printable
+// ASCII characters starting at the space
+var fontWidths = map[Font][95]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// Characters outside of ASCII measured as the one they are based on
+var widthAliases = map[rune]rune{
+	'Á': 'A', 'À': 'A', 'Â': 'A', 'Ä': 'A', 'Ã': 'A', 'Å': 'A',
+	'É': 'E', 'È': 'E', 'Ê': 'E', 'Ë': 'E',
+	'Í': 'I', 'Ì': 'I', 'Î': 'I', 'Ï': 'I',
+	'Ó': 'O', 'Ò': 'O', 'Ô': 'O', 'Ö': 'O', 'Õ': 'O',
+	'Ú': 'U', 'Ù': 'U', 'Û': 'U', 'Ü': 'U',
+	'Ñ': 'N', 'Ç': 'C',
+	'á': 'a', 'à': 'a', 'â': 'a', 'ä': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'ö': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ñ': 'n', 'ç': 'c',
+	'¿': '?', '¡': '!', '«': '<', '»': '>',
+	'‘': '\'', '’': '\'', '“': '"', '”': '"', '–': '-',
+	'\u00a0': ' ',
+}
+
+// Width of s in points when set in font at size
+func StringWidth(font Font, size float64, s string) float64 {
+	widths := fontWidths[font]
+
+	total := 0
+	for _, r := range s {
+		if alias, ok := widthAliases[r]; ok {
+			r = alias
+		}
+		switch {
+		case r >= ' ' && r <= '~':
+			total += widths[r-' ']
+		case r == '—' || r == '…':
+			total += 1000
+		default:
+			total += 556
+		}
+	}
+
+	return float64(total) * size / 1000
+}
+
+// Characters of windows-1252 that are not at the same code point in unicode
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'‰': 0x89, '‹': 0x8B, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99, '›': 0x9B,
+}
+
+// Encodes s as the WinAnsiEncoding used by the standard fonts.
+//
+// Characters it can't represent are replaced by '?'
+func encodeWinAnsi(s string) []byte {
+	out := make([]byte, 0, utf8.RuneCountInString(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= ' ' && r <= '~', r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiSpecials[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+
+	return out
+}
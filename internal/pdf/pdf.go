@@ -0,0 +1,325 @@
+// Package pdf is a minimal PDF writer for text documents.
+//
+// It only supports the standard Helvetica fonts, lines, filled
+// rectangles and JPEG images, which is all the reports need
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"strings"
+)
+
+// Page sizes in points
+const (
+	LetterWidth  = 612.0
+	LetterHeight = 792.0
+)
+
+var ErrNoPage = errors.New("pdf: no page has been added")
+
+type page struct {
+	content bytes.Buffer
+}
+
+// A JPEG image that can be drawn in any page of its document
+type Image struct {
+	Width  int
+	Height int
+	name   string
+	data   []byte
+	// PDF color space matching the color model of the JPEG
+	colorSpace string
+}
+
+type Document struct {
+	width  float64
+	height float64
+	pages  []*page
+	// Index of the page being drawn on
+	current int
+	images  []*Image
+
+	font     Font
+	fontSize float64
+}
+
+func New(width, height float64) *Document {
+	return &Document{
+		width:    width,
+		height:   height,
+		current:  -1,
+		font:     Helvetica,
+		fontSize: 12,
+	}
+}
+
+func (d *Document) Width() float64 {
+	return d.width
+}
+
+func (d *Document) Height() float64 {
+	return d.height
+}
+
+// Adds a blank page and makes it the current one
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &page{})
+	d.current = len(d.pages) - 1
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Makes the page at the zero based index i the current one
+func (d *Document) SetPage(i int) {
+	if i >= 0 && i < len(d.pages) {
+		d.current = i
+	}
+}
+
+func (d *Document) SetFont(font Font, size float64) {
+	d.font = font
+	d.fontSize = size
+}
+
+// Width of s set in the current font
+func (d *Document) StringWidth(s string) float64 {
+	return StringWidth(d.font, d.fontSize, s)
+}
+
+// Writes s with its baseline at y, coordinates are measured in points
+// from the top left corner of the page.
+//
+// r, g and b are the components of the text color, from 0 to 1
+func (d *Document) Text(x, y float64, s string, r, g, b float64) {
+	p := d.page()
+	if p == nil {
+		return
+	}
+
+	fmt.Fprintf(
+		&p.content,
+		"BT %.3f %.3f %.3f rg /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		r, g, b,
+		int(d.font)+1,
+		d.fontSize,
+		x,
+		d.height-y,
+		escapeString(encodeWinAnsi(s)),
+	)
+}
+
+// Strokes a line from (x1, y1) to (x2, y2) with the given width and gray level
+func (d *Document) Line(x1, y1, x2, y2, width, gray float64) {
+	p := d.page()
+	if p == nil {
+		return
+	}
+
+	fmt.Fprintf(
+		&p.content,
+		"%.3f G %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		gray, width, x1, d.height-y1, x2, d.height-y2,
+	)
+}
+
+// Fills a rectangle whose top left corner is at (x, y)
+func (d *Document) Rect(x, y, w, h, r, g, b float64) {
+	p := d.page()
+	if p == nil {
+		return
+	}
+
+	fmt.Fprintf(
+		&p.content,
+		"%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n",
+		r, g, b, x, d.height-y-h, w, h,
+	)
+}
+
+// Registers a JPEG image to be drawn with DrawImage
+func (d *Document) AddJPEG(data []byte) (*Image, error) {
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	colorSpace := "DeviceRGB"
+	switch cfg.ColorModel {
+	case color.GrayModel:
+		colorSpace = "DeviceGray"
+	case color.CMYKModel:
+		colorSpace = "DeviceCMYK"
+	}
+
+	img := &Image{
+		Width:      cfg.Width,
+		Height:     cfg.Height,
+		name:       fmt.Sprintf("Im%d", len(d.images)+1),
+		data:       data,
+		colorSpace: colorSpace,
+	}
+	d.images = append(d.images, img)
+
+	return img, nil
+}
+
+// Draws img scaled to w by h with its top left corner at (x, y)
+func (d *Document) DrawImage(img *Image, x, y, w, h float64) {
+	p := d.page()
+	if p == nil {
+		return
+	}
+
+	fmt.Fprintf(
+		&p.content,
+		"q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n",
+		w, h, x, d.height-y-h, img.name,
+	)
+}
+
+func (d *Document) page() *page {
+	if d.current < 0 || d.current >= len(d.pages) {
+		return nil
+	}
+
+	return d.pages[d.current]
+}
+
+// Writes the whole document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		return 0, ErrNoPage
+	}
+
+	pw := &pdfWriter{w: w}
+	pw.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// Objects are numbered in this order: catalog, page tree, fonts,
+	// images, then a page and its contents for every page
+	fontObj := 3
+	imageObj := fontObj + len(fontNames)
+	pageObj := imageObj + len(d.images)
+
+	pw.startObj()
+	pw.printf("<< /Type /Catalog /Pages 2 0 R >>\n")
+	pw.endObj()
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageObj+i*2)
+	}
+	pw.startObj()
+	pw.printf("<< /Type /Pages /Kids [%s] /Count %d >>\n", strings.Join(kids, " "), len(d.pages))
+	pw.endObj()
+
+	fonts := make([]string, len(fontNames))
+	for i := 0; i < len(fontNames); i++ {
+		pw.startObj()
+		pw.printf(
+			"<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\n",
+			fontNames[Font(i)],
+		)
+		pw.endObj()
+		fonts[i] = fmt.Sprintf("/F%d %d 0 R", i+1, fontObj+i)
+	}
+
+	images := make([]string, len(d.images))
+	for i, img := range d.images {
+		pw.startObj()
+		pw.printf(
+			"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n",
+			img.Width, img.Height, img.colorSpace, len(img.data),
+		)
+		pw.write(img.data)
+		pw.printf("\nendstream\n")
+		pw.endObj()
+		images[i] = fmt.Sprintf("/%s %d 0 R", img.name, imageObj+i)
+	}
+
+	resources := fmt.Sprintf("<< /Font << %s >> /XObject << %s >> >>", strings.Join(fonts, " "), strings.Join(images, " "))
+	for i, p := range d.pages {
+		pw.startObj()
+		pw.printf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>\n",
+			d.width, d.height, resources, pageObj+i*2+1,
+		)
+		pw.endObj()
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(p.content.Bytes())
+		zw.Close()
+
+		pw.startObj()
+		pw.printf("<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+		pw.write(compressed.Bytes())
+		pw.printf("\nendstream\n")
+		pw.endObj()
+	}
+
+	xrefOffset := pw.n
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, off := range pw.offsets {
+		pw.printf("%010d 00000 n \n", off)
+	}
+	pw.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, xrefOffset)
+
+	return pw.n, pw.err
+}
+
+// Keeps track of the offsets of every object and the first write error
+type pdfWriter struct {
+	w       io.Writer
+	n       int64
+	err     error
+	offsets []int64
+}
+
+func (pw *pdfWriter) write(b []byte) {
+	if pw.err != nil {
+		return
+	}
+
+	n, err := pw.w.Write(b)
+	pw.n += int64(n)
+	pw.err = err
+}
+
+func (pw *pdfWriter) printf(format string, args ...any) {
+	pw.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (pw *pdfWriter) startObj() {
+	pw.offsets = append(pw.offsets, pw.n)
+	pw.printf("%d 0 obj\n", len(pw.offsets))
+}
+
+func (pw *pdfWriter) endObj() {
+	pw.printf("endobj\n")
+}
+
+// Escapes the characters with special meaning inside PDF string literals
+func escapeString(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\r', '\n':
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
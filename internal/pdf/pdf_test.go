@@ -0,0 +1,81 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	lines := WrapText(Helvetica, 10, "Se tiene por recibido el escrito\n\nde la parte actora", 100)
+
+	expect := []string{"Se tiene por recibido", "el escrito", "", "de la parte actora"}
+	if len(lines) != len(expect) {
+		t.Fatalf("Expected %d lines, got %d: %q", len(expect), len(lines), lines)
+	}
+	for i, line := range lines {
+		if line != expect[i] {
+			t.Errorf("lines[%d] should be %q, got %q", i, expect[i], line)
+		}
+		if w := StringWidth(Helvetica, 10, line); w > 100 {
+			t.Errorf("lines[%d] is %.2fpt wide, over the 100pt limit", i, w)
+		}
+	}
+}
+
+func TestWrapTextLongWord(t *testing.T) {
+	lines := WrapText(Helvetica, 10, strings.Repeat("m", 30), 50)
+	if len(lines) < 2 {
+		t.Fatalf("Expected the word to be split, got %q", lines)
+	}
+	if strings.Join(lines, "") != strings.Repeat("m", 30) {
+		t.Errorf("Splitting lost characters: %q", lines)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	d := New(LetterWidth, LetterHeight)
+	if _, err := d.WriteTo(&bytes.Buffer{}); err != ErrNoPage {
+		t.Errorf("Expected ErrNoPage for an empty document, got %v", err)
+	}
+
+	d.AddPage()
+	d.Text(72, 72, "Año (2024)", 0, 0, 0)
+	d.AddPage()
+
+	buf := &bytes.Buffer{}
+	if _, err := d.WriteTo(buf); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Errorf("Output is missing the PDF header or trailer")
+	}
+	if !strings.Contains(out, "/Count 2") {
+		t.Errorf("Expected a page tree with 2 pages")
+	}
+
+	// Every xref entry must point at the start of its object
+	xref := strings.Index(out, "xref\n")
+	entries := strings.Split(out[xref:], "\n")[3:]
+	for i, entry := range entries {
+		if !strings.HasSuffix(entry, " n ") {
+			break
+		}
+		var offset int
+		fmt.Sscanf(entry, "%d", &offset)
+		if !strings.HasPrefix(out[offset:], fmt.Sprintf("%d 0 obj", i+1)) {
+			t.Errorf("xref entry %d points at %q", i+1, out[offset:offset+10])
+		}
+	}
+}
+
+func TestEncodeWinAnsi(t *testing.T) {
+	got := encodeWinAnsi("ñ€→")
+	expect := []byte{0xF1, 0x80, '?'}
+	if !bytes.Equal(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+}
@@ -0,0 +1,53 @@
+package pdf
+
+import "strings"
+
+// Splits s into lines no wider than maxWidth when set in font at size.
+//
+// Line breaks in s are kept and words longer than a line are split
+func WrapText(font Font, size float64, s string, maxWidth float64) []string {
+	lines := []string{}
+	for _, paragraph := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := ""
+		for _, word := range words {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if StringWidth(font, size, candidate) <= maxWidth {
+				line = candidate
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = word
+			for StringWidth(font, size, line) > maxWidth {
+				head, tail := splitAtWidth(font, size, line, maxWidth)
+				lines = append(lines, head)
+				line = tail
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// Splits s at the last rune that fits in maxWidth, keeping at least one
+func splitAtWidth(font Font, size float64, s string, maxWidth float64) (string, string) {
+	runes := []rune(s)
+	n := 1
+	for n < len(runes) && StringWidth(font, size, string(runes[:n+1])) <= maxWidth {
+		n++
+	}
+
+	return string(runes[:n]), string(runes[n:])
+}
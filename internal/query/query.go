@@ -0,0 +1,349 @@
+// Package query implements the small query language used to search cases.
+//
+// A query is a list of clauses separated by whitespace, all of which must
+// match for a case to be returned:
+//
+//	type:fam2 year:2023 tag:urgent updated:>2024-12-01 "pensión alimenticia"
+//
+// Clauses are either free text terms, "quoted phrases" or `field:value`
+// filters. Any clause can be negated with a leading `-`. Comparable fields
+// (year, no and updated) accept the operators >, >=, < and <= right after
+// the colon. Dates are formatted as 2006-01-02 or given relative to the
+// current day as a number of days (30d), weeks (2w), months (6m) or years (1y).
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Field string
+
+const (
+	FieldText    Field = ""
+	FieldType    Field = "type"
+	FieldYear    Field = "year"
+	FieldNo      Field = "no"
+	FieldId      Field = "id"
+	FieldTag     Field = "tag"
+	FieldAlias   Field = "alias"
+	FieldNature  Field = "nature"
+	FieldClient  Field = "client"
+	FieldUpdated Field = "updated"
+	FieldIs      Field = "is"
+)
+
+// Values accepted by the `is` field
+const (
+	IsArchived = "archived"
+	IsActive   = "active"
+)
+
+type Operator string
+
+const (
+	OpEq  Operator = "="
+	OpGt  Operator = ">"
+	OpGte Operator = ">="
+	OpLt  Operator = "<"
+	OpLte Operator = "<="
+)
+
+type fieldKind int
+
+const (
+	kindText fieldKind = iota
+	kindNumber
+	kindDate
+	kindState
+)
+
+var fieldKinds = map[Field]fieldKind{
+	FieldType:    kindText,
+	FieldYear:    kindNumber,
+	FieldNo:      kindNumber,
+	FieldId:      kindText,
+	FieldTag:     kindText,
+	FieldAlias:   kindText,
+	FieldNature:  kindText,
+	FieldClient:  kindText,
+	FieldUpdated: kindDate,
+	FieldIs:      kindState,
+}
+
+// Spanish names accepted for each field
+var fieldAliases = map[string]Field{
+	"tipo":        FieldType,
+	"año":         FieldYear,
+	"anio":        FieldYear,
+	"numero":      FieldNo,
+	"etiqueta":    FieldTag,
+	"naturaleza":  FieldNature,
+	"cliente":     FieldClient,
+	"actualizado": FieldUpdated,
+	"es":          FieldIs,
+}
+
+// A single condition of the query
+type Clause struct {
+	Field   Field
+	Op      Operator
+	Value   string
+	Negated bool
+	// Only set for free text clauses written between quotes
+	Phrase bool
+	// Byte offset of the clause within the query
+	Pos int
+}
+
+type Query struct {
+	Clauses []*Clause
+}
+
+func (q *Query) IsEmpty() bool {
+	return q == nil || len(q.Clauses) == 0
+}
+
+// Reports the offending token of a query that could not be parsed
+type ParseError struct {
+	// Byte offset of the token within the query
+	Pos   int
+	Token string
+	Msg   string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("query: %s at position %d: %q", e.Msg, e.Pos, e.Token)
+}
+
+func Parse(input string) (*Query, error) {
+	p := &parser{input: input}
+	q := &Query{Clauses: []*Clause{}}
+
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+
+		c, err := p.parseClause()
+		if err != nil {
+			return nil, err
+		}
+		q.Clauses = append(q.Clauses, c)
+	}
+
+	return q, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *parser) parseClause() (*Clause, error) {
+	c := &Clause{Op: OpEq, Pos: p.pos}
+
+	if p.peek() == '-' && p.pos+1 < len(p.input) && !isSpace(p.input[p.pos+1]) {
+		c.Negated = true
+		p.pos++
+	}
+
+	if p.peek() == '"' {
+		phrase, err := p.readPhrase()
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(phrase) == "" {
+			return nil, &ParseError{Pos: c.Pos, Token: p.input[c.Pos:p.pos], Msg: "empty phrase"}
+		}
+
+		c.Field = FieldText
+		c.Value = phrase
+		c.Phrase = true
+		return c, nil
+	}
+
+	wordStart := p.pos
+	word := p.readWord()
+	name, value, hasField := strings.Cut(word, ":")
+	if !hasField || !isFieldName(name) {
+		c.Field = FieldText
+		c.Value = word
+		return c, nil
+	}
+
+	field, ok := lookupField(name)
+	if !ok {
+		return nil, &ParseError{Pos: wordStart, Token: name, Msg: "unknown field"}
+	}
+	c.Field = field
+
+	valuePos := wordStart + len(name) + 1
+	c.Op, value = cutOperator(value)
+	if value == "" && !p.done() && p.peek() == '"' {
+		phrase, err := p.readPhrase()
+		if err != nil {
+			return nil, err
+		}
+		value = phrase
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil, &ParseError{Pos: wordStart, Token: p.input[wordStart:p.pos], Msg: "missing value for field"}
+	}
+	c.Value = value
+
+	if err := validateValue(c); err != nil {
+		return nil, &ParseError{Pos: valuePos, Token: p.input[valuePos:p.pos], Msg: err.Error()}
+	}
+
+	return c, nil
+}
+
+// Reads until the next whitespace or quote
+func (p *parser) readWord() string {
+	start := p.pos
+	for !p.done() && !isSpace(p.peek()) && p.peek() != '"' {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+// Reads a phrase between double quotes. A quote can be
+// included in the phrase by escaping it with a backslash
+func (p *parser) readPhrase() (string, error) {
+	start := p.pos
+	p.pos++
+
+	var sb strings.Builder
+	for !p.done() {
+		ch := p.peek()
+		switch {
+		case ch == '\\' && p.pos+1 < len(p.input) && p.input[p.pos+1] == '"':
+			sb.WriteByte('"')
+			p.pos += 2
+		case ch == '"':
+			p.pos++
+			return sb.String(), nil
+		default:
+			sb.WriteByte(ch)
+			p.pos++
+		}
+	}
+
+	return "", &ParseError{Pos: start, Token: p.input[start:], Msg: "unterminated quote"}
+}
+
+func cutOperator(value string) (Operator, string) {
+	for _, op := range []Operator{OpGte, OpLte, OpGt, OpLt, OpEq} {
+		if rest, ok := strings.CutPrefix(value, string(op)); ok {
+			return op, rest
+		}
+	}
+
+	return OpEq, value
+}
+
+func validateValue(c *Clause) error {
+	kind := fieldKinds[c.Field]
+	if c.Op != OpEq && kind != kindNumber && kind != kindDate {
+		return fmt.Errorf("operator %s not supported by field %s", c.Op, c.Field)
+	}
+
+	switch kind {
+	case kindNumber:
+		if _, err := strconv.Atoi(c.Value); err != nil {
+			return fmt.Errorf("field %s expects a number", c.Field)
+		}
+	case kindDate:
+		if _, err := ResolveDate(c.Value, time.Now()); err != nil {
+			return err
+		}
+	case kindState:
+		if c.Value != IsArchived && c.Value != IsActive {
+			return fmt.Errorf("field %s expects %q or %q", c.Field, IsArchived, IsActive)
+		}
+	}
+
+	return nil
+}
+
+// Resolves a date value, either absolute (2006-01-02) or relative
+// to now (30d, 2w, 6m, 1y), to the start of its day
+func ResolveDate(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	errInvalid := fmt.Errorf("invalid date, expected 2006-01-02 or a relative date like 30d")
+	if len(value) < 2 {
+		return time.Time{}, errInvalid
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, errInvalid
+	}
+
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	switch value[len(value)-1] {
+	case 'd':
+		return today.AddDate(0, 0, -n), nil
+	case 'w':
+		return today.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return today.AddDate(0, -n, 0), nil
+	case 'y':
+		return today.AddDate(-n, 0, 0), nil
+	}
+
+	return time.Time{}, errInvalid
+}
+
+func lookupField(name string) (Field, bool) {
+	name = strings.ToLower(name)
+	if f, ok := fieldAliases[name]; ok {
+		return f, true
+	}
+
+	f := Field(name)
+	_, ok := fieldKinds[f]
+	return f, ok
+}
+
+// Field names are made of letters only, so values like
+// times (12:30) are read as free text
+func isFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == 'ñ' || r == 'Ñ') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
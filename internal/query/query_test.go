@@ -0,0 +1,89 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`type:fam2 year:>=2023 -tag:urgent updated:<30d "pensión alimenticia" 123/2024 cliente:"Juan Pérez"`)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+
+	expectClauses := []Clause{
+		{Field: FieldType, Op: OpEq, Value: "fam2", Pos: 0},
+		{Field: FieldYear, Op: OpGte, Value: "2023", Pos: 10},
+		{Field: FieldTag, Op: OpEq, Value: "urgent", Negated: true, Pos: 22},
+		{Field: FieldUpdated, Op: OpLt, Value: "30d", Pos: 34},
+		{Field: FieldText, Op: OpEq, Value: "pensión alimenticia", Phrase: true, Pos: 47},
+		{Field: FieldText, Op: OpEq, Value: "123/2024", Pos: 70},
+		{Field: FieldClient, Op: OpEq, Value: "Juan Pérez", Pos: 79},
+	}
+	if len(q.Clauses) != len(expectClauses) {
+		t.Fatalf("Expected %d clauses, got %d", len(expectClauses), len(q.Clauses))
+	}
+	for i, expected := range expectClauses {
+		if *q.Clauses[i] != expected {
+			t.Errorf("Clauses[%d] is not %+v, got %+v", i, expected, *q.Clauses[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		input     string
+		expectPos int
+		expectTok string
+	}{
+		{`type:fam2 tpye:fam1`, 10, "tpye"},
+		{`year:20x3`, 5, "20x3"},
+		{`tag:>urgent`, 4, ">urgent"},
+		{`"unterminated phrase`, 0, `"unterminated phrase`},
+		{`alias: foo`, 0, "alias:"},
+		{`updated:>yesterday`, 8, ">yesterday"},
+		{`is:closed`, 3, "closed"},
+	}
+
+	for _, c := range cases {
+		_, err := Parse(c.input)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Parse(%q) expected a ParseError, got %v", c.input, err)
+			continue
+		}
+		if parseErr.Pos != c.expectPos || parseErr.Token != c.expectTok {
+			t.Errorf(
+				"Parse(%q) expected error at %d on %q, got %d on %q",
+				c.input,
+				c.expectPos,
+				c.expectTok,
+				parseErr.Pos,
+				parseErr.Token,
+			)
+		}
+	}
+}
+
+func TestResolveDate(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 18, 30, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"2024-12-01": time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC),
+		"30d":        time.Date(2025, time.February, 13, 0, 0, 0, 0, time.UTC),
+		"2w":         time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		"6m":         time.Date(2024, time.September, 15, 0, 0, 0, 0, time.UTC),
+		"1y":         time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	for value, expected := range cases {
+		got, err := ResolveDate(value, now)
+		if err != nil {
+			t.Errorf("ResolveDate(%q) errored with %v", value, err)
+			continue
+		}
+		if !got.Equal(expected) {
+			t.Errorf("ResolveDate(%q) is not %s, got %s", value, expected, got)
+		}
+	}
+}
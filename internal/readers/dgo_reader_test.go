@@ -10,4 +10,27 @@ More useless data
 
 No.		
 	`
+	data := []byte(input)
+	caseTable, err := dgoReader(&data)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if len(caseTable.Cases) != 0 {
+		t.Errorf("Expected no cases from the headers, got %d", len(caseTable.Cases))
+	}
+
+	data = []byte(input + `
+       1       00084/2003    Some sample nature   Some sample accord content
+`)
+	caseTable, err = dgoReader(&data)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	row := caseTable.Find("84/2003")
+	if row == nil {
+		t.Fatalf("Expected to find 84/2003, got %+v", caseTable.Cases)
+	}
+	if row.Nature != "Some sample nature" || row.Accord != "Some sample accord content" {
+		t.Errorf("Expected the nature and accord of the row, got %+v", row)
+	}
 }
@@ -0,0 +1,130 @@
+package readers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/fetchers"
+)
+
+var (
+	ErrUnknownProfile    = errors.New("unknown reader profile")
+	ErrUnsupportedFormat = errors.New("the reader doesn't accept the document format")
+	ErrNilDocument       = errors.New("the document is nil")
+)
+
+// Reads the documents of the formats it declares
+type DocReader interface {
+	Formats() []internal.DocFormat
+	Read(doc *internal.Document) (*CaseTable, error)
+}
+
+type funcDocReader struct {
+	formats []internal.DocFormat
+	read    func(*internal.Document) (*CaseTable, error)
+}
+
+func (r *funcDocReader) Formats() []internal.DocFormat {
+	return r.formats
+}
+
+func (r *funcDocReader) Read(doc *internal.Document) (*CaseTable, error) {
+	return r.read(doc)
+}
+
+// Adapts a Reader to read the data of documents in the formats
+func NewDocReader(read Reader, formats ...internal.DocFormat) DocReader {
+	return &funcDocReader{
+		formats: formats,
+		read: func(doc *internal.Document) (*CaseTable, error) {
+			return read(&doc.Data)
+		},
+	}
+}
+
+// Settings of a reader profile given by the source definition
+type ReaderOptions struct {
+	// Headers of the columns read by table readers. Matched
+	// ignoring case, unset columns are detected from their header
+	Columns TableColumns `json:"columns" yaml:"columns"`
+}
+
+type TableColumns struct {
+	CaseId string `json:"caseId" yaml:"caseId"`
+	Nature string `json:"nature" yaml:"nature"`
+	Accord string `json:"accord" yaml:"accord"`
+}
+
+// Readers by the name source definitions use to refer to them
+var profiles = map[string]func(opts ReaderOptions) (DocReader, error){
+	"dgo": func(ReaderOptions) (DocReader, error) {
+		return NewDocReader(dgoReader, internal.DocFormatText), nil
+	},
+	"html-table": func(opts ReaderOptions) (DocReader, error) {
+		return NewHtmlTableReader(opts.Columns), nil
+	},
+}
+
+// Returns the reader registered with the profile name
+func ReaderForProfile(name string, opts ReaderOptions) (DocReader, error) {
+	newReader, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%q:\n\t%w", name, ErrUnknownProfile)
+	}
+
+	return newReader(opts)
+}
+
+// Reports whether the reader accepts the format as is
+func Accepts(r DocReader, format internal.DocFormat) bool {
+	return slices.Contains(r.Formats(), format)
+}
+
+// Reports whether the reader can read the format, directly
+// or after converting the document
+func CanRead(r DocReader, format internal.DocFormat) bool {
+	if Accepts(r, format) {
+		return true
+	}
+
+	// PDFs are turned into text with pdftotext
+	return format == internal.DocFormatPdf && Accepts(r, internal.DocFormatText)
+}
+
+// Reads the document, converting it first when the reader
+// doesn't accept its format
+func ReadDocument(r DocReader, doc *internal.Document) (*CaseTable, error) {
+	if doc == nil {
+		return nil, ErrNilDocument
+	}
+
+	doc, err := convertFor(r, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Read(doc)
+}
+
+// Returns a converted copy of the document, or the document
+// itself if the reader accepts it
+func convertFor(r DocReader, doc *internal.Document) (*internal.Document, error) {
+	if Accepts(r, doc.Format) {
+		return doc, nil
+	}
+	if !CanRead(r, doc.Format) {
+		return nil, fmt.Errorf("%s document:\n\t%w", doc.Format, ErrUnsupportedFormat)
+	}
+
+	converted := *doc
+	data := slices.Clone(doc.Data)
+	if err := fetchers.PDFToData(&data); err != nil {
+		return nil, fmt.Errorf("Transform file err: %w", err)
+	}
+	converted.Data = data
+	converted.Format = internal.DocFormatText
+
+	return &converted, nil
+}
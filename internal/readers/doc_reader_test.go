@@ -0,0 +1,79 @@
+package readers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+// Puts a pdftotext in PATH that prints the text instead of converting
+// its input, so the conversion runs without poppler installed
+func fakePdfToText(t *testing.T, text string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake pdftotext is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncat > /dev/null\nprintf '%s' '" + text + "'\n"
+	if err := os.WriteFile(filepath.Join(dir, "pdftotext"), []byte(script), 0o755); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestReadDocumentConvertsPdf(t *testing.T) {
+	fakePdfToText(t, "converted text")
+
+	var read *internal.Document
+	reader := &funcDocReader{
+		formats: []internal.DocFormat{internal.DocFormatText},
+		read: func(doc *internal.Document) (*CaseTable, error) {
+			read = doc
+			return NewCaseTable(), nil
+		},
+	}
+	pdf := &internal.Document{Format: internal.DocFormatPdf, Data: []byte("%PDF-1.4"), Url: "https://example.com/lista.pdf"}
+
+	if _, err := ReadDocument(reader, pdf); err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if read == nil || read.Format != internal.DocFormatText || string(read.Data) != "converted text" {
+		t.Fatalf("Expected the reader to get the converted text, got %+v", read)
+	}
+	if read.Url != pdf.Url {
+		t.Errorf("Expected the converted document to keep the metadata, got %+v", read)
+	}
+	if pdf.Format != internal.DocFormatPdf || string(pdf.Data) != "%PDF-1.4" {
+		t.Errorf("Expected the fetched document to be left as is, got %+v", pdf)
+	}
+}
+
+func TestReadDocumentFormats(t *testing.T) {
+	text := &internal.Document{Format: internal.DocFormatText, Data: []byte("text")}
+	textReader := NewDocReader(func(data *[]byte) (*CaseTable, error) {
+		if string(*data) != "text" {
+			t.Errorf("Expected the text as is, got %q", *data)
+		}
+		return NewCaseTable(), nil
+	}, internal.DocFormatText)
+	if _, err := ReadDocument(textReader, text); err != nil {
+		t.Errorf("errored with\n  %v", err)
+	}
+
+	htmlReader := NewHtmlTableReader(TableColumns{})
+	if CanRead(htmlReader, internal.DocFormatPdf) {
+		t.Errorf("Expected the html reader not to read PDFs")
+	}
+	pdf := &internal.Document{Format: internal.DocFormatPdf, Data: []byte("%PDF-1.4")}
+	if _, err := ReadDocument(htmlReader, pdf); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+	if _, err := ReadDocument(htmlReader, nil); !errors.Is(err, ErrNilDocument) {
+		t.Errorf("Expected ErrNilDocument, got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package readers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+var ErrNoCaseTable = errors.New("the page has no table with a case id column")
+
+// Words that identify each column when its header isn't configured
+var htmlColumnKeywords = struct {
+	caseId, nature, accord []string
+}{
+	caseId: []string{"expediente", "exp.", "toca", "causa"},
+	nature: []string{"naturaleza", "juicio", "asunto", "partes", "actor"},
+	accord: []string{"acuerdo", "síntesis", "sintesis", "extracto", "resolución", "resolucion"},
+}
+
+// Case ids as written in the lists, e.g. "123/2024" or "123/2024-I"
+var htmlCaseIdRe = regexp.MustCompile(`\d+/\d{2,4}(?:-[A-Za-z0-9]+)?`)
+
+// Reads the lists courts publish as HTML tables. Every table whose
+// header has a case id column is read, one case per row
+type HtmlTableReader struct {
+	columns TableColumns
+}
+
+func NewHtmlTableReader(columns TableColumns) *HtmlTableReader {
+	return &HtmlTableReader{columns}
+}
+
+func (r *HtmlTableReader) Formats() []internal.DocFormat {
+	return []internal.DocFormat{internal.DocFormatHtml}
+}
+
+func (r *HtmlTableReader) Read(doc *internal.Document) (*CaseTable, error) {
+	// Many court sites still serve latin-1 pages
+	body, err := charset.NewReader(bytes.NewReader(doc.Data), doc.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	root, err := html.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("Parse html err: %w", err)
+	}
+
+	caseTable := NewCaseTable()
+	foundTable := false
+	for _, table := range findAll(root, atom.Table) {
+		rows := htmlTableRows(table)
+		header, cols, ok := r.findColumns(rows)
+		if !ok {
+			continue
+		}
+		foundTable = true
+
+		for _, row := range rows[header+1:] {
+			r.addRow(caseTable, doc.CaseType, row, cols)
+		}
+	}
+
+	if !foundTable {
+		return nil, ErrNoCaseTable
+	}
+	if len(caseTable.Cases) == 0 {
+		return nil, ErrNoRows
+	}
+
+	return caseTable, nil
+}
+
+// Column indexes of a table, -1 when it doesn't have the column
+type htmlColumns struct {
+	caseId, nature, accord int
+}
+
+// Returns the index of the header row and its columns. The header
+// is the first row with a case id column
+func (r *HtmlTableReader) findColumns(rows [][]string) (int, htmlColumns, bool) {
+	for i, row := range rows {
+		cols := htmlColumns{
+			caseId: matchColumn(row, r.columns.CaseId, htmlColumnKeywords.caseId),
+			nature: matchColumn(row, r.columns.Nature, htmlColumnKeywords.nature),
+			accord: matchColumn(row, r.columns.Accord, htmlColumnKeywords.accord),
+		}
+		if cols.caseId >= 0 {
+			return i, cols, true
+		}
+	}
+
+	return 0, htmlColumns{}, false
+}
+
+func (r *HtmlTableReader) addRow(caseTable *CaseTable, caseType internal.CaseType, row []string, cols htmlColumns) {
+	if cols.caseId >= len(row) {
+		return
+	}
+
+	caseData := &CaseData{
+		CaseType: string(caseType),
+		CaseId:   strings.Join(htmlCaseIdRe.FindAllString(row[cols.caseId], -1), "\n"),
+		Nature:   cellAt(row, cols.nature),
+		Accord:   cellAt(row, cols.accord),
+	}
+	if caseData.CaseId == "" {
+		// Rows without ids are separators or notes, not cases
+		if strings.TrimSpace(row[cols.caseId]) != "" {
+			caseTable.UnparsedCases = append(caseTable.UnparsedCases, caseData)
+		}
+		return
+	}
+
+	caseRow, err := NewCaseRow(caseData)
+	if err != nil {
+		caseTable.UnparsedCases = append(caseTable.UnparsedCases, caseData)
+		return
+	}
+	caseTable.Add(caseRow)
+}
+
+// Index of the cell matching the configured header or, when there's
+// none, containing one of the keywords. -1 if no cell matches
+func matchColumn(row []string, header string, keywords []string) int {
+	for i, cell := range row {
+		cell = strings.ToLower(cell)
+		if header != "" {
+			if cell == strings.ToLower(strings.TrimSpace(header)) {
+				return i
+			}
+			continue
+		}
+
+		for _, kw := range keywords {
+			if strings.Contains(cell, kw) {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func cellAt(row []string, idx int) string {
+	if idx < 0 || idx >= len(row) {
+		return ""
+	}
+
+	return row[idx]
+}
+
+// Returns the text of the cells of every row of the table,
+// leaving out the rows of nested tables
+func htmlTableRows(table *html.Node) [][]string {
+	rows := [][]string{}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+
+			switch c.DataAtom {
+			case atom.Table:
+				continue
+			case atom.Tr:
+				cells := []string{}
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+						cells = append(cells, nodeText(cell))
+					}
+				}
+				rows = append(rows, cells)
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(table)
+
+	return rows
+}
+
+func findAll(n *html.Node, a atom.Atom) []*html.Node {
+	found := []*html.Node{}
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		found = append(found, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		found = append(found, findAll(c, a)...)
+	}
+
+	return found
+}
+
+// Text of the node with its whitespace collapsed. Line breaks
+// and block elements start new lines
+func nodeText(n *html.Node) string {
+	buf := &strings.Builder{}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			buf.WriteString(n.Data)
+		case n.DataAtom == atom.Br:
+			buf.WriteByte('\n')
+		case n.DataAtom == atom.P || n.DataAtom == atom.Div || n.DataAtom == atom.Li:
+			buf.WriteByte('\n')
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+			buf.WriteByte('\n')
+		default:
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+
+	lines := []string{}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
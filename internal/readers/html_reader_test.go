@@ -0,0 +1,116 @@
+package readers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+func htmlDoc(body string) *internal.Document {
+	return &internal.Document{
+		Format:      internal.DocFormatHtml,
+		Data:        []byte(body),
+		ContentType: "text/html; charset=utf-8",
+		CaseType:    internal.CaseTypeFam2,
+	}
+}
+
+func TestHtmlTableReaderKeywordColumns(t *testing.T) {
+	doc := htmlDoc(`<html><body>
+<table>
+	<tr><td colspan="4">Lista de acuerdos del 1 de marzo</td></tr>
+	<tr><th>No.</th><th>Expediente</th><th>Juicio</th><th>Síntesis del acuerdo</th></tr>
+	<tr><td>1</td><td>00123/2024</td><td>Divorcio   incausado</td><td><p>Se admite</p><p>la demanda</p></td></tr>
+	<tr><td>2</td><td>45/2023<br>45/2023-I</td><td>Alimentos</td><td>Se fija audiencia</td></tr>
+	<tr><td></td><td></td><td></td><td></td></tr>
+	<tr><td>3</td><td>Sin número</td><td>Sucesorio</td><td>Se turna</td></tr>
+</table>
+</body></html>`)
+
+	caseTable, err := NewHtmlTableReader(TableColumns{}).Read(doc)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if len(caseTable.Cases) != 2 {
+		t.Fatalf("Expected 2 cases, got %d: %+v", len(caseTable.Cases), caseTable.Cases)
+	}
+
+	row := caseTable.Find("00123/2024")
+	if row == nil {
+		t.Fatalf("Expected to find 00123/2024, got %+v", caseTable.Cases)
+	}
+	if row.Nature != "Divorcio incausado" || row.Accord != "Se admite\nla demanda" || row.CaseType != "fam2" {
+		t.Errorf("Expected the collapsed nature and accord of fam2, got %+v", row)
+	}
+
+	// Every id of a cell belongs to the same case
+	row = caseTable.Find("45/2023")
+	if row == nil || row != caseTable.Find("45/2023-I") || len(row.AllIds) != 2 {
+		t.Errorf("Expected 45/2023 and 45/2023-I to find the same case, got %+v", row)
+	}
+
+	if len(caseTable.UnparsedCases) != 1 || caseTable.UnparsedCases[0].Nature != "Sucesorio" {
+		t.Errorf("Expected the row without an id to be unparsed, got %+v", caseTable.UnparsedCases)
+	}
+}
+
+func TestHtmlTableReaderConfiguredColumns(t *testing.T) {
+	doc := htmlDoc(`<table>
+	<tr><th>Expediente anterior</th><th>Clave</th><th>Descripción</th></tr>
+	<tr><td>1/2020</td><td>12/2024</td><td>Se admite la demanda</td></tr>
+</table>`)
+
+	// A configured header only matches its exact text, so the keyword
+	// of "Expediente anterior" doesn't make it the id column
+	caseTable, err := NewHtmlTableReader(TableColumns{CaseId: "clave", Accord: "Descripción"}).Read(doc)
+	if err != nil {
+		t.Fatalf("errored with\n  %v", err)
+	}
+	if caseTable.Find("1/2020") != nil {
+		t.Errorf("Expected the unconfigured column to be ignored, got %+v", caseTable.Cases)
+	}
+	row := caseTable.Find("12/2024")
+	if row == nil || row.Accord != "Se admite la demanda" || row.Nature != "" {
+		t.Errorf("Expected 12/2024 read from the configured columns, got %+v", row)
+	}
+
+	if _, err := NewHtmlTableReader(TableColumns{CaseId: "Folio"}).Read(doc); !errors.Is(err, ErrNoCaseTable) {
+		t.Errorf("Expected ErrNoCaseTable without the configured header, got %v", err)
+	}
+}
+
+func TestHtmlTableReaderLatin1(t *testing.T) {
+	// "Pensión alimenticia" and "Resolución" encoded as latin-1
+	body := "<table><tr><th>Expediente</th><th>Naturaleza</th><th>Resoluci\xf3n</th></tr>" +
+		"<tr><td>7/2024</td><td>Pensi\xf3n alimenticia</td><td>Se resolvi\xf3</td></tr></table>"
+
+	for _, contentType := range []string{"text/html; charset=iso-8859-1", ""} {
+		doc := htmlDoc(body)
+		doc.ContentType = contentType
+		if contentType == "" {
+			// Without a content type the charset is read from the page
+			doc.Data = append([]byte(`<meta charset="iso-8859-1">`), doc.Data...)
+		}
+
+		caseTable, err := NewHtmlTableReader(TableColumns{}).Read(doc)
+		if err != nil {
+			t.Fatalf("%q: errored with\n  %v", contentType, err)
+		}
+		row := caseTable.Find("7/2024")
+		if row == nil || row.Nature != "Pensión alimenticia" || row.Accord != "Se resolvió" {
+			t.Errorf("%q: expected the text decoded as latin-1, got %+v", contentType, row)
+		}
+	}
+}
+
+func TestHtmlTableReaderErrors(t *testing.T) {
+	reader := NewHtmlTableReader(TableColumns{})
+
+	if _, err := reader.Read(htmlDoc(`<p>Sin acuerdos</p>`)); !errors.Is(err, ErrNoCaseTable) {
+		t.Errorf("Expected ErrNoCaseTable for a page without tables, got %v", err)
+	}
+	if _, err := reader.Read(htmlDoc(`<table><tr><th>Expediente</th></tr></table>`)); !errors.Is(err, ErrNoRows) {
+		t.Errorf("Expected ErrNoRows for a table without cases, got %v", err)
+	}
+}
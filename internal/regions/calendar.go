@@ -0,0 +1,33 @@
+package regions
+
+import "time"
+
+// Tells which days a region's courts publish their bulletins.
+// Searches skip the days that aren't workdays instead of fetching
+// documents that will never exist
+type Calendar interface {
+	IsWorkday(date time.Time) bool
+}
+
+// Calendar where courts publish monday to friday except on holidays.
+//
+// Holidays are written either as "01-02" (MM-DD) for the ones that fall
+// on the same day every year, or as "2006-01-02" for a single date
+type WeekdayCalendar struct {
+	Holidays []string
+}
+
+func (c WeekdayCalendar) IsWorkday(date time.Time) bool {
+	if wd := date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+
+	yearly, exact := date.Format("01-02"), date.Format("2006-01-02")
+	for _, h := range c.Holidays {
+		if h == yearly || h == exact {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+package regions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+var ErrUnknownCourt = errors.New("the region has no court for the case type")
+
+type Matter string
+
+const (
+	MatterCivil     Matter = "civil"
+	MatterFamiliar  Matter = "familiar"
+	MatterMercantil Matter = "mercantil"
+	MatterLaboral   Matter = "laboral"
+	MatterOther     Matter = "otro"
+)
+
+// Court publishing the accords of a case type in a region.
+// Only Name is always set, the rest is filled when the source
+// definition includes it
+type Court struct {
+	Region   internal.Region   `json:"region"`
+	CaseType internal.CaseType `json:"caseType"`
+	Name     string            `json:"name"`
+	Matter   Matter            `json:"matter"`
+	Address  string            `json:"address"`
+	Judge    string            `json:"judge"`
+	Schedule string            `json:"schedule"`
+}
+
+// Returns the court of the case type, nil when the region
+// doesn't publish it
+func (r *Region) Court(caseType internal.CaseType) *Court {
+	for _, c := range r.Courts {
+		if c.CaseType == caseType {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func FindCourt(region internal.Region, caseType internal.CaseType) (*Court, error) {
+	r, err := Find(region)
+	if err != nil {
+		return nil, err
+	}
+
+	c := r.Court(caseType)
+	if c == nil {
+		return nil, fmt.Errorf("%s in %s:\n\t%w", caseType, r.Id, ErrUnknownCourt)
+	}
+
+	return c, nil
+}
+
+// Returns the courts of every registered region
+func Catalog() []*Court {
+	courts := []*Court{}
+	for _, r := range All() {
+		courts = append(courts, r.Courts...)
+	}
+
+	return courts
+}
@@ -0,0 +1,120 @@
+// Package regions keeps the registry of the regions the app can search
+// for accords. Each region contributes the fetcher and reader used to
+// get its bulletins, the case types it publishes and the calendar of
+// the days its courts publish.
+//
+// Regions are described by source definitions (see SourceDef). The
+// built-in ones are embedded and more can be added to the sources
+// dir of the app data dir without a new build
+package regions
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/fetchers"
+	"github.com/vladwithcode/lex_app/internal/readers"
+)
+
+var (
+	ErrUnknownRegion    = errors.New("the region is not registered")
+	ErrDuplicatedRegion = errors.New("a region with the same id is already registered")
+	ErrInvalidRegion    = errors.New("the region is missing required fields")
+)
+
+type Region struct {
+	Id        internal.Region
+	Name      string
+	CaseTypes []internal.CaseType
+	// Catalog of the courts behind the case types, in the same order
+	Courts   []*Court
+	Calendar Calendar
+	Fetch    fetchers.DocFetcher
+	Reader   readers.DocReader
+}
+
+// Reports whether the region publishes accords for the case type
+func (r *Region) HasCaseType(caseType internal.CaseType) bool {
+	return slices.Contains(r.CaseTypes, caseType)
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[internal.Region]*Region{}
+)
+
+// Adds the region to the registry. Regions are registered once,
+// usually from an init func
+func Register(r *Region) error {
+	if r == nil || r.Id == "" || r.Fetch == nil || r.Reader == nil {
+		return ErrInvalidRegion
+	}
+	if r.Calendar == nil {
+		r.Calendar = WeekdayCalendar{}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := registry[r.Id]; ok {
+		return fmt.Errorf("%s:\n\t%w", r.Id, ErrDuplicatedRegion)
+	}
+	registry[r.Id] = r
+
+	return nil
+}
+
+// Adds the region to the registry, replacing the one with the same id
+func set(r *Region) {
+	if r.Calendar == nil {
+		r.Calendar = WeekdayCalendar{}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	registry[r.Id] = r
+}
+
+// Same as Register but panics on error
+func MustRegister(r *Region) {
+	if err := Register(r); err != nil {
+		panic(err)
+	}
+}
+
+// Returns the registered region with the id.
+// An empty id returns the default region
+func Find(id internal.Region) (*Region, error) {
+	if id == "" {
+		id = internal.RegionDefault
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	r, ok := registry[id]
+	if !ok {
+		return nil, fmt.Errorf("%s:\n\t%w", id, ErrUnknownRegion)
+	}
+
+	return r, nil
+}
+
+// Returns every registered region sorted by id
+func All() []*Region {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all := make([]*Region, 0, len(registry))
+	for _, r := range registry {
+		all = append(all, r)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Id < all[j].Id })
+
+	return all
+}
@@ -0,0 +1,51 @@
+package regions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+func TestWeekdayCalendar(t *testing.T) {
+	cal := WeekdayCalendar{Holidays: []string{"09-16", "2026-11-20"}}
+
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2026-10-19", true},  // monday
+		{"2026-10-17", false}, // saturday
+		{"2026-10-18", false}, // sunday
+		{"2025-09-16", false}, // yearly holiday
+		{"2026-11-20", false}, // single date holiday
+		{"2025-11-20", true},
+	}
+	for _, tt := range tests {
+		date, _ := time.Parse("2006-01-02", tt.date)
+		if got := cal.IsWorkday(date); got != tt.want {
+			t.Errorf("IsWorkday(%s) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	dgo, err := Find("")
+	if err != nil {
+		t.Fatalf("default region not registered: %v", err)
+	}
+	if dgo.Id != internal.RegionDefault {
+		t.Errorf("Find(\"\") returned %q, want %q", dgo.Id, internal.RegionDefault)
+	}
+	if !dgo.HasCaseType(internal.CaseTypeFam2) || dgo.HasCaseType("xyz") {
+		t.Error("HasCaseType doesn't match the registered case types")
+	}
+
+	if err := Register(dgo); !errors.Is(err, ErrDuplicatedRegion) {
+		t.Errorf("registering twice returned %v, want ErrDuplicatedRegion", err)
+	}
+	if _, err := Find("MX_XX_XX"); !errors.Is(err, ErrUnknownRegion) {
+		t.Errorf("Find of unknown region returned %v, want ErrUnknownRegion", err)
+	}
+}
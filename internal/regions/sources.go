@@ -0,0 +1,308 @@
+package regions
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/fetchers"
+	"github.com/vladwithcode/lex_app/internal/readers"
+)
+
+// Name of the directory, inside the app data dir, holding the
+// source definitions of the user
+const SourcesDirName = "sources"
+
+var (
+	ErrUnsupportedSourceFile = errors.New("source definitions must be .yaml, .yml or .json files")
+	ErrInvalidSource         = errors.New("invalid source definition")
+)
+
+//go:embed builtin/*.yaml
+var builtinSources embed.FS
+
+// Declarative description of a region: where its documents are
+// published and how to read them. Written as YAML or JSON, e.g.
+//
+//	region: MX_DGO_GPE
+//	name: Gómez Palacio, Dgo.
+//	url: http://example.com/listas/{date}/{caseType}.pdf
+//	dateFormat: "{DD}-{MM}-{YYYY}"
+//	format: pdf
+//	reader: dgo
+//	readerOptions:
+//	  columns:
+//	    caseId: No. de expediente
+//	holidays: ["01-01", "2026-11-20"]
+//	caseTypes:
+//	  - slug: fam1
+//	    name: Juzgado Primero Familiar
+//	    matter: familiar
+//	    address: Av. Ejemplo 123
+//	    judge: Lic. Nombre Apellido
+//	    schedule: Lunes a viernes de 8:00 a 15:00
+//	  - slug: civ1
+//	    remote: civil1
+type SourceDef struct {
+	Region internal.Region `json:"region" yaml:"region"`
+	Name   string          `json:"name" yaml:"name"`
+	// Url of the documents with {date} and {caseType} placeholders
+	Url string `json:"url" yaml:"url"`
+	// Built from the tokens {D}, {DD}, {M}, {MM}, {YY} and {YYYY}
+	// where the single letter versions aren't zero padded
+	DateFormat string `json:"dateFormat" yaml:"dateFormat"`
+	// One of pdf, html or text. Defaults to pdf
+	Format internal.DocFormat `json:"format" yaml:"format"`
+	// Name of the reader profile that parses the documents,
+	// dgo or html-table
+	Reader        string                `json:"reader" yaml:"reader"`
+	ReaderOptions readers.ReaderOptions `json:"readerOptions" yaml:"readerOptions"`
+	Holidays      []string              `json:"holidays" yaml:"holidays"`
+	CaseTypes     []CaseTypeDef         `json:"caseTypes" yaml:"caseTypes"`
+}
+
+type CaseTypeDef struct {
+	Slug internal.CaseType `json:"slug" yaml:"slug"`
+	// Name of the case type in the source url when it differs from slug
+	Remote string `json:"remote,omitempty" yaml:"remote,omitempty"`
+
+	// Court catalog data. Name defaults to the slug
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Matter   Matter `json:"matter,omitempty" yaml:"matter,omitempty"`
+	Address  string `json:"address,omitempty" yaml:"address,omitempty"`
+	Judge    string `json:"judge,omitempty" yaml:"judge,omitempty"`
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+}
+
+var dateTokens = strings.NewReplacer(
+	"{DD}", "02",
+	"{D}", "2",
+	"{MM}", "01",
+	"{M}", "1",
+	"{YYYY}", "2006",
+	"{YY}", "06",
+)
+
+// Parses a source definition. The format is picked from the
+// file extension
+func ParseSource(data []byte, filename string) (*SourceDef, error) {
+	def := &SourceDef{}
+
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, def)
+	case ".json":
+		err = json.Unmarshal(data, def)
+	default:
+		return nil, ErrUnsupportedSourceFile
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w:\n\t%w", ErrInvalidSource, err)
+	}
+
+	return def, nil
+}
+
+func (def *SourceDef) Validate() error {
+	missing := []string{}
+	if def.Region == "" {
+		missing = append(missing, "region")
+	}
+	if def.Url == "" {
+		missing = append(missing, "url")
+	}
+	if def.DateFormat == "" {
+		missing = append(missing, "dateFormat")
+	}
+	if def.Reader == "" {
+		missing = append(missing, "reader")
+	}
+	if len(def.CaseTypes) == 0 {
+		missing = append(missing, "caseTypes")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalidSource, strings.Join(missing, ", "))
+	}
+
+	for i, ct := range def.CaseTypes {
+		if ct.Slug == "" {
+			return fmt.Errorf("%w: caseTypes[%d] has no slug", ErrInvalidSource, i)
+		}
+	}
+
+	return nil
+}
+
+// Builds the region described by the definition
+func (def *SourceDef) Build() (*Region, error) {
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	format := def.Format
+	if format == "" {
+		format = internal.DocFormatPdf
+	}
+
+	caseTypes := make([]internal.CaseType, 0, len(def.CaseTypes))
+	courts := make([]*Court, 0, len(def.CaseTypes))
+	slugs := map[internal.CaseType]string{}
+	for _, ct := range def.CaseTypes {
+		caseTypes = append(caseTypes, ct.Slug)
+		if ct.Remote != "" {
+			slugs[ct.Slug] = ct.Remote
+		}
+
+		court := &Court{
+			Region:   def.Region,
+			CaseType: ct.Slug,
+			Name:     ct.Name,
+			Matter:   ct.Matter,
+			Address:  ct.Address,
+			Judge:    ct.Judge,
+			Schedule: ct.Schedule,
+		}
+		if court.Name == "" {
+			court.Name = string(ct.Slug)
+		}
+		if court.Matter == "" {
+			court.Matter = MatterOther
+		}
+		courts = append(courts, court)
+	}
+
+	fetch, err := fetchers.NewTemplateFetcher(fetchers.TemplateSource{
+		UrlTemplate: def.Url,
+		DateLayout:  dateTokens.Replace(def.DateFormat),
+		Format:      format,
+		Slugs:       slugs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w:\n\t%w", ErrInvalidSource, err)
+	}
+
+	reader, err := readers.ReaderForProfile(def.Reader, def.ReaderOptions)
+	if err != nil {
+		return nil, fmt.Errorf("%w:\n\t%w", ErrInvalidSource, err)
+	}
+	if !readers.CanRead(reader, format) {
+		return nil, fmt.Errorf("%w: reader %q can't read %s documents", ErrInvalidSource, def.Reader, format)
+	}
+
+	name := def.Name
+	if name == "" {
+		name = string(def.Region)
+	}
+
+	return &Region{
+		Id:        def.Region,
+		Name:      name,
+		CaseTypes: caseTypes,
+		Courts:    courts,
+		Calendar:  WeekdayCalendar{Holidays: def.Holidays},
+		Fetch:     fetch,
+		Reader:    reader,
+	}, nil
+}
+
+// Loads and registers every definition in the directory. Definitions
+// replace the registered region with the same id, so a built-in
+// source can be fixed without a new build.
+//
+// Invalid files are skipped and reported in the returned error
+func LoadSources(dir string) (loaded []*Region, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := []error{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		filename := filepath.Join(dir, e.Name())
+		r, err := loadSource(os.DirFS(dir), e.Name())
+		if errors.Is(err, ErrUnsupportedSourceFile) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filename, err))
+			continue
+		}
+
+		set(r)
+		loaded = append(loaded, r)
+	}
+
+	return loaded, errors.Join(errs...)
+}
+
+// Loads the definitions in the sources dir of the app data dir.
+// A missing dir means there are no user sources
+func LoadUserSources() ([]*Region, error) {
+	dir, err := SourcesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := LoadSources(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	return loaded, err
+}
+
+func SourcesDir() (string, error) {
+	dir, err := internal.GetAppDataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, SourcesDirName), nil
+}
+
+func loadSource(fsys fs.FS, name string) (*Region, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return nil, ErrUnsupportedSourceFile
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := ParseSource(data, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return def.Build()
+}
+
+func init() {
+	entries, err := builtinSources.ReadDir("builtin")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, e := range entries {
+		r, err := loadSource(builtinSources, "builtin/"+e.Name())
+		if err != nil {
+			panic(fmt.Errorf("builtin source %s: %w", e.Name(), err))
+		}
+		MustRegister(r)
+	}
+}
@@ -0,0 +1,134 @@
+package regions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal"
+	"github.com/vladwithcode/lex_app/internal/fetchers"
+	"github.com/vladwithcode/lex_app/internal/readers"
+)
+
+func TestBuiltinMatchesDgoUrl(t *testing.T) {
+	data, err := builtinSources.ReadFile("builtin/mx_dgo_dgo.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := ParseSource(data, "mx_dgo_dgo.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := fetchers.TemplateSource{
+		UrlTemplate: def.Url,
+		DateLayout:  dateTokens.Replace(def.DateFormat),
+	}
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.Local)
+	want := "http://tsjdgo.gob.mx/Recursos/images/flash/ListasAcuerdos/532024/fam2.pdf"
+	if got := src.Url(date, internal.CaseTypeFam2); got != want {
+		t.Errorf("built-in url is %q, want %q", got, want)
+	}
+}
+
+func TestLoadSources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"gpe.json": `{
+			"region": "MX_TEST_GPE",
+			"name": "Gómez Palacio",
+			"url": "http://example.com/{date}/{caseType}.txt",
+			"dateFormat": "{DD}-{MM}-{YY}",
+			"format": "text",
+			"reader": "dgo",
+			"caseTypes": [{"slug": "fam1", "remote": "familiar1", "name": "Juzgado Primero Familiar", "matter": "familiar"}]
+		}`,
+		"bad.yaml":  "region: MX_TEST_BAD\nurl: http://example.com\n",
+		"notes.txt": "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := LoadSources(dir)
+	if !errors.Is(err, ErrInvalidSource) {
+		t.Errorf("expected bad.yaml to be reported as ErrInvalidSource, got %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Id != "MX_TEST_GPE" {
+		t.Fatalf("expected only MX_TEST_GPE to load, got %v", loaded)
+	}
+
+	r, err := Find("MX_TEST_GPE")
+	if err != nil {
+		t.Fatalf("loaded region wasn't registered: %v", err)
+	}
+	if !r.HasCaseType(internal.CaseTypeFam1) {
+		t.Error("loaded region is missing its case type")
+	}
+
+	court, err := FindCourt("MX_TEST_GPE", internal.CaseTypeFam1)
+	if err != nil {
+		t.Fatalf("loaded region is missing its court: %v", err)
+	}
+	if court.Name != "Juzgado Primero Familiar" || court.Matter != MatterFamiliar {
+		t.Errorf("court catalog data wasn't loaded, got %+v", court)
+	}
+	if _, err := FindCourt("MX_TEST_GPE", internal.CaseTypeFam2); !errors.Is(err, ErrUnknownCourt) {
+		t.Errorf("FindCourt of a missing case type returned %v, want ErrUnknownCourt", err)
+	}
+}
+
+func TestHtmlTableSource(t *testing.T) {
+	def, err := ParseSource([]byte(`
+region: MX_TEST_HTML
+url: http://example.com/{date}/{caseType}.html
+dateFormat: "{YYYY}{MM}{DD}"
+format: html
+reader: html-table
+caseTypes:
+  - slug: civ2
+`), "html.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := def.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Latin-1 encoded, as many court sites still serve them
+	page := []byte("<html><body><table><tr><td>Lista del día</td></tr></table>" +
+		"<table><thead><tr><th>No.</th><th>Expediente</th><th>Juicio</th><th>S\xedntesis del acuerdo</th></tr></thead>" +
+		"<tbody><tr><td>1</td><td>84/2003<br>84/2003-I</td><td>Ordinario civil</td><td>Se admite la demanda</td></tr>" +
+		"<tr><td>2</td><td>Sin número</td><td></td><td>Aviso</td></tr>" +
+		"<tr><td>3</td><td>13/1998</td><td>Ejecutivo</td><td><p>Se fija</p><p>fecha de audiencia</p></td></tr></tbody></table></body></html>")
+	caseTable, err := readers.ReadDocument(r.Reader, &internal.Document{
+		Format:      internal.DocFormatHtml,
+		Data:        page,
+		ContentType: "text/html; charset=iso-8859-1",
+		CaseType:    internal.CaseTypeCiv2,
+	})
+	if err != nil {
+		t.Fatalf("reading the page errored with %v", err)
+	}
+
+	if len(caseTable.Cases) != 2 || len(caseTable.UnparsedCases) != 1 {
+		t.Fatalf("expected 2 cases and 1 unparsed row, got %d and %d", len(caseTable.Cases), len(caseTable.UnparsedCases))
+	}
+	row := caseTable.Find("84/2003-I")
+	if row == nil || row.Nature != "Ordinario civil" || row.Accord != "Se admite la demanda" {
+		t.Errorf("unexpected row for 84/2003-I: %+v", row)
+	}
+	if row := caseTable.Find("13/1998"); row == nil || row.Accord != "Se fija\nfecha de audiencia" {
+		t.Errorf("unexpected row for 13/1998: %+v", row)
+	}
+
+	_, err = readers.ReadDocument(r.Reader, &internal.Document{Format: internal.DocFormatPdf})
+	if !errors.Is(err, readers.ErrUnsupportedFormat) {
+		t.Errorf("expected a pdf to be rejected with ErrUnsupportedFormat, got %v", err)
+	}
+}
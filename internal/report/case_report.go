@@ -0,0 +1,222 @@
+// Package report renders printable PDF reports of cases
+package report
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal/db"
+	"github.com/vladwithcode/lex_app/internal/pdf"
+)
+
+var ErrNoCases = errors.New("report: no cases to render")
+
+const (
+	dateLayout     = "02/01/2006"
+	dateTimeLayout = "02/01/2006 15:04"
+)
+
+var partyRoleNames = map[db.PartyRole]string{
+	db.PartyRoleActor:     "Actor",
+	db.PartyRoleDemandado: "Demandado",
+	db.PartyRoleTercero:   "Tercero",
+}
+
+// Everything printed for a single case
+type CaseData struct {
+	Case         *db.LexCase
+	Participants *db.CaseParticipants
+	// Hearings from the day the report is loaded onwards
+	Hearings []*db.CaseHearing
+}
+
+// Loads a case with its full timeline, participants and upcoming hearings
+func LoadCase(ctx context.Context, appDb *sql.DB, id string) (*CaseData, error) {
+	c, err := db.FindCaseWithTimeline(ctx, appDb, id, -1)
+	if err != nil {
+		return nil, err
+	}
+	if c.Id == "" {
+		return nil, db.ErrCaseNotFound
+	}
+
+	participants, err := db.FindCaseParticipants(ctx, appDb, id)
+	if err != nil {
+		return nil, err
+	}
+
+	y, m, d := time.Now().Date()
+	hearings, err := db.FindUpcomingHearings(ctx, appDb, id, time.Date(y, m, d, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CaseData{Case: c, Participants: participants, Hearings: hearings}, nil
+}
+
+// Loads every tracked case linked to the client
+func LoadClientCases(ctx context.Context, appDb *sql.DB, clientId string) ([]*CaseData, error) {
+	opts := db.DefaultFindCaseOptions
+	opts.ClientId = clientId
+	opts.SortBy = db.CaseSortYear
+	opts.SortOrder = db.SortDesc
+
+	cases, err := db.FindFilteredCases(ctx, appDb, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]*CaseData, 0, len(cases))
+	for _, c := range cases {
+		cd, err := LoadCase(ctx, appDb, c.Id)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, cd)
+	}
+
+	return data, nil
+}
+
+// Renders the cases as a PDF, each one starting on a new page
+func WriteCaseReport(w io.Writer, cases []*CaseData, lh *Letterhead, generatedAt time.Time) error {
+	if len(cases) == 0 {
+		return ErrNoCases
+	}
+	if lh == nil {
+		lh = &DefaultLetterhead
+	}
+
+	l, err := newLayout(lh)
+	if err != nil {
+		return err
+	}
+
+	for _, cd := range cases {
+		l.newPage()
+		writeCase(l, cd)
+	}
+	l.footers(func(page, total int) string {
+		return fmt.Sprintf("Generado el %s · Página %d de %d", generatedAt.Format(dateTimeLayout), page, total)
+	})
+
+	_, err = l.doc.WriteTo(w)
+	return err
+}
+
+func writeCase(l *layout, cd *CaseData) {
+	c := cd.Case
+
+	l.title(fmt.Sprintf("Expediente %s · %s", c.CaseId, strings.ToUpper(c.CaseType)))
+	if c.Alias != "" {
+		l.paragraph(pdf.Helvetica, 12, c.Alias, 0, grayText)
+	}
+	l.space(6)
+
+	status := "Activo"
+	switch {
+	case c.DeletedAt != nil:
+		status = "En papelera"
+	case c.ArchivedAt != nil:
+		status = "Archivado el " + c.ArchivedAt.Format(dateLayout)
+	}
+	l.field("Naturaleza", c.Nature)
+	l.field("Año", c.CaseYear)
+	l.field("Estado", status)
+
+	otherIds := []string{}
+	for _, id := range c.OtherIds {
+		if id != c.CaseId {
+			otherIds = append(otherIds, id)
+		}
+	}
+	l.field("Otros números", strings.Join(otherIds, ", "))
+
+	tags := make([]string, len(c.Tags))
+	for i, t := range c.Tags {
+		tags[i] = t.Name
+	}
+	l.field("Etiquetas", strings.Join(tags, ", "))
+
+	if cd.Participants != nil {
+		clients := make([]string, len(cd.Participants.Clients))
+		for i, cc := range cd.Participants.Clients {
+			clients[i] = withRole(cc.Client.Name, cc.Role)
+		}
+		l.field("Clientes", strings.Join(clients, ", "))
+
+		parties := make([]string, len(cd.Participants.Parties))
+		for i, cp := range cd.Participants.Parties {
+			parties[i] = withRole(cp.Party.Name, cp.Role)
+		}
+		l.field("Partes", strings.Join(parties, ", "))
+	}
+
+	l.section("Próximas audiencias")
+	for _, h := range cd.Hearings {
+		l.entry(h.Date.Format(dateTimeLayout), h.Description)
+	}
+	if len(cd.Hearings) == 0 {
+		l.paragraph(pdf.Helvetica, 10, "Sin audiencias programadas.", 0, grayText)
+	}
+
+	l.section("Acuerdos")
+	accords := 0
+	for _, entry := range c.Timeline {
+		switch entry.Kind {
+		case db.TimelineAccord:
+			l.entry(entry.Date.Format(dateLayout), entry.Accord.Content)
+			accords++
+		case db.TimelineEvent:
+			l.entry(entry.Date.Format(dateLayout), eventDescription(entry.Event))
+		}
+	}
+	if accords == 0 {
+		l.paragraph(pdf.Helvetica, 10, "Sin acuerdos registrados.", 0, grayText)
+	}
+
+	notes := 0
+	for _, entry := range c.Timeline {
+		if entry.Kind != db.TimelineNote {
+			continue
+		}
+		if notes == 0 {
+			l.section("Notas")
+		}
+		l.entry(entry.Date.Format(dateLayout), entry.Note.Content)
+		notes++
+	}
+}
+
+func withRole(name string, role db.PartyRole) string {
+	if r, ok := partyRoleNames[role]; ok {
+		return fmt.Sprintf("%s (%s)", name, r)
+	}
+
+	return name
+}
+
+func eventDescription(event string) string {
+	switch event {
+	case db.TimelineEventArchived:
+		return "El caso fue archivado."
+	case db.TimelineEventDeleted:
+		return "El caso fue enviado a la papelera."
+	}
+
+	return event
+}
+
+func readLogo(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	return os.ReadFile(path)
+}
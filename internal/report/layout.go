@@ -0,0 +1,160 @@
+package report
+
+import (
+	"github.com/vladwithcode/lex_app/internal/pdf"
+)
+
+const (
+	margin       = 54.0
+	footerHeight = 24.0
+	logoHeight   = 42.0
+	fieldLabelW  = 110.0
+	entryDateW   = 72.0
+	lineSpacing  = 1.3
+)
+
+type rgb [3]float64
+
+var (
+	blackText = rgb{0.1, 0.1, 0.1}
+	grayText  = rgb{0.4, 0.4, 0.4}
+	accent    = rgb{0.12, 0.23, 0.4}
+)
+
+// Flows text down the pages of a document, starting a new page with
+// the letterhead whenever the current one is full
+type layout struct {
+	doc  *pdf.Document
+	lh   *Letterhead
+	logo *pdf.Image
+	y    float64
+}
+
+func newLayout(lh *Letterhead) (*layout, error) {
+	l := &layout{
+		doc: pdf.New(pdf.LetterWidth, pdf.LetterHeight),
+		lh:  lh,
+	}
+
+	logo, err := readLogo(lh.LogoPath)
+	if err != nil {
+		return nil, err
+	}
+	if logo != nil {
+		l.logo, err = l.doc.AddJPEG(logo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+func (l *layout) contentWidth() float64 {
+	return l.doc.Width() - 2*margin
+}
+
+func (l *layout) newPage() {
+	l.doc.AddPage()
+	l.y = margin
+	l.letterhead()
+}
+
+func (l *layout) letterhead() {
+	x := margin
+	top := l.y
+	if l.logo != nil {
+		w := logoHeight * float64(l.logo.Width) / float64(l.logo.Height)
+		l.doc.DrawImage(l.logo, x, top, w, logoHeight)
+		x += w + 12
+	}
+
+	l.doc.SetFont(pdf.HelveticaBold, 14)
+	l.doc.Text(x, top+14, l.lh.FirmName, accent[0], accent[1], accent[2])
+	y := top + 14
+	l.doc.SetFont(pdf.Helvetica, 8.5)
+	for _, line := range l.lh.Lines {
+		y += 11
+		l.doc.Text(x, y, line, grayText[0], grayText[1], grayText[2])
+	}
+
+	bottom := y + 8
+	if l.logo != nil && top+logoHeight+4 > bottom {
+		bottom = top + logoHeight + 4
+	}
+	l.doc.Line(margin, bottom, l.doc.Width()-margin, bottom, 0.8, 0.6)
+	l.y = bottom + 20
+}
+
+// Starts a new page unless there are h points left in the current one
+func (l *layout) ensure(h float64) {
+	if l.y+h > l.doc.Height()-margin-footerHeight {
+		l.newPage()
+	}
+}
+
+func (l *layout) space(h float64) {
+	l.y += h
+}
+
+func (l *layout) title(s string) {
+	l.wrapped(pdf.HelveticaBold, 16, s, margin, l.contentWidth(), accent)
+	l.space(2)
+}
+
+func (l *layout) section(s string) {
+	l.space(12)
+	l.ensure(40)
+	l.wrapped(pdf.HelveticaBold, 12, s, margin, l.contentWidth(), accent)
+	l.doc.Line(margin, l.y-6, l.doc.Width()-margin, l.y-6, 0.5, 0.8)
+	l.space(4)
+}
+
+func (l *layout) paragraph(font pdf.Font, size float64, s string, indent float64, color rgb) {
+	l.wrapped(font, size, s, margin+indent, l.contentWidth()-indent, color)
+}
+
+// Writes a label and its value side by side, skipped if value is empty
+func (l *layout) field(label, value string) {
+	if value == "" {
+		return
+	}
+
+	l.ensure(14)
+	l.doc.SetFont(pdf.HelveticaBold, 10)
+	l.doc.Text(margin, l.y+10, label, grayText[0], grayText[1], grayText[2])
+	l.wrapped(pdf.Helvetica, 10, value, margin+fieldLabelW, l.contentWidth()-fieldLabelW, blackText)
+}
+
+// Writes a dated entry with its text beside the date
+func (l *layout) entry(date, text string) {
+	l.ensure(14)
+	l.doc.SetFont(pdf.HelveticaBold, 10)
+	l.doc.Text(margin, l.y+10, date, blackText[0], blackText[1], blackText[2])
+	l.wrapped(pdf.Helvetica, 10, text, margin+entryDateW, l.contentWidth()-entryDateW, blackText)
+	l.space(6)
+}
+
+// Writes s wrapped to width, breaking pages between lines as needed
+func (l *layout) wrapped(font pdf.Font, size float64, s string, x, width float64, color rgb) {
+	lineH := size * lineSpacing
+	l.doc.SetFont(font, size)
+	for _, line := range pdf.WrapText(font, size, s, width) {
+		l.ensure(lineH)
+		l.doc.SetFont(font, size)
+		l.doc.Text(x, l.y+size, line, color[0], color[1], color[2])
+		l.y += lineH
+	}
+}
+
+// Writes the text returned by fn at the bottom of every page
+func (l *layout) footers(fn func(page, total int) string) {
+	total := l.doc.PageCount()
+	for i := 0; i < total; i++ {
+		l.doc.SetPage(i)
+		l.doc.SetFont(pdf.Helvetica, 8)
+		text := fn(i+1, total)
+		x := (l.doc.Width() - l.doc.StringWidth(text)) / 2
+		l.doc.Text(x, l.doc.Height()-margin+footerHeight/2, text, grayText[0], grayText[1], grayText[2])
+	}
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/vladwithcode/lex_app/internal"
+)
+
+// Name of the letterhead file inside the app data dir
+const LetterheadFile = "letterhead.json"
+
+// Firm details printed at the top of every page of a report
+type Letterhead struct {
+	FirmName string `json:"firmName"`
+	// Address, phone numbers and any other line under the firm name
+	Lines []string `json:"lines"`
+	// JPEG logo, relative paths are resolved from the app data dir
+	LogoPath string `json:"logoPath"`
+}
+
+var DefaultLetterhead = Letterhead{
+	FirmName: "lexApp",
+	Lines:    []string{},
+}
+
+// Reads the letterhead from the app data dir.
+//
+// Returns DefaultLetterhead if the file does not exist
+func LoadLetterhead() (*Letterhead, error) {
+	dir, err := internal.GetAppDataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, LetterheadFile))
+	if errors.Is(err, os.ErrNotExist) {
+		lh := DefaultLetterhead
+		return &lh, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lh := &Letterhead{}
+	if err := json.Unmarshal(data, lh); err != nil {
+		return nil, err
+	}
+	if lh.LogoPath != "" && !filepath.IsAbs(lh.LogoPath) {
+		lh.LogoPath = filepath.Join(dir, lh.LogoPath)
+	}
+
+	return lh, nil
+}
@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EventHeader     = "X-Lex-Event"
+	DeliveryHeader  = "X-Lex-Delivery"
+	SignatureHeader = "X-Lex-Signature"
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook signature doesn't match the body")
+	ErrMalformedHeader  = errors.New("webhook signature header should be formatted as 't=<timestamp>,v1=<signature>'")
+	ErrExpiredSignature = errors.New("webhook signature is too old")
+)
+
+// Returns the value of the SignatureHeader for body sent at t
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, signature(secret, ts, body))
+}
+
+// Checks the SignatureHeader of a delivery against its body. Signatures
+// older than tolerance are rejected, a tolerance <= 0 accepts any age
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrMalformedHeader
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 && time.Since(time.Unix(unix, 0)) > tolerance {
+		return ErrExpiredSignature
+	}
+
+	return nil
+}
+
+func signature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,280 @@
+// Package webhooks notifies other systems of case events through signed
+// HTTP requests.
+//
+// Every delivery is a POST with a JSON Payload as body and the headers:
+//
+//	X-Lex-Event: the name of the event
+//	X-Lex-Delivery: the id of the delivery, the same across its retries
+//	X-Lex-Signature: t=<unix timestamp>,v1=<signature>
+//
+// The signature is the hex encoded HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with the secret of the webhook, see Verify
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+	// Bytes of the response body kept as the error of a failed attempt
+	maxErrorBody = 256
+)
+
+// Wait before each retry of a failed delivery. A delivery is attempted
+// once more than the number of delays before being marked as failed
+var RetryDelays = []time.Duration{
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	time.Hour,
+}
+
+var (
+	client = &http.Client{Timeout: requestTimeout}
+	// Deliveries being sent by this process, see Flush
+	inFlight sync.WaitGroup
+)
+
+// Body of every delivery
+type Payload struct {
+	// Id of the event, shared by the deliveries of every webhook
+	Id        string    `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// Sends the event to every enabled webhook subscribed to it.
+//
+// The deliveries are logged and sent in the background, failed ones are
+// retried after each of RetryDelays
+func Emit(ctx context.Context, appDb *sql.DB, event string, data any) error {
+	// Deliveries outlive the request or task that emitted the event
+	ctx = context.WithoutCancel(ctx)
+
+	webhooks, err := db.FindWebhooksForEvent(ctx, appDb, event)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, err := newPayload(event, data)
+	if err != nil {
+		return err
+	}
+
+	for _, wh := range webhooks {
+		d, err := db.NewWebhookDelivery(wh.Id, event, payload)
+		if err != nil {
+			return err
+		}
+		if err := db.InsertWebhookDelivery(ctx, appDb, d); err != nil {
+			return err
+		}
+
+		inFlight.Add(1)
+		go func() {
+			defer inFlight.Done()
+			deliver(ctx, appDb, d)
+		}()
+	}
+
+	return nil
+}
+
+// Sends a webhook.test event to the webhook right away, without retries.
+//
+// The delivery is logged like any other and returned with its outcome
+func SendTest(ctx context.Context, appDb *sql.DB, webhookId string) (*db.WebhookDelivery, error) {
+	wh, err := db.FindWebhookById(ctx, appDb, webhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := newPayload(db.WebhookEventTest, map[string]string{
+		"webhookId": wh.Id,
+		"message":   "Test delivery from lexApp",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := db.NewWebhookDelivery(wh.Id, db.WebhookEventTest, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt(ctx, wh, d)
+	if d.Status == db.WebhookDeliveryPending {
+		d.Status = db.WebhookDeliveryFailed
+		d.NextAttemptAt = nil
+	}
+	if err := db.InsertWebhookDelivery(ctx, appDb, d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Restarts the deliveries left pending by a previous run, e.g. when the
+// app was closed while waiting to retry them
+func ResumePending(ctx context.Context, appDb *sql.DB) error {
+	ctx = context.WithoutCancel(ctx)
+
+	deliveries, err := db.FindPendingWebhookDeliveries(ctx, appDb)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deliveries {
+		inFlight.Add(1)
+		go func(d *db.WebhookDelivery) {
+			defer inFlight.Done()
+			if d.NextAttemptAt != nil {
+				time.Sleep(time.Until(*d.NextAttemptAt))
+			}
+			deliver(ctx, appDb, d)
+		}(d)
+	}
+
+	return nil
+}
+
+// Waits for the deliveries sent by this process until ctx is done.
+//
+// Deliveries still waiting for a retry stay pending and are resumed by
+// the next ResumePending
+func Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newPayload(event string, data any) (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", fmt.Errorf("%w\n\t%w", db.ErrGenUUID, err)
+	}
+
+	body, err := json.Marshal(&Payload{
+		Id:        id.String(),
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+
+	return string(body), err
+}
+
+// Attempts the delivery until it succeeds or runs out of retries,
+// logging the outcome of each attempt.
+//
+// The webhook is read again before each attempt, so edits to it apply
+// to the retries. Deleting it removes its deliveries, which stops them
+func deliver(ctx context.Context, appDb *sql.DB, d *db.WebhookDelivery) {
+	for {
+		wh, err := db.FindWebhookById(ctx, appDb, d.WebhookId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return
+		}
+		if err != nil {
+			// Left pending for the next ResumePending
+			fmt.Printf("FindWebhookById Err: %v\n", err)
+			return
+		}
+
+		if !wh.Enabled {
+			d.Status = db.WebhookDeliveryFailed
+			d.NextAttemptAt = nil
+			d.Error = "the webhook was disabled"
+		} else {
+			attempt(ctx, wh, d)
+		}
+
+		if err := db.UpdateWebhookDelivery(ctx, appDb, d); err != nil {
+			fmt.Printf("UpdateWebhookDelivery Err: %v\n", err)
+		}
+		if d.Status != db.WebhookDeliveryPending {
+			return
+		}
+
+		time.Sleep(time.Until(*d.NextAttemptAt))
+	}
+}
+
+// Sends the delivery once, setting its status from the response.
+// Failed attempts with retries left keep the delivery pending
+func attempt(ctx context.Context, wh *db.Webhook, d *db.WebhookDelivery) {
+	d.Attempts++
+	d.ResponseStatus = 0
+
+	err := send(ctx, wh, d)
+	if err == nil {
+		now := time.Now()
+		d.Status = db.WebhookDeliveryDelivered
+		d.DeliveredAt = &now
+		d.NextAttemptAt = nil
+		d.Error = ""
+		return
+	}
+
+	d.Error = err.Error()
+	if d.Attempts > len(RetryDelays) {
+		d.Status = db.WebhookDeliveryFailed
+		d.NextAttemptAt = nil
+		return
+	}
+
+	next := time.Now().Add(RetryDelays[d.Attempts-1])
+	d.Status = db.WebhookDeliveryPending
+	d.NextAttemptAt = &next
+}
+
+func send(ctx context.Context, wh *db.Webhook, d *db.WebhookDelivery) error {
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lexApp-Webhooks")
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, d.Id)
+	req.Header.Set(SignatureHeader, Sign(wh.Secret, time.Now(), body))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	d.ResponseStatus = res.StatusCode
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBody))
+	return fmt.Errorf("%s: %s", res.Status, bytes.TrimSpace(msg))
+}
@@ -0,0 +1,73 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vladwithcode/lex_app/internal/db"
+)
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"event":"case.created"}`)
+	header := Sign("secret", time.Now(), body)
+
+	if err := Verify("secret", header, body, time.Minute); err != nil {
+		t.Errorf("Expected the signature to be valid, got %v", err)
+	}
+	if err := Verify("other", header, body, time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature with another secret, got %v", err)
+	}
+	if err := Verify("secret", header, []byte(`{}`), time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for another body, got %v", err)
+	}
+	if err := Verify("secret", "v1=abc", body, time.Minute); !errors.Is(err, ErrMalformedHeader) {
+		t.Errorf("Expected ErrMalformedHeader, got %v", err)
+	}
+
+	old := Sign("secret", time.Now().Add(-time.Hour), body)
+	if err := Verify("secret", old, body, time.Minute); !errors.Is(err, ErrExpiredSignature) {
+		t.Errorf("Expected ErrExpiredSignature, got %v", err)
+	}
+}
+
+func TestAttempt(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify("secret", r.Header.Get(SignatureHeader), body, time.Minute); err != nil {
+			t.Errorf("Delivery with invalid signature: %v", err)
+		}
+		if r.Header.Get(EventHeader) != db.WebhookEventCaseCreated {
+			t.Errorf("Expected the %s header to be set, got %q", EventHeader, r.Header.Get(EventHeader))
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	wh := &db.Webhook{Url: srv.URL, Secret: "secret", Enabled: true}
+	d := &db.WebhookDelivery{Id: "1", Event: db.WebhookEventCaseCreated, Payload: `{}`}
+
+	attempt(context.Background(), wh, d)
+	if d.Status != db.WebhookDeliveryDelivered || d.DeliveredAt == nil || d.ResponseStatus != http.StatusOK {
+		t.Errorf("Expected the delivery to succeed, got %+v", d)
+	}
+
+	status = http.StatusInternalServerError
+	d = &db.WebhookDelivery{Id: "2", Event: db.WebhookEventCaseCreated, Payload: `{}`}
+	for i := 0; i < len(RetryDelays); i++ {
+		attempt(context.Background(), wh, d)
+		if d.Status != db.WebhookDeliveryPending || d.NextAttemptAt == nil {
+			t.Fatalf("Expected attempt %d to be retried, got %+v", d.Attempts, d)
+		}
+	}
+
+	attempt(context.Background(), wh, d)
+	if d.Status != db.WebhookDeliveryFailed || d.NextAttemptAt != nil || d.ResponseStatus != status {
+		t.Errorf("Expected the delivery to fail after %d attempts, got %+v", d.Attempts, d)
+	}
+}
@@ -33,6 +33,16 @@ func main() {
 	app := NewApp()
 	caseCtl := controllers.NewCaseControler()
 	accUpdtrCtl := controllers.NewAccordUpdaterCtl()
+	tagCtl := controllers.NewTagController()
+	clientCtl := controllers.NewClientController()
+	savedSearchCtl := controllers.NewSavedSearchController()
+	statsCtl := controllers.NewStatsController()
+	importCtl := controllers.NewImportController()
+	apiCtl := controllers.NewApiController()
+	webhookCtl := controllers.NewWebhookController()
+	courtCtl := controllers.NewCourtController()
+	watchlistCtl := controllers.NewWatchlistController()
+	backfillCtl := controllers.NewBackfillController()
 
 	// Create application with options
 	err = wails.Run(&options.App{
@@ -49,11 +59,34 @@ func main() {
 			app.startup(ctx, db)
 			caseCtl.Startup(ctx, db)
 			accUpdtrCtl.Startup(ctx, db)
+			tagCtl.Startup(ctx, db)
+			clientCtl.Startup(ctx, db)
+			savedSearchCtl.Startup(ctx, db)
+			statsCtl.Startup(ctx, db)
+			importCtl.Startup(ctx, db)
+			apiCtl.Startup(ctx, db)
+			webhookCtl.Startup(ctx, db)
+			courtCtl.Startup(ctx, db)
+			watchlistCtl.Startup(ctx, db)
+			backfillCtl.Startup(ctx, db)
+		},
+		OnShutdown: func(ctx context.Context) {
+			apiCtl.Shutdown(ctx)
 		},
 		Bind: []interface{}{
 			app,
 			caseCtl,
 			accUpdtrCtl,
+			tagCtl,
+			clientCtl,
+			savedSearchCtl,
+			statsCtl,
+			importCtl,
+			apiCtl,
+			webhookCtl,
+			courtCtl,
+			watchlistCtl,
+			backfillCtl,
 		},
 		EnumBind: []interface{}{
 			internal.AllRegions,